@@ -0,0 +1,185 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package anymap provides Map type which is an ordered map implemented without
+// generics, in the way of designs before Go 1.18: keys and values are held as
+// interface{} and key types are checked with reflection at run time.
+//
+// This package exists only to quantify the benefit of generics by comparing
+// it with the generic implementations in this repository.
+package anymap
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Map is a struct which represents a map which preserves the order of key
+// insertions and holds keys and values as interface{}.
+type Map struct {
+	m    map[interface{}](*Entry)
+	head *Entry
+	last *Entry
+	len  int
+}
+
+// Entry is a struct which is a map element and holds a pair of key and value.
+type Entry struct {
+	key   interface{}
+	value interface{}
+	prev  *Entry
+	next  *Entry
+}
+
+// UncomparableKeyError is an error type which reports that a key cannot be
+// used as a map key because its type is not comparable.
+type UncomparableKeyError struct {
+	Type reflect.Type
+}
+
+func (err UncomparableKeyError) Error() string {
+	return "anymap: uncomparable key type: " + err.Type.String()
+}
+
+// New is a function which creates a new ordered map, which is empty.
+func New() Map {
+	return Map{m: make(map[interface{}](*Entry))}
+}
+
+func checkKey(key interface{}) {
+	t := reflect.TypeOf(key)
+	if t != nil && !t.Comparable() {
+		panic(UncomparableKeyError{Type: t})
+	}
+}
+
+// Len is a method which returns the number of entries in this map.
+func (om *Map) Len() int {
+	return om.len
+}
+
+// Store is a method which sets a value for a key.
+// This method panics with UncomparableKeyError if the type of the key is not
+// comparable.
+func (om *Map) Store(key, value interface{}) {
+	checkKey(key)
+
+	ent, exists := om.m[key]
+	if exists {
+		ent.value = value
+		return
+	}
+
+	ent = &Entry{key: key, value: value}
+	om.m[key] = ent
+
+	if om.last == nil {
+		om.head = ent
+	} else {
+		ent.prev = om.last
+		om.last.next = ent
+	}
+	om.last = ent
+	om.len++
+}
+
+// Load is a method which returns a value stored in this map for a key.
+// If no value was found for a key, the ok result is false.
+func (om *Map) Load(key interface{}) (value interface{}, ok bool) {
+	checkKey(key)
+
+	ent, exists := om.m[key]
+	if exists {
+		value = ent.value
+		ok = true
+	}
+	return
+}
+
+// Delete is a method which deletes a value for a key.
+func (om *Map) Delete(key interface{}) {
+	checkKey(key)
+
+	ent, exists := om.m[key]
+	if !exists {
+		return
+	}
+
+	delete(om.m, key)
+
+	if ent.prev != nil {
+		ent.prev.next = ent.next
+	} else {
+		om.head = ent.next
+	}
+
+	if ent.next != nil {
+		ent.next.prev = ent.prev
+	} else {
+		om.last = ent.prev
+	}
+
+	ent.next = nil
+	ent.prev = nil
+	om.len--
+}
+
+// Range is a method which calls the specified function: fn sequentially for
+// each key and value in this map.
+// If fn returns false, this method stops the iteration.
+func (om *Map) Range(fn func(key, value interface{}) bool) {
+	for ent := om.head; ent != nil; ent = ent.next {
+		if !fn(ent.key, ent.value) {
+			break
+		}
+	}
+}
+
+// Front is a method which returns the head entry of this map.
+func (om *Map) Front() *Entry {
+	return om.head
+}
+
+// Back is a method which returns the last entry of this map.
+func (om *Map) Back() *Entry {
+	return om.last
+}
+
+// String is a method which returns a string of the content of this map.
+func (om Map) String() string {
+	var buf strings.Builder
+	buf.WriteString("Map[")
+	ent := om.Front()
+	if ent != nil {
+		buf.WriteString(fmt.Sprintf("%v:%v", ent.Key(), ent.Value()))
+		for ent = ent.Next(); ent != nil; ent = ent.Next() {
+			buf.WriteString(fmt.Sprintf(" %v:%v", ent.Key(), ent.Value()))
+		}
+	}
+	buf.WriteString("]")
+	return buf.String()
+}
+
+// Prev is a method which returns the previous entry of this entry.
+// If this entry is a head entry of an ordered map, the returned value is nil.
+func (ent *Entry) Prev() *Entry {
+	return ent.prev
+}
+
+// Next is a method which returns the next entry of this entry.
+// If this entry is a last entry of an ordered map, the returned value is nil.
+func (ent *Entry) Next() *Entry {
+	return ent.next
+}
+
+// Key is a method which returns the key of this entry.
+func (ent *Entry) Key() interface{} {
+	return ent.key
+}
+
+// Value is a method which returns the value of this entry.
+func (ent *Entry) Value() interface{} {
+	return ent.value
+}
@@ -0,0 +1,199 @@
+package anymap_test
+
+import (
+	"testing"
+
+	"github.com/sttk/benchmarks_orderedmap/anymap"
+	orderedmap "github.com/sttk/benchmarks_orderedmap/v1_0_0"
+)
+
+type Foo struct {
+	Bar string
+	Baz int
+}
+
+func BenchmarkAnyMap_Store_newFiveEntries(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		om := anymap.New()
+		om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+		om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+		om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+		om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+		om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+	}
+}
+
+func BenchmarkOrderedMap_Store_newFiveEntries(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		om := orderedmap.New[string, Foo]()
+		om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+		om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+		om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+		om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+		om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+	}
+}
+
+func BenchmarkAnyMap_Store_rewriteFiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := anymap.New()
+	om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+	om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+	om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+	om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+	om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+		om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+		om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+		om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+		om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+	}
+}
+
+func BenchmarkOrderedMap_Store_rewriteFiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[string, Foo]()
+	om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+	om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+	om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+	om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+	om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+		om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+		om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+		om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+		om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+	}
+}
+
+func BenchmarkAnyMap_Load_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := anymap.New()
+	om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+	om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+	om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+	om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+	om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		v0, exists := om.Load("foo-0")
+		v1, exists := om.Load("foo-1")
+		v2, exists := om.Load("foo-2")
+		v3, exists := om.Load("foo-3")
+		v4, exists := om.Load("foo-4")
+		_ = v0.(Foo)
+		_ = v1.(Foo)
+		_ = v2.(Foo)
+		_ = v3.(Foo)
+		_ = v4.(Foo)
+		_ = exists
+	}
+}
+
+func BenchmarkOrderedMap_Load_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[string, Foo]()
+	om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+	om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+	om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+	om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+	om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		v0, exists := om.Load("foo-0")
+		v1, exists := om.Load("foo-1")
+		v2, exists := om.Load("foo-2")
+		v3, exists := om.Load("foo-3")
+		v4, exists := om.Load("foo-4")
+		_ = v0
+		_ = v1
+		_ = v2
+		_ = v3
+		_ = v4
+		_ = exists
+	}
+}
+
+func BenchmarkAnyMap_Delete_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := anymap.New()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+		om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+		om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+		om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+		om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+		om.Delete("foo-0")
+		om.Delete("foo-1")
+		om.Delete("foo-2")
+		om.Delete("foo-3")
+		om.Delete("foo-4")
+	}
+}
+
+func BenchmarkOrderedMap_Delete_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[string, Foo]()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+		om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+		om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+		om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+		om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+		om.Delete("foo-0")
+		om.Delete("foo-1")
+		om.Delete("foo-2")
+		om.Delete("foo-3")
+		om.Delete("foo-4")
+	}
+}
+
+func BenchmarkAnyMap_IterateWithRange_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := anymap.New()
+	om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+	om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+	om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+	om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+	om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Range(func(k, v interface{}) bool {
+			_ = k.(string)
+			_ = v.(Foo)
+			return true
+		})
+	}
+}
+
+func BenchmarkOrderedMap_IterateWithRange_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[string, Foo]()
+	om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+	om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+	om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+	om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+	om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Range(func(k string, v Foo) bool {
+			_ = k
+			_ = v
+			return true
+		})
+	}
+}
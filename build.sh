@@ -43,6 +43,13 @@ cover() {
   errcheck $?
 }
 
+golden() {
+  for arch in amd64 386; do
+    GOARCH=$arch go test -run '_golden$' ./extended
+    errcheck $?
+  done
+}
+
 bench() {
   local dir=$1
   if [[ "$dir" == "" ]]; then
@@ -54,6 +61,30 @@ bench() {
   popd
 }
 
+profile() {
+  local dir=$1
+  local pattern=$2
+  if [[ "$dir" == "" ]]; then
+    dir="."
+  fi
+  if [[ "$pattern" == "" ]]; then
+    pattern="."
+  fi
+  local outdir="$(pwd)/profiles/$(basename $(cd $dir && pwd))"
+  mkdir -p $outdir
+  errcheck $?
+  pushd $dir
+  for name in $(go test -list "$pattern" | grep '^Benchmark'); do
+    go test -run '^$' -bench "^${name}\$" --benchmem \
+      -o $outdir/bench.test \
+      -cpuprofile $outdir/${name}.cpu.pprof \
+      -memprofile $outdir/${name}.mem.pprof
+    errcheck $?
+  done
+  rm -f $outdir/bench.test
+  popd
+}
+
 if [[ "$#" == "0" ]]; then
   clean
   format
@@ -67,6 +98,9 @@ elif [[ "$1" == "unit" ]]; then
 elif [[ "$1" == "bench" ]]; then
   bench $2
 
+elif [[ "$1" == "profile" ]]; then
+  profile $2 $3
+
 else
   for a in "$@"; do
     case "$a" in
@@ -85,6 +119,9 @@ else
     cover)
       cover
       ;;
+    golden)
+      golden
+      ;;
     '')
       compile
       ;;
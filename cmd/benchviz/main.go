@@ -0,0 +1,146 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Command benchviz renders results of `go test -bench` into a static HTML
+// page which has a bar chart per operation.
+//
+// # Usage
+//
+// The inputs are files which contain outputs of `go test -bench`. The
+// benchmark.md files in this repository can be passed as they are:
+//
+//	go run ./cmd/benchviz -o report.html v1_0_0/benchmark.md v0_6_0/benchmark_json.md
+//	go test -bench . --benchmem ./v1_0_0 | go run ./cmd/benchviz > report.html
+//
+// Benchmark names are split at the first "_" into an implementation name and
+// an operation name, e.g. BenchmarkOmW_MarshalJSON_empty is the operation
+// "MarshalJSON_empty" of the implementation "OmW". Results of the same
+// operation in the same package are drawn in one chart.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Result is a struct which holds the measured values of a benchmark case.
+type Result struct {
+	Impl    string
+	Op      string
+	Metrics map[string]float64
+}
+
+// Group is a struct which holds results of the same operation in the same
+// package.
+type Group struct {
+	Pkg     string
+	Op      string
+	Results []Result
+}
+
+var (
+	reBenchLine = regexp.MustCompile(`^Benchmark(\S+?)(-\d+)?\s+(\d+)\s+(.*)$`)
+	reMetric    = regexp.MustCompile(`([-0-9.e+]+)\s+(\S+)`)
+)
+
+func main() {
+	output := flag.String("o", "", "output file (default: stdout)")
+	title := flag.String("title", "Benchmarks of orderedmap", "title of the report")
+	flag.Parse()
+
+	var groups []*Group
+	var err error
+	if flag.NArg() == 0 {
+		groups, err = parse(os.Stdin, groups)
+	} else {
+		for _, path := range flag.Args() {
+			groups, err = parseFile(path, groups)
+			if err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	err = render(w, *title, groups)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func parseFile(path string, groups []*Group) ([]*Group, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return groups, err
+	}
+	defer f.Close()
+	return parse(f, groups)
+}
+
+func parse(r io.Reader, groups []*Group) ([]*Group, error) {
+	index := make(map[string]*Group, len(groups))
+	for _, g := range groups {
+		index[g.Pkg+"\x00"+g.Op] = g
+	}
+
+	pkg := ""
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "pkg:") {
+			pkg = strings.TrimSpace(strings.TrimPrefix(line, "pkg:"))
+			continue
+		}
+
+		m := reBenchLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		impl, op, found := strings.Cut(m[1], "_")
+		if !found {
+			op = impl
+		}
+
+		res := Result{Impl: impl, Op: op, Metrics: make(map[string]float64)}
+		for _, mm := range reMetric.FindAllStringSubmatch(m[4], -1) {
+			v, err := strconv.ParseFloat(mm[1], 64)
+			if err != nil {
+				continue
+			}
+			res.Metrics[mm[2]] = v
+		}
+
+		key := pkg + "\x00" + op
+		g, exists := index[key]
+		if !exists {
+			g = &Group{Pkg: pkg, Op: op}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.Results = append(g.Results, res)
+	}
+	return groups, sc.Err()
+}
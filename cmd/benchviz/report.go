@@ -0,0 +1,140 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package main
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+)
+
+var metricNames = []string{"ns/op", "B/op", "allocs/op"}
+
+var colors = []string{
+	"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948",
+	"#b07aa1", "#ff9da7", "#9c755f", "#bab0ac",
+}
+
+const (
+	labelWidth   = 160
+	barAreaWidth = 400
+	barHeight    = 18
+	barGap       = 4
+)
+
+type bar struct {
+	Label string
+	Value string
+	Y     int
+	Width int
+	Color string
+}
+
+type chart struct {
+	Metric string
+	Height int
+	Bars   []bar
+}
+
+type section struct {
+	Pkg    string
+	Op     string
+	Charts []chart
+}
+
+func render(w io.Writer, title string, groups []*Group) error {
+	implColors := make(map[string]string)
+
+	sections := make([]section, 0, len(groups))
+	for _, g := range groups {
+		sec := section{Pkg: g.Pkg, Op: g.Op}
+		for _, metric := range metricNames {
+			max := 0.0
+			found := false
+			for _, res := range g.Results {
+				v, ok := res.Metrics[metric]
+				if !ok {
+					continue
+				}
+				found = true
+				if v > max {
+					max = v
+				}
+			}
+			if !found {
+				continue
+			}
+
+			ch := chart{Metric: metric}
+			for i, res := range g.Results {
+				v := res.Metrics[metric]
+				color, exists := implColors[res.Impl]
+				if !exists {
+					color = colors[len(implColors)%len(colors)]
+					implColors[res.Impl] = color
+				}
+				width := 0
+				if max > 0 {
+					width = int(v / max * barAreaWidth)
+				}
+				ch.Bars = append(ch.Bars, bar{
+					Label: res.Impl,
+					Value: strconv.FormatFloat(v, 'f', -1, 64),
+					Y:     i * (barHeight + barGap),
+					Width: width,
+					Color: color,
+				})
+			}
+			ch.Height = len(ch.Bars) * (barHeight + barGap)
+			sec.Charts = append(sec.Charts, ch)
+		}
+		sections = append(sections, sec)
+	}
+
+	return reportTemplate.Execute(w, map[string]any{
+		"Title":      title,
+		"Sections":   sections,
+		"LabelWidth": labelWidth,
+		"ChartWidth": labelWidth + barAreaWidth + 120,
+		"BarHeight":  barHeight,
+	})
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+section { margin-bottom: 2em; }
+h2 { font-size: 1.1em; margin-bottom: 0.2em; }
+.pkg { color: #666; font-size: 0.85em; }
+.metric { font-size: 0.85em; margin: 0.6em 0 0.2em; }
+svg text { font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- $lw := .LabelWidth }}{{ $cw := .ChartWidth }}{{ $bh := .BarHeight }}
+{{- range .Sections}}
+<section>
+<h2>{{.Op}}</h2>
+<div class="pkg">{{.Pkg}}</div>
+{{- range .Charts}}
+<div class="metric">{{.Metric}}</div>
+<svg width="{{$cw}}" height="{{.Height}}">
+{{- range .Bars}}
+<text x="0" y="{{.Y}}" dy="13">{{.Label}}</text>
+<rect x="{{$lw}}" y="{{.Y}}" width="{{.Width}}" height="{{$bh}}" fill="{{.Color}}"></rect>
+<text x="{{$lw}}" y="{{.Y}}" dx="{{.Width}}" dy="13">&nbsp;{{.Value}}</text>
+{{- end}}
+</svg>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
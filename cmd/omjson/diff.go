@@ -0,0 +1,81 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// writeDiff is a function which writes differences of two values, one per
+// line: "- path: value" for removed, "+ path: value" for added, and
+// "~ path: old -> new" for changed values. Object keys are compared in the
+// order of the first document, then the keys only in the second document.
+func writeDiff(w io.Writer, path string, a, b any) error {
+	oa, aok := a.(object)
+	ob, bok := b.(object)
+	if aok && bok {
+		var err error
+		oa.Range(func(key string, va any) bool {
+			p := joinPath(path, key)
+			vb, ok := ob.Load(key)
+			if !ok {
+				_, err = fmt.Fprintf(w, "- %s: %s\n", p, compact(va))
+			} else {
+				err = writeDiff(w, p, va, vb)
+			}
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+		ob.Range(func(key string, vb any) bool {
+			if _, ok := oa.Load(key); !ok {
+				_, err = fmt.Fprintf(w, "+ %s: %s\n", joinPath(path, key), compact(vb))
+			}
+			return err == nil
+		})
+		return err
+	}
+
+	sa, aok := a.([]any)
+	sb, bok := b.([]any)
+	if aok && bok {
+		for i := 0; i < len(sa) || i < len(sb); i++ {
+			p := joinPath(path, strconv.Itoa(i))
+			var err error
+			switch {
+			case i >= len(sb):
+				_, err = fmt.Fprintf(w, "- %s: %s\n", p, compact(sa[i]))
+			case i >= len(sa):
+				_, err = fmt.Fprintf(w, "+ %s: %s\n", p, compact(sb[i]))
+			default:
+				err = writeDiff(w, p, sa[i], sb[i])
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ca, cb := compact(a), compact(b)
+	if ca == cb {
+		return nil
+	}
+	if path == "" {
+		path = "."
+	}
+	_, err := fmt.Fprintf(w, "~ %s: %s -> %s\n", path, ca, cb)
+	return err
+}
+
+func joinPath(path, seg string) string {
+	if path == "" {
+		return seg
+	}
+	return path + "." + seg
+}
@@ -0,0 +1,154 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Command omjson transforms JSON documents preserving the order of object
+// keys, using ordered maps of this repository.
+//
+// # Usage
+//
+//	omjson pretty [-indent n] [file]   pretty-prints a document
+//	omjson sort [-indent n] [file]     sorts object keys at every depth
+//	omjson get path [file]             extracts a value at a path, e.g. a.b.0
+//	omjson diff file1 file2            prints differences of two documents
+//	omjson yaml [file]                 converts a document to YAML
+//	omjson table [-markdown] [file]    prints an object as a table of keys and values
+//
+// If file is omitted, the document is read from stdin.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+const usage = `usage:
+  omjson pretty [-indent n] [file]
+  omjson sort [-indent n] [file]
+  omjson get path [file]
+  omjson diff file1 file2
+  omjson yaml [file]
+  omjson table [-markdown] [file]
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	err := run(w, os.Args[1], os.Args[2:])
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "omjson:", err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, cmd string, args []string) error {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	indent := fs.Int("indent", 2, "number of spaces for indentation")
+	markdown := fs.Bool("markdown", false, "print a table in Markdown")
+	fs.Parse(args)
+	args = fs.Args()
+
+	switch cmd {
+	case "pretty", "sort":
+		v, err := readFile(arg(args, 0))
+		if err != nil {
+			return err
+		}
+		if cmd == "sort" {
+			v = sortKeys(v)
+		}
+		return writeJSON(w, v, *indent)
+
+	case "get":
+		if len(args) < 1 {
+			return fmt.Errorf("get needs a path")
+		}
+		v, err := readFile(arg(args, 1))
+		if err != nil {
+			return err
+		}
+		v, err = extract(v, args[0])
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, v, *indent)
+
+	case "diff":
+		if len(args) < 2 {
+			return fmt.Errorf("diff needs two files")
+		}
+		a, err := readFile(args[0])
+		if err != nil {
+			return err
+		}
+		b, err := readFile(args[1])
+		if err != nil {
+			return err
+		}
+		return writeDiff(w, "", a, b)
+
+	case "yaml":
+		v, err := readFile(arg(args, 0))
+		if err != nil {
+			return err
+		}
+		return writeYAML(w, v)
+
+	case "table":
+		v, err := readFile(arg(args, 0))
+		if err != nil {
+			return err
+		}
+		om, ok := v.(object)
+		if !ok {
+			return fmt.Errorf("table needs an object")
+		}
+		return om.WriteTable(w, orderedmap.TableOptions{
+			Markdown: *markdown,
+			Format:   tableCell,
+		})
+
+	default:
+		return fmt.Errorf("unknown command: %s\n%s", cmd, usage)
+	}
+}
+
+// tableCell is a function which returns strings as they are, and other values
+// in compact JSON.
+func tableCell(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return compact(v)
+}
+
+func arg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+func readFile(path string) (any, error) {
+	if path == "" || path == "-" {
+		return decode(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return decode(f)
+}
@@ -0,0 +1,234 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+// object is the type of JSON objects in documents. Objects are held in
+// ordered maps at every depth, and numbers are held as json.Number to be
+// written as they were read.
+type object = *orderedmap.Map[string, any]
+
+func decode(r io.Reader) (any, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	v, err := decodeValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid data after the top-level value")
+	}
+	return v, nil
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		om := orderedmap.New[string, any]()
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			om.Store(tok.(string), v)
+		}
+		_, err = dec.Token()
+		return &om, err
+	default:
+		arr := []any{}
+		for dec.More() {
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		_, err = dec.Token()
+		return arr, err
+	}
+}
+
+func writeJSON(w io.Writer, v any, indent int) error {
+	err := writeValue(w, v, strings.Repeat(" ", indent), "")
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func writeValue(w io.Writer, v any, indent, prefix string) error {
+	switch t := v.(type) {
+	case object:
+		if t.Len() == 0 {
+			_, err := io.WriteString(w, "{}")
+			return err
+		}
+		io.WriteString(w, "{\n")
+		inner := prefix + indent
+		var err error
+		i := 0
+		t.Range(func(key string, val any) bool {
+			k, _ := json.Marshal(key)
+			io.WriteString(w, inner)
+			w.Write(k)
+			io.WriteString(w, ": ")
+			err = writeValue(w, val, indent, inner)
+			if err != nil {
+				return false
+			}
+			i++
+			if i < t.Len() {
+				io.WriteString(w, ",")
+			}
+			io.WriteString(w, "\n")
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, prefix+"}")
+		return err
+	case []any:
+		if len(t) == 0 {
+			_, err := io.WriteString(w, "[]")
+			return err
+		}
+		io.WriteString(w, "[\n")
+		inner := prefix + indent
+		for i, val := range t {
+			io.WriteString(w, inner)
+			err := writeValue(w, val, indent, inner)
+			if err != nil {
+				return err
+			}
+			if i < len(t)-1 {
+				io.WriteString(w, ",")
+			}
+			io.WriteString(w, "\n")
+		}
+		_, err := io.WriteString(w, prefix+"]")
+		return err
+	default:
+		bs, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bs)
+		return err
+	}
+}
+
+func sortKeys(v any) any {
+	switch t := v.(type) {
+	case object:
+		keys := make([]string, 0, t.Len())
+		t.Range(func(key string, _ any) bool {
+			keys = append(keys, key)
+			return true
+		})
+		sort.Strings(keys)
+		om := orderedmap.New[string, any]()
+		for _, key := range keys {
+			val, _ := t.Load(key)
+			om.Store(key, sortKeys(val))
+		}
+		return &om
+	case []any:
+		arr := make([]any, len(t))
+		for i, val := range t {
+			arr[i] = sortKeys(val)
+		}
+		return arr
+	default:
+		return v
+	}
+}
+
+// extract is a function which returns the value at a path, which is a
+// sequence of object keys and array indexes separated by dots.
+func extract(v any, path string) (any, error) {
+	if path == "" || path == "." {
+		return v, nil
+	}
+	for _, seg := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		switch t := v.(type) {
+		case object:
+			val, ok := t.Load(seg)
+			if !ok {
+				return nil, fmt.Errorf("no key %q in path %s", seg, path)
+			}
+			v = val
+		case []any:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil, fmt.Errorf("no index %q in path %s", seg, path)
+			}
+			v = t[i]
+		default:
+			return nil, fmt.Errorf("no value at %q in path %s", seg, path)
+		}
+	}
+	return v, nil
+}
+
+func compact(v any) string {
+	var sb strings.Builder
+	writeCompact(&sb, v)
+	return sb.String()
+}
+
+func writeCompact(sb *strings.Builder, v any) {
+	switch t := v.(type) {
+	case object:
+		sb.WriteString("{")
+		sep := ""
+		t.Range(func(key string, val any) bool {
+			k, _ := json.Marshal(key)
+			sb.WriteString(sep)
+			sb.Write(k)
+			sb.WriteString(":")
+			writeCompact(sb, val)
+			sep = ","
+			return true
+		})
+		sb.WriteString("}")
+	case []any:
+		sb.WriteString("[")
+		for i, val := range t {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			writeCompact(sb, val)
+		}
+		sb.WriteString("]")
+	default:
+		bs, _ := json.Marshal(t)
+		sb.Write(bs)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// writeYAML is a function which writes a value as a YAML document in block
+// style, keeping the order of object keys.
+func writeYAML(w io.Writer, v any) error {
+	var sb strings.Builder
+	switch t := v.(type) {
+	case object:
+		if t.Len() == 0 {
+			sb.WriteString("{}\n")
+		} else {
+			yamlObject(&sb, t, "")
+		}
+	case []any:
+		if len(t) == 0 {
+			sb.WriteString("[]\n")
+		} else {
+			yamlArray(&sb, t, "")
+		}
+	default:
+		sb.WriteString(yamlScalar(v))
+		sb.WriteString("\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func yamlObject(sb *strings.Builder, om object, prefix string) {
+	om.Range(func(key string, val any) bool {
+		sb.WriteString(prefix)
+		sb.WriteString(yamlString(key))
+		sb.WriteString(":")
+		yamlChild(sb, val, prefix)
+		return true
+	})
+}
+
+func yamlArray(sb *strings.Builder, arr []any, prefix string) {
+	for _, val := range arr {
+		sb.WriteString(prefix)
+		sb.WriteString("-")
+		yamlChild(sb, val, prefix)
+	}
+}
+
+func yamlChild(sb *strings.Builder, v any, prefix string) {
+	switch t := v.(type) {
+	case object:
+		if t.Len() == 0 {
+			sb.WriteString(" {}\n")
+			return
+		}
+		sb.WriteString("\n")
+		yamlObject(sb, t, prefix+"  ")
+	case []any:
+		if len(t) == 0 {
+			sb.WriteString(" []\n")
+			return
+		}
+		sb.WriteString("\n")
+		yamlArray(sb, t, prefix+"  ")
+	default:
+		sb.WriteString(" ")
+		sb.WriteString(yamlScalar(v))
+		sb.WriteString("\n")
+	}
+}
+
+func yamlScalar(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(t)
+	case json.Number:
+		return t.String()
+	case string:
+		return yamlString(t)
+	default:
+		bs, _ := json.Marshal(t)
+		return string(bs)
+	}
+}
+
+// yamlString is a function which returns a string as a plain scalar if it is
+// safe, otherwise as a double-quoted scalar, which has the same escapes as a
+// JSON string.
+func yamlString(s string) string {
+	if isPlainYAML(s) {
+		return s
+	}
+	bs, _ := json.Marshal(s)
+	return string(bs)
+}
+
+func isPlainYAML(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return false
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return false
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return false
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return false
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package conformance
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Backend is an interface which has the methods of a backend of an ordered
+// map, instantiated with string keys and int values.
+type Backend interface {
+	Get(key string) (value int, ok bool, err error)
+	Put(key string, value int) error
+	Delete(key string) error
+	Iterate(fn func(key string, value int) bool) error
+	Len() (int, error)
+}
+
+// BatchGetter is an interface which a backend implements optionally to get
+// values for many keys at once.
+type BatchGetter interface {
+	GetMany(keys []string) (values []int, found []bool, err error)
+}
+
+// RunBackend is a function which runs the cases for a backend of an ordered
+// map. newBackend must return an empty backend on each call.
+func RunBackend(t *testing.T, newBackend func(t *testing.T) Backend) {
+	t.Run("PutAndGet", func(t *testing.T) {
+		b := newBackend(t)
+		must(t, b.Put("a", 1))
+		must(t, b.Put("b", 2))
+		must(t, b.Put("a", 10))
+
+		v, ok, err := b.Get("a")
+		must(t, err)
+		if v != 10 || !ok {
+			t.Errorf("Get(a) = (%d, %t), want (10, true)", v, ok)
+		}
+		_, ok, err = b.Get("x")
+		must(t, err)
+		if ok {
+			t.Errorf("Get(x) found an absent key")
+		}
+		assertBackendKeys(t, b, "a", "b")
+	})
+	t.Run("Delete", func(t *testing.T) {
+		b := newBackend(t)
+		must(t, b.Put("a", 1))
+		must(t, b.Put("b", 2))
+		must(t, b.Put("c", 3))
+		must(t, b.Delete("a"))
+		must(t, b.Delete("x"))
+		assertBackendKeys(t, b, "b", "c")
+
+		must(t, b.Put("a", 4))
+		assertBackendKeys(t, b, "b", "c", "a")
+	})
+	t.Run("IterateStop", func(t *testing.T) {
+		b := newBackend(t)
+		must(t, b.Put("a", 1))
+		must(t, b.Put("b", 2))
+		n := 0
+		must(t, b.Iterate(func(string, int) bool {
+			n++
+			return false
+		}))
+		if n != 1 {
+			t.Errorf("Iterate did not stop: %d", n)
+		}
+	})
+	t.Run("GetMany", func(t *testing.T) {
+		b := newBackend(t)
+		bg, ok := b.(BatchGetter)
+		if !ok {
+			t.Skip("GetMany is not supported")
+		}
+		must(t, b.Put("a", 1))
+		must(t, b.Put("b", 2))
+		values, found, err := bg.GetMany([]string{"b", "x", "a"})
+		must(t, err)
+		if !reflect.DeepEqual(values, []int{2, 0, 1}) ||
+			!reflect.DeepEqual(found, []bool{true, false, true}) {
+			t.Errorf("GetMany = (%v, %v)", values, found)
+		}
+	})
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertBackendKeys(t *testing.T, b Backend, keys ...string) {
+	t.Helper()
+	got := []string{}
+	must(t, b.Iterate(func(k string, v int) bool {
+		got = append(got, k)
+		return true
+	}))
+	if !equalKeys(got, keys) {
+		t.Errorf("Iterate keys = %v, want %v", got, keys)
+	}
+	n, err := b.Len()
+	must(t, err)
+	if n != len(keys) {
+		t.Errorf("Len() = %d, want %d", n, len(keys))
+	}
+}
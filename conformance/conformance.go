@@ -0,0 +1,330 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package conformance provides a black-box test suite which checks the
+// behaviors of ordered maps of every version in this repository.
+//
+// The suite uses only the methods which every version has, and a version is
+// connected to the suite with an Adapter:
+//
+//	func TestV1_0_0(t *testing.T) {
+//		conformance.Run(t, conformance.Adapter{
+//			New: func() conformance.Map {
+//				om := v1_0_0.New[string, int]()
+//				return &om
+//			},
+//			...
+//		})
+//	}
+package conformance
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// Map is an interface which has the methods that ordered maps of all versions
+// have in common, instantiated with string keys and int values.
+type Map interface {
+	Len() int
+	Store(key string, value int)
+	Swap(key string, value int) (previous int, loaded bool)
+	Load(key string) (value int, ok bool)
+	LoadOrStore(key string, value int) (actual int, loaded bool)
+	Delete(key string)
+	Ldelete(key string)
+	LoadAndDelete(key string) (value int, loaded bool)
+	LoadAndLdelete(key string) (value int, loaded bool)
+	Range(fn func(key string, value int) bool)
+}
+
+// Adapter is a struct which connects an ordered map of a version to this
+// suite.
+//
+// New, Forward and Backward are mandatory. Forward and Backward return keys by
+// walking entries with Front/Next and Back/Prev.
+// PopFront and PopBack are optional and are for versions which have
+// FrontAndDelete and BackAndDelete. If they are nil, the cases for them are
+// skipped.
+type Adapter struct {
+	New      func() Map
+	Forward  func(m Map) []string
+	Backward func(m Map) []string
+	PopFront func(m Map) (key string, value int, ok bool)
+	PopBack  func(m Map) (key string, value int, ok bool)
+}
+
+// Run is a function which runs all cases of this suite for the ordered map
+// which the specified adapter creates.
+func Run(t *testing.T, a Adapter) {
+	t.Run("Store", func(t *testing.T) { testStore(t, a) })
+	t.Run("Swap", func(t *testing.T) { testSwap(t, a) })
+	t.Run("LoadOrStore", func(t *testing.T) { testLoadOrStore(t, a) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, a) })
+	t.Run("Ldelete", func(t *testing.T) { testLdelete(t, a) })
+	t.Run("LoadAndDelete", func(t *testing.T) { testLoadAndDelete(t, a) })
+	t.Run("LoadAndLdelete", func(t *testing.T) { testLoadAndLdelete(t, a) })
+	t.Run("Range", func(t *testing.T) { testRange(t, a) })
+	t.Run("Walk", func(t *testing.T) { testWalk(t, a) })
+	t.Run("PopFront", func(t *testing.T) { testPopFront(t, a) })
+	t.Run("PopBack", func(t *testing.T) { testPopBack(t, a) })
+	t.Run("JSON", func(t *testing.T) { testJSON(t, a) })
+}
+
+func newMap(a Adapter, keys ...string) Map {
+	m := a.New()
+	for i, k := range keys {
+		m.Store(k, i)
+	}
+	return m
+}
+
+func rangeKeys(m Map) []string {
+	keys := []string{}
+	m.Range(func(k string, v int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func assertKeys(t *testing.T, a Adapter, m Map, keys ...string) {
+	t.Helper()
+	if keys == nil {
+		keys = []string{}
+	}
+	if got := rangeKeys(m); !equalKeys(got, keys) {
+		t.Errorf("Range keys = %v, want %v", got, keys)
+	}
+	if got := a.Forward(m); !equalKeys(got, keys) {
+		t.Errorf("Front/Next keys = %v, want %v", got, keys)
+	}
+	reversed := make([]string, len(keys))
+	for i, k := range keys {
+		reversed[len(keys)-1-i] = k
+	}
+	if got := a.Backward(m); !equalKeys(got, reversed) {
+		t.Errorf("Back/Prev keys = %v, want %v", got, reversed)
+	}
+	if m.Len() != len(keys) {
+		t.Errorf("Len() = %d, want %d", m.Len(), len(keys))
+	}
+}
+
+func equalKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func assertLoad(t *testing.T, m Map, key string, value int, ok bool) {
+	t.Helper()
+	v, found := m.Load(key)
+	if v != value || found != ok {
+		t.Errorf("Load(%q) = (%d, %t), want (%d, %t)", key, v, found, value, ok)
+	}
+}
+
+func testStore(t *testing.T, a Adapter) {
+	m := newMap(a)
+	assertKeys(t, a, m)
+	assertLoad(t, m, "a", 0, false)
+
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Store("c", 3)
+	assertKeys(t, a, m, "a", "b", "c")
+
+	m.Store("a", 10)
+	assertKeys(t, a, m, "a", "b", "c")
+	assertLoad(t, m, "a", 10, true)
+}
+
+func testSwap(t *testing.T, a Adapter) {
+	m := newMap(a, "a", "b")
+
+	prev, loaded := m.Swap("a", 10)
+	if prev != 0 || !loaded {
+		t.Errorf("Swap(a) = (%d, %t), want (0, true)", prev, loaded)
+	}
+	prev, loaded = m.Swap("c", 20)
+	if prev != 0 || loaded {
+		t.Errorf("Swap(c) = (%d, %t), want (0, false)", prev, loaded)
+	}
+	assertKeys(t, a, m, "a", "b", "c")
+	assertLoad(t, m, "a", 10, true)
+	assertLoad(t, m, "c", 20, true)
+}
+
+func testLoadOrStore(t *testing.T, a Adapter) {
+	m := newMap(a, "a")
+
+	actual, loaded := m.LoadOrStore("a", 10)
+	if actual != 0 || !loaded {
+		t.Errorf("LoadOrStore(a) = (%d, %t), want (0, true)", actual, loaded)
+	}
+	actual, loaded = m.LoadOrStore("b", 20)
+	if actual != 20 || loaded {
+		t.Errorf("LoadOrStore(b) = (%d, %t), want (20, false)", actual, loaded)
+	}
+	assertKeys(t, a, m, "a", "b")
+}
+
+func testDelete(t *testing.T, a Adapter) {
+	m := newMap(a, "a", "b", "c", "d")
+
+	m.Delete("b")
+	assertKeys(t, a, m, "a", "c", "d")
+	m.Delete("a")
+	assertKeys(t, a, m, "c", "d")
+	m.Delete("d")
+	assertKeys(t, a, m, "c")
+	m.Delete("x")
+	assertKeys(t, a, m, "c")
+	assertLoad(t, m, "a", 0, false)
+
+	m.Store("a", 10)
+	assertKeys(t, a, m, "c", "a")
+	m.Delete("c")
+	m.Delete("a")
+	assertKeys(t, a, m)
+}
+
+func testLdelete(t *testing.T, a Adapter) {
+	m := newMap(a, "a", "b", "c")
+
+	m.Ldelete("b")
+	assertKeys(t, a, m, "a", "c")
+	assertLoad(t, m, "b", 0, false)
+	m.Ldelete("b")
+	assertKeys(t, a, m, "a", "c")
+
+	m.Store("b", 10)
+	assertKeys(t, a, m, "a", "c", "b")
+	assertLoad(t, m, "b", 10, true)
+
+	m.Ldelete("a")
+	m.Delete("a")
+	assertKeys(t, a, m, "c", "b")
+}
+
+func testLoadAndDelete(t *testing.T, a Adapter) {
+	m := newMap(a, "a", "b")
+
+	v, loaded := m.LoadAndDelete("a")
+	if v != 0 || !loaded {
+		t.Errorf("LoadAndDelete(a) = (%d, %t), want (0, true)", v, loaded)
+	}
+	v, loaded = m.LoadAndDelete("a")
+	if v != 0 || loaded {
+		t.Errorf("LoadAndDelete(a) = (%d, %t), want (0, false)", v, loaded)
+	}
+	assertKeys(t, a, m, "b")
+}
+
+func testLoadAndLdelete(t *testing.T, a Adapter) {
+	m := newMap(a, "a", "b")
+
+	v, loaded := m.LoadAndLdelete("b")
+	if v != 1 || !loaded {
+		t.Errorf("LoadAndLdelete(b) = (%d, %t), want (1, true)", v, loaded)
+	}
+	v, loaded = m.LoadAndLdelete("b")
+	if v != 0 || loaded {
+		t.Errorf("LoadAndLdelete(b) = (%d, %t), want (0, false)", v, loaded)
+	}
+	assertKeys(t, a, m, "a")
+}
+
+func testRange(t *testing.T, a Adapter) {
+	m := newMap(a, "a", "b", "c")
+
+	keys := []string{}
+	m.Range(func(k string, v int) bool {
+		keys = append(keys, k)
+		return k != "b"
+	})
+	if !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		t.Errorf("Range stopped at %v, want [a b]", keys)
+	}
+}
+
+func testWalk(t *testing.T, a Adapter) {
+	m := newMap(a, "a", "b", "c", "d", "e")
+	m.Delete("c")
+	m.Ldelete("a")
+	m.Store("c", 10)
+	m.Store("a", 11)
+	m.Store("b", 12)
+	assertKeys(t, a, m, "b", "d", "e", "c", "a")
+}
+
+func testPopFront(t *testing.T, a Adapter) {
+	if a.PopFront == nil {
+		t.Skip("FrontAndDelete is not supported")
+	}
+	m := newMap(a, "a", "b")
+
+	k, v, ok := a.PopFront(m)
+	if k != "a" || v != 0 || !ok {
+		t.Errorf("PopFront = (%q, %d, %t), want (a, 0, true)", k, v, ok)
+	}
+	assertKeys(t, a, m, "b")
+	a.PopFront(m)
+	assertKeys(t, a, m)
+	_, _, ok = a.PopFront(m)
+	if ok {
+		t.Errorf("PopFront of an empty map returned an entry")
+	}
+}
+
+func testPopBack(t *testing.T, a Adapter) {
+	if a.PopBack == nil {
+		t.Skip("BackAndDelete is not supported")
+	}
+	m := newMap(a, "a", "b")
+
+	k, v, ok := a.PopBack(m)
+	if k != "b" || v != 1 || !ok {
+		t.Errorf("PopBack = (%q, %d, %t), want (b, 1, true)", k, v, ok)
+	}
+	assertKeys(t, a, m, "a")
+	a.PopBack(m)
+	assertKeys(t, a, m)
+	_, _, ok = a.PopBack(m)
+	if ok {
+		t.Errorf("PopBack of an empty map returned an entry")
+	}
+}
+
+func testJSON(t *testing.T, a Adapter) {
+	m := newMap(a, "c", "a", "b")
+	marshaler, ok := m.(json.Marshaler)
+	if !ok {
+		t.Skip("MarshalJSON is not supported")
+	}
+
+	bs, err := marshaler.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != `{"c":0,"a":1,"b":2}` {
+		t.Errorf("MarshalJSON = %s", bs)
+	}
+
+	m2 := a.New()
+	err = m2.(json.Unmarshaler).UnmarshalJSON(bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertKeys(t, a, m2, "c", "a", "b")
+	assertLoad(t, m2, "b", 2, true)
+}
@@ -0,0 +1,135 @@
+package conformance_test
+
+import (
+	"testing"
+
+	"github.com/sttk/benchmarks_orderedmap/conformance"
+	"github.com/sttk/benchmarks_orderedmap/extended"
+	"github.com/sttk/benchmarks_orderedmap/v0_1_0"
+	"github.com/sttk/benchmarks_orderedmap/v0_4_0"
+	"github.com/sttk/benchmarks_orderedmap/v0_5_0"
+	"github.com/sttk/benchmarks_orderedmap/v0_6_0"
+	"github.com/sttk/benchmarks_orderedmap/v1_0_0"
+)
+
+type entry[E any] interface {
+	comparable
+	Key() string
+	Value() int
+	Next() E
+	Prev() E
+}
+
+func forward[E entry[E]](ent E) []string {
+	var zero E
+	keys := []string{}
+	for ; ent != zero; ent = ent.Next() {
+		keys = append(keys, ent.Key())
+	}
+	return keys
+}
+
+func backward[E entry[E]](ent E) []string {
+	var zero E
+	keys := []string{}
+	for ; ent != zero; ent = ent.Prev() {
+		keys = append(keys, ent.Key())
+	}
+	return keys
+}
+
+func pop[E entry[E]](ent E) (string, int, bool) {
+	var zero E
+	if ent == zero {
+		return "", 0, false
+	}
+	return ent.Key(), ent.Value(), true
+}
+
+func TestV0_1_0(t *testing.T) {
+	type M = v0_1_0.Map[string, int]
+	conformance.Run(t, conformance.Adapter{
+		New: func() conformance.Map {
+			om := v0_1_0.New[string, int]()
+			return &om
+		},
+		Forward:  func(m conformance.Map) []string { return forward(m.(*M).Front()) },
+		Backward: func(m conformance.Map) []string { return backward(m.(*M).Back()) },
+	})
+}
+
+func TestV0_4_0(t *testing.T) {
+	type M = v0_4_0.Map[string, int]
+	conformance.Run(t, conformance.Adapter{
+		New: func() conformance.Map {
+			om := v0_4_0.New[string, int]()
+			return &om
+		},
+		Forward:  func(m conformance.Map) []string { return forward(m.(*M).Front()) },
+		Backward: func(m conformance.Map) []string { return backward(m.(*M).Back()) },
+		PopFront: func(m conformance.Map) (string, int, bool) { return pop(m.(*M).FrontAndDelete()) },
+		PopBack:  func(m conformance.Map) (string, int, bool) { return pop(m.(*M).BackAndDelete()) },
+	})
+}
+
+func TestV0_5_0(t *testing.T) {
+	type M = v0_5_0.Map[string, int]
+	conformance.Run(t, conformance.Adapter{
+		New: func() conformance.Map {
+			om := v0_5_0.New[string, int]()
+			return &om
+		},
+		Forward:  func(m conformance.Map) []string { return forward(m.(*M).Front()) },
+		Backward: func(m conformance.Map) []string { return backward(m.(*M).Back()) },
+		PopFront: func(m conformance.Map) (string, int, bool) { return pop(m.(*M).FrontAndDelete()) },
+		PopBack:  func(m conformance.Map) (string, int, bool) { return pop(m.(*M).BackAndDelete()) },
+	})
+}
+
+func TestV0_6_0(t *testing.T) {
+	type M = v0_6_0.Map[string, int]
+	conformance.Run(t, conformance.Adapter{
+		New: func() conformance.Map {
+			om := v0_6_0.New[string, int]()
+			return &om
+		},
+		Forward:  func(m conformance.Map) []string { return forward(m.(*M).Front()) },
+		Backward: func(m conformance.Map) []string { return backward(m.(*M).Back()) },
+		PopFront: func(m conformance.Map) (string, int, bool) { return pop(m.(*M).FrontAndDelete()) },
+		PopBack:  func(m conformance.Map) (string, int, bool) { return pop(m.(*M).BackAndDelete()) },
+	})
+}
+
+func TestV1_0_0(t *testing.T) {
+	type M = v1_0_0.Map[string, int]
+	conformance.Run(t, conformance.Adapter{
+		New: func() conformance.Map {
+			om := v1_0_0.New[string, int]()
+			return &om
+		},
+		Forward:  func(m conformance.Map) []string { return forward(m.(*M).Front()) },
+		Backward: func(m conformance.Map) []string { return backward(m.(*M).Back()) },
+		PopFront: func(m conformance.Map) (string, int, bool) { return pop(m.(*M).FrontAndDelete()) },
+		PopBack:  func(m conformance.Map) (string, int, bool) { return pop(m.(*M).BackAndDelete()) },
+	})
+}
+
+func TestExtended(t *testing.T) {
+	type M = extended.Map[string, int]
+	conformance.Run(t, conformance.Adapter{
+		New: func() conformance.Map {
+			om := extended.New[string, int]()
+			return &om
+		},
+		Forward:  func(m conformance.Map) []string { return forward(m.(*M).Front()) },
+		Backward: func(m conformance.Map) []string { return backward(m.(*M).Back()) },
+		PopFront: func(m conformance.Map) (string, int, bool) { return pop(m.(*M).FrontAndDelete()) },
+		PopBack:  func(m conformance.Map) (string, int, bool) { return pop(m.(*M).BackAndDelete()) },
+	})
+}
+
+func TestExtendedMemoryBackend(t *testing.T) {
+	conformance.RunBackend(t, func(t *testing.T) conformance.Backend {
+		return extended.NewMemoryBackend[string, int]()
+	})
+}
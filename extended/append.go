@@ -0,0 +1,57 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// Append is a function which appends elements to the slice value for a key
+// in an ordered map of slices. If the key is absent or has expired, a new
+// entry is stored with a slice of the elements.
+// This looks up the key only once, while Load and Store does it twice.
+func Append[K comparable, E any](om *Map[K, []E], key K, elems ...E) {
+	if om.limited() {
+		return
+	}
+	appendTo(om, key, elems)
+}
+
+func appendTo[K comparable, E any](om *Map[K, []E], key K, elems []E) {
+	ent, exists := om.m[key]
+	if exists && !ent.deleted && om.expireIfDue(ent) {
+		exists = false
+	}
+	if exists {
+		if !ent.deleted {
+			old := ent.value
+			ent.value = append(ent.value, elems...)
+			om.updated(ent, old)
+			om.written(key, ent.value)
+			return
+		}
+		ent.value = append([]E(nil), elems...)
+		ent.deleted = false
+	} else {
+		ent = om.newEntry(key, append([]E(nil), elems...))
+	}
+
+	om.index(key, ent)
+	om.linkLast(ent)
+	om.written(key, ent.value)
+}
+
+// GroupAppend is a function which appends each element to the slice value
+// for the key which keyOf returns for the element, in an ordered map of
+// slices. Keys absent in the map are stored in the order of their first
+// elements.
+func GroupAppend[K comparable, E any](
+	om *Map[K, []E],
+	elems []E,
+	keyOf func(elem E) K,
+) {
+	if om.limited() {
+		return
+	}
+	for _, elem := range elems {
+		appendTo(om, keyOf(elem), []E{elem})
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// Backend is an interface of a storage which holds the entries of a
+// BackedMap, e.g. an in-memory map or a key-value store on disk.
+//
+// A backend must preserve the order of key insertions: Put of an absent key
+// appends an entry, and Put of a present key updates the value in place.
+// Iterate must call fn in that order and stop when fn returns false.
+type Backend[K comparable, V any] interface {
+	Get(key K) (value V, ok bool, err error)
+	Put(key K, value V) error
+	Delete(key K) error
+	Iterate(fn func(key K, value V) bool) error
+	Len() (int, error)
+}
+
+// BatchGetter is an interface which a Backend implements optionally to get
+// values for many keys in one round trip, e.g. in one transaction.
+// The values and the found flags are in the same order as the keys.
+type BatchGetter[K comparable, V any] interface {
+	GetMany(keys []K) (values []V, found []bool, err error)
+}
+
+// getMany is a function which gets values for keys from a backend, in one
+// batch if the backend is a BatchGetter.
+func getMany[K comparable, V any](
+	backend Backend[K, V],
+	keys []K,
+) (values []V, found []bool, err error) {
+	if bg, ok := backend.(BatchGetter[K, V]); ok {
+		return bg.GetMany(keys)
+	}
+	values = make([]V, len(keys))
+	found = make([]bool, len(keys))
+	for i, key := range keys {
+		values[i], found[i], err = backend.Get(key)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return
+}
+
+// BackedMap is a struct which is an ordered map whose entries are held by a
+// Backend. This enables huge ordered datasets to spill to disk behind the
+// same methods as Map, which return errors of the backend additionally.
+type BackedMap[K comparable, V any] struct {
+	backend Backend[K, V]
+}
+
+// NewBacked is a function which creates a new ordered map on the specified
+// backend. If the backend is nil, a MemoryBackend is used.
+func NewBacked[K comparable, V any](backend Backend[K, V]) *BackedMap[K, V] {
+	if backend == nil {
+		backend = NewMemoryBackend[K, V]()
+	}
+	return &BackedMap[K, V]{backend: backend}
+}
+
+// Backend is a method which returns the backend of this map.
+func (bm *BackedMap[K, V]) Backend() Backend[K, V] {
+	return bm.backend
+}
+
+// Len is a method which returns the number of entries in this map.
+func (bm *BackedMap[K, V]) Len() (int, error) {
+	return bm.backend.Len()
+}
+
+// Store is a method which sets a value for a key.
+func (bm *BackedMap[K, V]) Store(key K, value V) error {
+	return bm.backend.Put(key, value)
+}
+
+// Load is a method which returns a value stored in this map for a key.
+// If no value was found for a key, the ok result is false.
+func (bm *BackedMap[K, V]) Load(key K) (value V, ok bool, err error) {
+	return bm.backend.Get(key)
+}
+
+// LoadMany is a method which returns values stored in this map for keys,
+// getting them from the backend in one batch if it is a BatchGetter.
+// The values are in the same order as the keys, and the value for a key which
+// was not found is the zero value. The missing result has the keys which were
+// not found, in the order of the keys.
+func (bm *BackedMap[K, V]) LoadMany(keys []K) (values []V, missing []K, err error) {
+	values, found, err := getMany(bm.backend, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, ok := range found {
+		if !ok {
+			missing = append(missing, keys[i])
+		}
+	}
+	return
+}
+
+// Delete is a method which deletes a value for a key.
+func (bm *BackedMap[K, V]) Delete(key K) error {
+	return bm.backend.Delete(key)
+}
+
+// Range is a method which calls the specified function: fn sequentially for
+// each key and value in this map.
+// If fn returns false, this method stops the iteration.
+func (bm *BackedMap[K, V]) Range(fn func(key K, value V) bool) error {
+	return bm.backend.Iterate(fn)
+}
+
+// MemoryBackend is a struct which is a Backend holding entries in an ordered
+// map in memory.
+type MemoryBackend[K comparable, V any] struct {
+	om Map[K, V]
+}
+
+// NewMemoryBackend is a function which creates a new empty MemoryBackend.
+func NewMemoryBackend[K comparable, V any]() *MemoryBackend[K, V] {
+	return &MemoryBackend[K, V]{om: New[K, V]()}
+}
+
+// Get is a method which returns a value for a key.
+func (mb *MemoryBackend[K, V]) Get(key K) (value V, ok bool, err error) {
+	value, ok = mb.om.Load(key)
+	return
+}
+
+// GetMany is a method which returns values for keys.
+func (mb *MemoryBackend[K, V]) GetMany(keys []K) (values []V, found []bool, err error) {
+	values = make([]V, len(keys))
+	found = make([]bool, len(keys))
+	for i, key := range keys {
+		values[i], found[i] = mb.om.Load(key)
+	}
+	return
+}
+
+// Put is a method which sets a value for a key.
+func (mb *MemoryBackend[K, V]) Put(key K, value V) error {
+	mb.om.Store(key, value)
+	return nil
+}
+
+// Delete is a method which deletes a value for a key.
+func (mb *MemoryBackend[K, V]) Delete(key K) error {
+	mb.om.Delete(key)
+	return nil
+}
+
+// Iterate is a method which calls fn for each entry in the order of key
+// insertions.
+func (mb *MemoryBackend[K, V]) Iterate(fn func(key K, value V) bool) error {
+	mb.om.Range(fn)
+	return nil
+}
+
+// Len is a method which returns the number of entries.
+func (mb *MemoryBackend[K, V]) Len() (int, error) {
+	return mb.om.Len(), nil
+}
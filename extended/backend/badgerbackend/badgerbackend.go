@@ -0,0 +1,285 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package badgerbackend provides a Backend of orderedmap.BackedMap which holds
+// entries in a Badger database.
+//
+// Entries are held under a prefix: "<prefix>\x00e" + sequence number maps to
+// a pair of a key and a value, and "<prefix>\x00i" + key maps to the sequence
+// number of the key. Keys and values are encoded in JSON.
+package badgerbackend
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	badger "github.com/dgraph-io/badger/v4"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+	"github.com/sttk/benchmarks_orderedmap/extended/backend/internal/codec"
+)
+
+const seqBandwidth = 1000
+
+// ErrClosed is an error which is returned by methods of a Backend after it
+// was closed. It wraps orderedmap.ErrClosed.
+var ErrClosed = fmt.Errorf("badgerbackend: backend is closed: %w", orderedmap.ErrClosed)
+
+// Backend is a struct which holds entries of an ordered map under a key
+// prefix of a Badger database.
+type Backend[K comparable, V any] struct {
+	db          *badger.DB
+	seq         *badger.Sequence
+	entryPrefix []byte
+	indexPrefix []byte
+	closed      atomic.Bool
+}
+
+var (
+	_ orderedmap.Backend[string, int]     = (*Backend[string, int])(nil)
+	_ orderedmap.BatchGetter[string, int] = (*Backend[string, int])(nil)
+	_ io.Closer                           = (*Backend[string, int])(nil)
+)
+
+// New is a function which creates a Backend under the specified key prefix
+// in the database. Close must be called to release the sequence of the
+// backend.
+func New[K comparable, V any](db *badger.DB, prefix string) (*Backend[K, V], error) {
+	seq, err := db.GetSequence([]byte(prefix+"\x00s"), seqBandwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &Backend[K, V]{
+		db:          db,
+		seq:         seq,
+		entryPrefix: []byte(prefix + "\x00e"),
+		indexPrefix: []byte(prefix + "\x00i"),
+	}, nil
+}
+
+// Close is a method which releases the sequence of this backend. The
+// database is not closed. Calling Close more than once does nothing, and
+// other methods return ErrClosed after Close.
+func (b *Backend[K, V]) Close() error {
+	if !b.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	return b.seq.Release()
+}
+
+func (b *Backend[K, V]) indexKey(key K) ([]byte, error) {
+	k, err := codec.EncodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, b.indexPrefix...), k...), nil
+}
+
+func (b *Backend[K, V]) entryKey(seq []byte) []byte {
+	return append(append([]byte{}, b.entryPrefix...), seq...)
+}
+
+// Get is a method which returns a value for a key.
+func (b *Backend[K, V]) Get(key K) (value V, ok bool, err error) {
+	if b.closed.Load() {
+		return value, false, ErrClosed
+	}
+	ik, err := b.indexKey(key)
+	if err != nil {
+		return
+	}
+	err = b.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(ik)
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		seq, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		item, err = txn.Get(b.entryKey(seq))
+		if err != nil {
+			return err
+		}
+		return item.Value(func(data []byte) error {
+			v, err := codec.DecodeValue[K, V](data)
+			if err != nil {
+				return err
+			}
+			value, ok = v, true
+			return nil
+		})
+	})
+	return
+}
+
+// GetMany is a method which returns values for keys in one read
+// transaction.
+func (b *Backend[K, V]) GetMany(keys []K) (values []V, found []bool, err error) {
+	if b.closed.Load() {
+		return nil, nil, ErrClosed
+	}
+	values = make([]V, len(keys))
+	found = make([]bool, len(keys))
+	err = b.db.View(func(txn *badger.Txn) error {
+		for i, key := range keys {
+			ik, err := b.indexKey(key)
+			if err != nil {
+				return err
+			}
+			item, err := txn.Get(ik)
+			if err == badger.ErrKeyNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			seq, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			item, err = txn.Get(b.entryKey(seq))
+			if err != nil {
+				return err
+			}
+			err = item.Value(func(data []byte) error {
+				var err error
+				values[i], err = codec.DecodeValue[K, V](data)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			found[i] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return
+}
+
+// Put is a method which sets a value for a key.
+func (b *Backend[K, V]) Put(key K, value V) error {
+	if b.closed.Load() {
+		return ErrClosed
+	}
+	ik, err := b.indexKey(key)
+	if err != nil {
+		return err
+	}
+	ent, err := codec.EncodeEntry(key, value)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(txn *badger.Txn) error {
+		var seq []byte
+		item, err := txn.Get(ik)
+		switch err {
+		case nil:
+			seq, err = item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+		case badger.ErrKeyNotFound:
+			n, err := b.seq.Next()
+			if err != nil {
+				return err
+			}
+			seq = codec.Seq(n)
+			err = txn.Set(ik, seq)
+			if err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+		return txn.Set(b.entryKey(seq), ent)
+	})
+}
+
+// Delete is a method which deletes a value for a key.
+func (b *Backend[K, V]) Delete(key K) error {
+	if b.closed.Load() {
+		return ErrClosed
+	}
+	ik, err := b.indexKey(key)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get(ik)
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		seq, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		err = txn.Delete(b.entryKey(seq))
+		if err != nil {
+			return err
+		}
+		return txn.Delete(ik)
+	})
+}
+
+// Iterate is a method which calls fn for each entry in the order of key
+// insertions, in a read transaction.
+func (b *Backend[K, V]) Iterate(fn func(key K, value V) bool) error {
+	if b.closed.Load() {
+		return ErrClosed
+	}
+	return b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = b.entryPrefix
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			var key K
+			var value V
+			err := it.Item().Value(func(data []byte) error {
+				var err error
+				key, value, err = codec.DecodeEntry[K, V](data)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if !fn(key, value) {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+// Len is a method which returns the number of entries. This method counts
+// keys in the index.
+func (b *Backend[K, V]) Len() (int, error) {
+	if b.closed.Load() {
+		return 0, ErrClosed
+	}
+	n := 0
+	err := b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = b.indexPrefix
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
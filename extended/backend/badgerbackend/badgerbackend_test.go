@@ -0,0 +1,56 @@
+package badgerbackend_test
+
+import (
+	"errors"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v4"
+
+	"github.com/sttk/benchmarks_orderedmap/conformance"
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+	"github.com/sttk/benchmarks_orderedmap/extended/backend/badgerbackend"
+)
+
+func TestBackend(t *testing.T) {
+	conformance.RunBackend(t, func(t *testing.T) conformance.Backend {
+		opts := badger.DefaultOptions("").WithInMemory(true).WithLogger(nil)
+		db, err := badger.Open(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := badgerbackend.New[string, int](db, "om")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			b.Close()
+			db.Close()
+		})
+		return b
+	})
+}
+
+func TestBackend_Close(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	b, err := badgerbackend.New[string, int](db, "om")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+	if err := b.Put("a", 1); err != badgerbackend.ErrClosed {
+		t.Errorf("Put after Close = %v", err)
+	}
+	if _, _, err := b.Get("a"); !errors.Is(err, orderedmap.ErrClosed) {
+		t.Errorf("Get after Close = %v", err)
+	}
+}
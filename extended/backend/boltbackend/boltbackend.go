@@ -0,0 +1,192 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package boltbackend provides a Backend of orderedmap.BackedMap which holds
+// entries in a bbolt database.
+//
+// Entries are held in a bucket: the sub-bucket "entries" maps sequence numbers
+// to pairs of keys and values, and the sub-bucket "index" maps keys to their
+// sequence numbers. Keys and values are encoded in JSON.
+package boltbackend
+
+import (
+	bolt "go.etcd.io/bbolt"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+	"github.com/sttk/benchmarks_orderedmap/extended/backend/internal/codec"
+)
+
+var (
+	entriesBucket = []byte("entries")
+	indexBucket   = []byte("index")
+)
+
+// Backend is a struct which holds entries of an ordered map in a bucket of a
+// bbolt database.
+type Backend[K comparable, V any] struct {
+	db     *bolt.DB
+	bucket []byte
+}
+
+var (
+	_ orderedmap.Backend[string, int]     = (*Backend[string, int])(nil)
+	_ orderedmap.BatchGetter[string, int] = (*Backend[string, int])(nil)
+)
+
+// New is a function which creates a Backend on the bucket of the specified
+// name in the database. The bucket is created if absent.
+func New[K comparable, V any](db *bolt.DB, bucket string) (*Backend[K, V], error) {
+	b := &Backend[K, V]{db: db, bucket: []byte(bucket)}
+	err := db.Update(func(tx *bolt.Tx) error {
+		bk, err := tx.CreateBucketIfNotExists(b.bucket)
+		if err != nil {
+			return err
+		}
+		_, err = bk.CreateBucketIfNotExists(entriesBucket)
+		if err != nil {
+			return err
+		}
+		_, err = bk.CreateBucketIfNotExists(indexBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *Backend[K, V]) buckets(tx *bolt.Tx) (entries, index *bolt.Bucket) {
+	bk := tx.Bucket(b.bucket)
+	return bk.Bucket(entriesBucket), bk.Bucket(indexBucket)
+}
+
+// Get is a method which returns a value for a key.
+func (b *Backend[K, V]) Get(key K) (value V, ok bool, err error) {
+	k, err := codec.EncodeKey(key)
+	if err != nil {
+		return
+	}
+	err = b.db.View(func(tx *bolt.Tx) error {
+		entries, index := b.buckets(tx)
+		seq := index.Get(k)
+		if seq == nil {
+			return nil
+		}
+		v, err := codec.DecodeValue[K, V](entries.Get(seq))
+		if err != nil {
+			return err
+		}
+		value, ok = v, true
+		return nil
+	})
+	return
+}
+
+// GetMany is a method which returns values for keys in one read
+// transaction.
+func (b *Backend[K, V]) GetMany(keys []K) (values []V, found []bool, err error) {
+	values = make([]V, len(keys))
+	found = make([]bool, len(keys))
+	err = b.db.View(func(tx *bolt.Tx) error {
+		entries, index := b.buckets(tx)
+		for i, key := range keys {
+			k, err := codec.EncodeKey(key)
+			if err != nil {
+				return err
+			}
+			seq := index.Get(k)
+			if seq == nil {
+				continue
+			}
+			values[i], err = codec.DecodeValue[K, V](entries.Get(seq))
+			if err != nil {
+				return err
+			}
+			found[i] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return
+}
+
+// Put is a method which sets a value for a key.
+func (b *Backend[K, V]) Put(key K, value V) error {
+	k, err := codec.EncodeKey(key)
+	if err != nil {
+		return err
+	}
+	ent, err := codec.EncodeEntry(key, value)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		entries, index := b.buckets(tx)
+		seq := index.Get(k)
+		if seq == nil {
+			n, err := entries.NextSequence()
+			if err != nil {
+				return err
+			}
+			seq = codec.Seq(n)
+			err = index.Put(k, seq)
+			if err != nil {
+				return err
+			}
+		}
+		return entries.Put(seq, ent)
+	})
+}
+
+// Delete is a method which deletes a value for a key.
+func (b *Backend[K, V]) Delete(key K) error {
+	k, err := codec.EncodeKey(key)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		entries, index := b.buckets(tx)
+		seq := index.Get(k)
+		if seq == nil {
+			return nil
+		}
+		err := entries.Delete(seq)
+		if err != nil {
+			return err
+		}
+		return index.Delete(k)
+	})
+}
+
+// Iterate is a method which calls fn for each entry in the order of key
+// insertions, in a read transaction.
+func (b *Backend[K, V]) Iterate(fn func(key K, value V) bool) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		entries, _ := b.buckets(tx)
+		c := entries.Cursor()
+		for _, data := c.First(); data != nil; _, data = c.Next() {
+			key, value, err := codec.DecodeEntry[K, V](data)
+			if err != nil {
+				return err
+			}
+			if !fn(key, value) {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+// Len is a method which returns the number of entries.
+func (b *Backend[K, V]) Len() (int, error) {
+	n := 0
+	err := b.db.View(func(tx *bolt.Tx) error {
+		_, index := b.buckets(tx)
+		n = index.Stats().KeyN
+		return nil
+	})
+	return n, err
+}
@@ -0,0 +1,52 @@
+package boltbackend_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/sttk/benchmarks_orderedmap/conformance"
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+	"github.com/sttk/benchmarks_orderedmap/extended/backend/boltbackend"
+)
+
+func openDB(t *testing.T) *bolt.DB {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestBackend(t *testing.T) {
+	conformance.RunBackend(t, func(t *testing.T) conformance.Backend {
+		b, err := boltbackend.New[string, int](openDB(t), "om")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	})
+}
+
+func TestBackedMap(t *testing.T) {
+	db := openDB(t)
+	b, err := boltbackend.New[string, []string](db, "om")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bm := orderedmap.NewBacked[string, []string](b)
+	if err := bm.Store("x", []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	b2, err := boltbackend.New[string, []string](db, "om")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, ok, err := orderedmap.NewBacked[string, []string](b2).Load("x")
+	if err != nil || !ok || len(v) != 2 || v[1] != "b" {
+		t.Errorf("Load = (%v, %t, %v)", v, ok, err)
+	}
+}
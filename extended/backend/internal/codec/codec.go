@@ -0,0 +1,49 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package codec provides encodings of keys and entries which are shared by
+// the backend adapters.
+package codec
+
+import (
+	"encoding/binary"
+	"encoding/json"
+)
+
+type entry[K comparable, V any] struct {
+	Key   K `json:"k"`
+	Value V `json:"v"`
+}
+
+// EncodeKey is a function which encodes a key into bytes used to index it.
+func EncodeKey[K comparable](key K) ([]byte, error) {
+	return json.Marshal(key)
+}
+
+// EncodeEntry is a function which encodes a pair of a key and a value.
+func EncodeEntry[K comparable, V any](key K, value V) ([]byte, error) {
+	return json.Marshal(entry[K, V]{Key: key, Value: value})
+}
+
+// DecodeEntry is a function which decodes bytes encoded by EncodeEntry.
+func DecodeEntry[K comparable, V any](data []byte) (key K, value V, err error) {
+	var ent entry[K, V]
+	err = json.Unmarshal(data, &ent)
+	return ent.Key, ent.Value, err
+}
+
+// DecodeValue is a function which decodes only the value from bytes encoded
+// by EncodeEntry.
+func DecodeValue[K comparable, V any](data []byte) (value V, err error) {
+	_, value, err = DecodeEntry[K, V](data)
+	return
+}
+
+// Seq is a function which encodes a sequence number into bytes whose
+// lexicographical order is same with the numerical order.
+func Seq(n uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+	return b
+}
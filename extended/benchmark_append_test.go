@@ -0,0 +1,45 @@
+package extended_test
+
+import (
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+func BenchmarkNew_OrderedMap_Append_loadAndStore(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		om := orderedmap.New[int, []int]()
+		for j := 0; j < 1000; j++ {
+			v, _ := om.Load(j % 10)
+			om.Store(j%10, append(v, j))
+		}
+	}
+}
+
+func BenchmarkNew_OrderedMap_Append_append(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		om := orderedmap.New[int, []int]()
+		for j := 0; j < 1000; j++ {
+			orderedmap.Append(&om, j%10, j)
+		}
+	}
+}
+
+func BenchmarkNew_OrderedMap_Add_loadAndStore(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		om := orderedmap.New[int, int]()
+		for j := 0; j < 1000; j++ {
+			v, _ := om.Load(j % 10)
+			om.Store(j%10, v+j)
+		}
+	}
+}
+
+func BenchmarkNew_OrderedMap_Add_add(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		om := orderedmap.New[int, int]()
+		for j := 0; j < 1000; j++ {
+			orderedmap.Add(&om, j%10, j)
+		}
+	}
+}
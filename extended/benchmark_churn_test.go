@@ -0,0 +1,109 @@
+package extended_test
+
+import (
+	"runtime"
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+// The churn benchmarks keep a fixed window of live entries and, on every
+// iteration, store one new key and delete the oldest one. Because the number
+// of live entries never changes, the live heap should stay flat; the reported
+// "live-B" metric is the growth of the live heap between the start and the
+// end of the run, and "live-B/op" is that growth divided by b.N.
+//
+// Run them for a long time to use them as a soak reproducer, e.g.:
+//
+//	go test -run '^$' -bench Churn -benchtime 5m ./extended
+const churnWindow = 10000
+
+func liveHeap() uint64 {
+	runtime.GC()
+	runtime.GC()
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.HeapAlloc
+}
+
+func reportHeapGrowth(b *testing.B, before, after uint64) {
+	growth := float64(after) - float64(before)
+	b.ReportMetric(growth, "live-B")
+	b.ReportMetric(growth/float64(b.N), "live-B/op")
+}
+
+func BenchmarkNew_OrderedMap_Churn_storeAndDelete(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[int, Foo]()
+	for i := 0; i < churnWindow; i++ {
+		om.Store(i, Foo{Bar: "bar", Baz: i})
+	}
+	before := liveHeap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Store(churnWindow+i, Foo{Bar: "bar", Baz: i})
+		om.Delete(i)
+	}
+
+	b.StopTimer()
+	reportHeapGrowth(b, before, liveHeap())
+	runtime.KeepAlive(&om)
+}
+
+func BenchmarkNew_OrderedMap_Churn_storeAndLdelete(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[int, Foo]()
+	for i := 0; i < churnWindow; i++ {
+		om.Store(i, Foo{Bar: "bar", Baz: i})
+	}
+	before := liveHeap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Store(churnWindow+i, Foo{Bar: "bar", Baz: i})
+		om.Ldelete(i)
+	}
+
+	b.StopTimer()
+	reportHeapGrowth(b, before, liveHeap())
+	runtime.KeepAlive(&om)
+}
+
+func BenchmarkNew_OrderedMap_Churn_frontAndDelete(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[int, Foo]()
+	for i := 0; i < churnWindow; i++ {
+		om.Store(i, Foo{Bar: "bar", Baz: i})
+	}
+	before := liveHeap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Store(churnWindow+i, Foo{Bar: "bar", Baz: i})
+		om.FrontAndDelete()
+	}
+
+	b.StopTimer()
+	reportHeapGrowth(b, before, liveHeap())
+	runtime.KeepAlive(&om)
+}
+
+func BenchmarkMap_Churn_storeAndDelete(b *testing.B) {
+	b.StopTimer()
+	m := make(map[int]Foo)
+	for i := 0; i < churnWindow; i++ {
+		m[i] = Foo{Bar: "bar", Baz: i}
+	}
+	before := liveHeap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		m[churnWindow+i] = Foo{Bar: "bar", Baz: i}
+		delete(m, i)
+	}
+
+	b.StopTimer()
+	reportHeapGrowth(b, before, liveHeap())
+	runtime.KeepAlive(m)
+}
@@ -0,0 +1,49 @@
+package extended_test
+
+import (
+	"io"
+	"strconv"
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+// The encode benchmarks write a large map to io.Discard. MarshalJSON builds
+// the whole document before it is written, while EncodeJSON writes it in
+// chunks, so B/op shows the memory which streaming saves.
+const encodeLen = 100000
+
+func largeMap() orderedmap.Map[string, Foo] {
+	om := orderedmap.New[string, Foo]()
+	for i := 0; i < encodeLen; i++ {
+		om.Store("key-"+strconv.Itoa(i), Foo{Bar: "bar", Baz: i})
+	}
+	return om
+}
+
+func BenchmarkNew_OrderedMap_Encode_marshalJSONAndWrite(b *testing.B) {
+	b.StopTimer()
+	om := largeMap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		bs, err := om.MarshalJSON()
+		if err != nil {
+			b.Fatal(err)
+		}
+		io.Discard.Write(bs)
+	}
+}
+
+func BenchmarkNew_OrderedMap_Encode_encodeJSON(b *testing.B) {
+	b.StopTimer()
+	om := largeMap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		err := om.EncodeJSON(io.Discard)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
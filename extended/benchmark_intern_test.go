@@ -0,0 +1,44 @@
+package extended_test
+
+import (
+	"runtime"
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+// These benchmarks store values which are a few enum strings repeated many
+// times, each of which is a separate string as when it is read from an input,
+// and report the live heap retained by the map as "live-B".
+
+const numEnumEntries = 10000
+
+var enumBytes = [][]byte{
+	[]byte("active"), []byte("inactive"), []byte("pending"), []byte("closed"),
+}
+
+func benchmarkStoreEnums(b *testing.B, opts ...orderedmap.Option) {
+	b.StopTimer()
+	var om orderedmap.Map[int, string]
+	before := liveHeap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om = orderedmap.New[int, string](opts...)
+		for j := 0; j < numEnumEntries; j++ {
+			om.Store(j, string(enumBytes[j%len(enumBytes)]))
+		}
+	}
+
+	b.StopTimer()
+	b.ReportMetric(float64(liveHeap())-float64(before), "live-B")
+	runtime.KeepAlive(&om)
+}
+
+func BenchmarkNew_OrderedMap_Store_enums(b *testing.B) {
+	benchmarkStoreEnums(b)
+}
+
+func BenchmarkNew_OrderedMap_Store_enumsWithStringInterning(b *testing.B) {
+	benchmarkStoreEnums(b, orderedmap.WithStringInterning())
+}
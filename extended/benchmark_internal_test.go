@@ -0,0 +1,93 @@
+package extended
+
+import (
+	"testing"
+)
+
+// These benchmarks separate the cost of the hash index (the Go map from keys
+// to entries) from the cost of maintaining the doubly linked entry list.
+// Entries are allocated before the timer starts, so the *_indexAndList
+// results are the sum of the other two plus the overhead of their
+// interaction, without the entry allocation of Store.
+
+type foo struct {
+	Bar string
+	Baz int
+}
+
+var fiveKeys = []string{"foo-0", "foo-1", "foo-2", "foo-3", "foo-4"}
+
+func fiveEntries() []*Entry[string, foo] {
+	ents := make([]*Entry[string, foo], len(fiveKeys))
+	for i, k := range fiveKeys {
+		ents[i] = &Entry[string, foo]{key: k, value: foo{Bar: "bar", Baz: i}}
+	}
+	return ents
+}
+
+func BenchmarkNew_OrderedMap_StoreAndDelete_indexOnly(b *testing.B) {
+	b.StopTimer()
+	om := New[string, foo]()
+	ents := fiveEntries()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		for _, ent := range ents {
+			om.m[ent.key] = ent
+		}
+		for _, ent := range ents {
+			delete(om.m, ent.key)
+		}
+	}
+}
+
+func BenchmarkNew_OrderedMap_StoreAndDelete_listOnly(b *testing.B) {
+	b.StopTimer()
+	om := New[string, foo]()
+	ents := fiveEntries()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		for _, ent := range ents {
+			om.linkLast(ent)
+		}
+		for _, ent := range ents {
+			om.unlink(ent)
+		}
+	}
+}
+
+func BenchmarkNew_OrderedMap_StoreAndDelete_indexAndList(b *testing.B) {
+	b.StopTimer()
+	om := New[string, foo]()
+	ents := fiveEntries()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		for _, ent := range ents {
+			om.m[ent.key] = ent
+			om.linkLast(ent)
+		}
+		for _, ent := range ents {
+			delete(om.m, ent.key)
+			om.unlink(ent)
+		}
+	}
+}
+
+func BenchmarkNew_OrderedMap_Lookup_indexOnly(b *testing.B) {
+	b.StopTimer()
+	om := New[string, foo]()
+	for _, ent := range fiveEntries() {
+		om.m[ent.key] = ent
+		om.linkLast(ent)
+	}
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		for _, k := range fiveKeys {
+			ent := om.m[k]
+			_ = ent
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package extended_test
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+const numIterateEntries = 1000
+
+func newIterateMap() orderedmap.Map[string, Foo] {
+	om := orderedmap.New[string, Foo]()
+	for i := 0; i < numIterateEntries; i++ {
+		om.Store("foo-"+strconv.Itoa(i), Foo{Bar: "bar", Baz: i})
+	}
+	return om
+}
+
+func BenchmarkNew_OrderedMap_IterateWithFront_keyAndValue(b *testing.B) {
+	b.StopTimer()
+	om := newIterateMap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		n := 0
+		for ent := om.Front(); ent != nil; ent = ent.Next() {
+			k := ent.Key()
+			v := ent.Value()
+			n += len(k) + v.Baz
+		}
+		_ = n
+	}
+}
+
+func BenchmarkNew_OrderedMap_IterateWithFront_kv(b *testing.B) {
+	b.StopTimer()
+	om := newIterateMap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		n := 0
+		for ent := om.Front(); ent != nil; ent = ent.Next() {
+			k, v := ent.KV()
+			n += len(k) + v.Baz
+		}
+		_ = n
+	}
+}
+
+func BenchmarkNew_OrderedMap_IterateWithRange_sum(b *testing.B) {
+	b.StopTimer()
+	om := newIterateMap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		n := 0
+		om.Range(func(k string, v Foo) bool {
+			n += v.Baz
+			return true
+		})
+		_ = n
+	}
+}
+
+func BenchmarkNew_OrderedMap_IterateWithEachN_sum(b *testing.B) {
+	b.StopTimer()
+	om := newIterateMap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		n := 0
+		om.EachN(64, func(keys []string, vals []Foo) {
+			for j := range vals {
+				n += vals[j].Baz
+			}
+		})
+		_ = n
+	}
+}
+
+func BenchmarkNew_OrderedMap_TopN_sort(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[int, int]()
+	for i := 0; i < 100000; i++ {
+		om.Store(i, (i*7919)%100003)
+	}
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		ents := make([]*orderedmap.Entry[int, int], 0, om.Len())
+		for ent := om.Front(); ent != nil; ent = ent.Next() {
+			ents = append(ents, ent)
+		}
+		sort.SliceStable(ents, func(i, j int) bool {
+			return ents[i].Value() > ents[j].Value()
+		})
+		top := ents[:10]
+		_ = top
+	}
+}
+
+func BenchmarkNew_OrderedMap_TopN_topN(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[int, int]()
+	for i := 0; i < 100000; i++ {
+		om.Store(i, (i*7919)%100003)
+	}
+	less := func(a, b *orderedmap.Entry[int, int]) bool {
+		return a.Value() < b.Value()
+	}
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		top := om.TopN(10, less)
+		_ = top
+	}
+}
@@ -0,0 +1,334 @@
+package extended_test
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+const numKeyTypeEntries = 100
+
+func stringKeys() []string {
+	keys := make([]string, numKeyTypeEntries)
+	for i := range keys {
+		keys[i] = "foo-" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+func longStringKeys() []string {
+	prefix := strings.Repeat("abcdefghijklmnopqrstuvwxyz", 10)
+	keys := make([]string, numKeyTypeEntries)
+	for i := range keys {
+		keys[i] = prefix + "-" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+func int64Keys() []int64 {
+	keys := make([]int64, numKeyTypeEntries)
+	for i := range keys {
+		keys[i] = int64(i) * 7919
+	}
+	return keys
+}
+
+func stringPointerKeys() []*string {
+	strs := stringKeys()
+	keys := make([]*string, len(strs))
+	for i := range strs {
+		keys[i] = &strs[i]
+	}
+	return keys
+}
+
+func int64PointerKeys() []*int64 {
+	nums := int64Keys()
+	keys := make([]*int64, len(nums))
+	for i := range nums {
+		keys[i] = &nums[i]
+	}
+	return keys
+}
+
+func uuidKeys() [][16]byte {
+	keys := make([][16]byte, numKeyTypeEntries)
+	for i := range keys {
+		for j := range keys[i] {
+			keys[i][j] = byte(i*31 + j*7)
+		}
+	}
+	return keys
+}
+
+func uuidStringKeys() []string {
+	uuids := uuidKeys()
+	keys := make([]string, len(uuids))
+	for i, u := range uuids {
+		keys[i] = fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+	}
+	return keys
+}
+
+func benchmarkStoreByKeyType[K comparable](b *testing.B, keys []K) {
+	for i := 0; i < b.N; i++ {
+		om := orderedmap.New[K, int]()
+		for j, k := range keys {
+			om.Store(k, j)
+		}
+	}
+}
+
+func benchmarkLoadByKeyType[K comparable](b *testing.B, keys []K) {
+	b.StopTimer()
+	om := orderedmap.New[K, int]()
+	for j, k := range keys {
+		om.Store(k, j)
+	}
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		for _, k := range keys {
+			v, ok := om.Load(k)
+			_ = v
+			_ = ok
+		}
+	}
+}
+
+func benchmarkDeleteByKeyType[K comparable](b *testing.B, keys []K) {
+	b.StopTimer()
+	om := orderedmap.New[K, int]()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		for j, k := range keys {
+			om.Store(k, j)
+		}
+		for _, k := range keys {
+			om.Delete(k)
+		}
+	}
+}
+
+func benchmarkMarshalJSONByKeyType[K comparable](b *testing.B, keys []K) {
+	b.StopTimer()
+	om := orderedmap.New[K, int]()
+	for j, k := range keys {
+		om.Store(k, j)
+	}
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		bs, err := om.MarshalJSON()
+		_ = bs
+		_ = err
+	}
+}
+
+func benchmarkUnmarshalJSONByKeyType[K comparable](b *testing.B, keys []K) {
+	b.StopTimer()
+	src := orderedmap.New[K, int]()
+	for j, k := range keys {
+		src.Store(k, j)
+	}
+	bs, err := src.MarshalJSON()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om := orderedmap.New[K, int]()
+		err := om.UnmarshalJSON(bs)
+		_ = err
+	}
+}
+
+func BenchmarkNew_OrderedMap_Store_keyIsString(b *testing.B) {
+	benchmarkStoreByKeyType(b, stringKeys())
+}
+
+func BenchmarkNew_OrderedMap_Store_keyIsLongString(b *testing.B) {
+	benchmarkStoreByKeyType(b, longStringKeys())
+}
+
+func BenchmarkNew_OrderedMap_Store_keyIsInt64(b *testing.B) {
+	benchmarkStoreByKeyType(b, int64Keys())
+}
+
+func BenchmarkNew_OrderedMap_Store_keyIsStringPointer(b *testing.B) {
+	benchmarkStoreByKeyType(b, stringPointerKeys())
+}
+
+func BenchmarkNew_OrderedMap_Store_keyIsInt64Pointer(b *testing.B) {
+	benchmarkStoreByKeyType(b, int64PointerKeys())
+}
+
+func BenchmarkNew_OrderedMap_Load_keyIsString(b *testing.B) {
+	benchmarkLoadByKeyType(b, stringKeys())
+}
+
+func BenchmarkNew_OrderedMap_Load_keyIsLongString(b *testing.B) {
+	benchmarkLoadByKeyType(b, longStringKeys())
+}
+
+func BenchmarkNew_OrderedMap_Load_keyIsInt64(b *testing.B) {
+	benchmarkLoadByKeyType(b, int64Keys())
+}
+
+func BenchmarkNew_OrderedMap_Load_keyIsStringPointer(b *testing.B) {
+	benchmarkLoadByKeyType(b, stringPointerKeys())
+}
+
+func BenchmarkNew_OrderedMap_Load_keyIsInt64Pointer(b *testing.B) {
+	benchmarkLoadByKeyType(b, int64PointerKeys())
+}
+
+func BenchmarkNew_OrderedMap_Delete_keyIsString(b *testing.B) {
+	benchmarkDeleteByKeyType(b, stringKeys())
+}
+
+func BenchmarkNew_OrderedMap_Delete_keyIsLongString(b *testing.B) {
+	benchmarkDeleteByKeyType(b, longStringKeys())
+}
+
+func BenchmarkNew_OrderedMap_Delete_keyIsInt64(b *testing.B) {
+	benchmarkDeleteByKeyType(b, int64Keys())
+}
+
+func BenchmarkNew_OrderedMap_Delete_keyIsStringPointer(b *testing.B) {
+	benchmarkDeleteByKeyType(b, stringPointerKeys())
+}
+
+func BenchmarkNew_OrderedMap_Delete_keyIsInt64Pointer(b *testing.B) {
+	benchmarkDeleteByKeyType(b, int64PointerKeys())
+}
+
+func BenchmarkNew_OrderedMap_MarshalJSON_keyIsString(b *testing.B) {
+	benchmarkMarshalJSONByKeyType(b, stringKeys())
+}
+
+func BenchmarkNew_OrderedMap_MarshalJSON_keyIsLongString(b *testing.B) {
+	benchmarkMarshalJSONByKeyType(b, longStringKeys())
+}
+
+func BenchmarkNew_OrderedMap_MarshalJSON_keyIsInt64(b *testing.B) {
+	benchmarkMarshalJSONByKeyType(b, int64Keys())
+}
+
+func BenchmarkNew_OrderedMap_MarshalJSON_keyIsStringPointer(b *testing.B) {
+	benchmarkMarshalJSONByKeyType(b, stringPointerKeys())
+}
+
+func BenchmarkNew_OrderedMap_MarshalJSON_keyIsInt64Pointer(b *testing.B) {
+	benchmarkMarshalJSONByKeyType(b, int64PointerKeys())
+}
+
+func BenchmarkNew_OrderedMap_UnmarshalJSON_keyIsString(b *testing.B) {
+	benchmarkUnmarshalJSONByKeyType(b, stringKeys())
+}
+
+func BenchmarkNew_OrderedMap_UnmarshalJSON_keyIsLongString(b *testing.B) {
+	benchmarkUnmarshalJSONByKeyType(b, longStringKeys())
+}
+
+func BenchmarkNew_OrderedMap_UnmarshalJSON_keyIsInt64(b *testing.B) {
+	benchmarkUnmarshalJSONByKeyType(b, int64Keys())
+}
+
+func BenchmarkNew_OrderedMap_UnmarshalJSON_keyIsStringPointer(b *testing.B) {
+	benchmarkUnmarshalJSONByKeyType(b, stringPointerKeys())
+}
+
+func BenchmarkNew_OrderedMap_UnmarshalJSON_keyIsInt64Pointer(b *testing.B) {
+	benchmarkUnmarshalJSONByKeyType(b, int64PointerKeys())
+}
+
+func BenchmarkNew_OrderedMap_Store_keyIsUUID(b *testing.B) {
+	benchmarkStoreByKeyType(b, uuidKeys())
+}
+
+func BenchmarkNew_OrderedMap_Store_keyIsUUIDString(b *testing.B) {
+	benchmarkStoreByKeyType(b, uuidStringKeys())
+}
+
+func BenchmarkNew_OrderedMap_Load_keyIsUUID(b *testing.B) {
+	benchmarkLoadByKeyType(b, uuidKeys())
+}
+
+func BenchmarkNew_OrderedMap_Load_keyIsUUIDString(b *testing.B) {
+	benchmarkLoadByKeyType(b, uuidStringKeys())
+}
+
+func BenchmarkNew_OrderedMap_Delete_keyIsUUID(b *testing.B) {
+	benchmarkDeleteByKeyType(b, uuidKeys())
+}
+
+func BenchmarkNew_OrderedMap_Delete_keyIsUUIDString(b *testing.B) {
+	benchmarkDeleteByKeyType(b, uuidStringKeys())
+}
+
+func BenchmarkNew_OrderedMap_MarshalJSON_keyIsUUID(b *testing.B) {
+	benchmarkMarshalJSONByKeyType(b, uuidKeys())
+}
+
+func BenchmarkNew_OrderedMap_MarshalJSON_keyIsUUIDString(b *testing.B) {
+	benchmarkMarshalJSONByKeyType(b, uuidStringKeys())
+}
+
+func BenchmarkNew_OrderedMap_UnmarshalJSON_keyIsUUID(b *testing.B) {
+	benchmarkUnmarshalJSONByKeyType(b, uuidKeys())
+}
+
+func BenchmarkNew_OrderedMap_UnmarshalJSON_keyIsUUIDString(b *testing.B) {
+	benchmarkUnmarshalJSONByKeyType(b, uuidStringKeys())
+}
+
+func tupleKeys() []orderedmap.Key2[string, int] {
+	keys := make([]orderedmap.Key2[string, int], numKeyTypeEntries)
+	for i := range keys {
+		keys[i] = orderedmap.MakeKey2("foo", i)
+	}
+	return keys
+}
+
+func sprintfKeys() []string {
+	keys := make([]string, numKeyTypeEntries)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s|%d", "foo", i)
+	}
+	return keys
+}
+
+func BenchmarkNew_OrderedMap_Load_keyIsKey2(b *testing.B) {
+	benchmarkLoadByKeyType(b, tupleKeys())
+}
+
+func BenchmarkNew_OrderedMap_Load_keyIsSprintf(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[string, int]()
+	for j, k := range sprintfKeys() {
+		om.Store(k, j)
+	}
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < numKeyTypeEntries; j++ {
+			v, ok := om.Load(fmt.Sprintf("%s|%d", "foo", j))
+			_ = v
+			_ = ok
+		}
+	}
+}
+
+func BenchmarkNew_OrderedMap_MarshalJSON_keyIsKey2(b *testing.B) {
+	benchmarkMarshalJSONByKeyType(b, tupleKeys())
+}
+
+func BenchmarkNew_OrderedMap_UnmarshalJSON_keyIsKey2(b *testing.B) {
+	benchmarkUnmarshalJSONByKeyType(b, tupleKeys())
+}
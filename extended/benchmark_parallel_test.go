@@ -0,0 +1,79 @@
+package extended_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+// The parallel iteration benchmarks apply a CPU-heavy function to every entry
+// of a map with numParallelEntries entries, sequentially with Range and in
+// parallel with ForEachParallel.
+const numParallelEntries = 10_000_000
+
+var parallelMap orderedmap.Map[int, uint64]
+
+func newParallelMap() *orderedmap.Map[int, uint64] {
+	if parallelMap.Len() == 0 {
+		parallelMap = orderedmap.New[int, uint64]()
+		for i := 0; i < numParallelEntries; i++ {
+			parallelMap.Store(i, uint64(i))
+		}
+	}
+	return &parallelMap
+}
+
+// heavyWork is a function which mixes a value many times, as a stand-in for
+// per-entry work such as hashing or parsing.
+func heavyWork(v uint64) uint64 {
+	for i := 0; i < 64; i++ {
+		v ^= v >> 33
+		v *= 0xff51afd7ed558ccd
+		v ^= v >> 33
+	}
+	return v
+}
+
+func BenchmarkNew_OrderedMap_Heavy_range(b *testing.B) {
+	b.StopTimer()
+	om := newParallelMap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		var sum atomic.Uint64
+		om.Range(func(k int, v uint64) bool {
+			if w := heavyWork(v); w == 0 {
+				sum.Add(w)
+			}
+			return true
+		})
+	}
+}
+
+func benchmarkForEachParallel(b *testing.B, workers int) {
+	b.StopTimer()
+	om := newParallelMap()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		var sum atomic.Uint64
+		om.ForEachParallel(workers, func(k int, v uint64) {
+			if w := heavyWork(v); w == 0 {
+				sum.Add(w)
+			}
+		})
+	}
+}
+
+func BenchmarkNew_OrderedMap_Heavy_forEachParallel2(b *testing.B) {
+	benchmarkForEachParallel(b, 2)
+}
+
+func BenchmarkNew_OrderedMap_Heavy_forEachParallel4(b *testing.B) {
+	benchmarkForEachParallel(b, 4)
+}
+
+func BenchmarkNew_OrderedMap_Heavy_forEachParallelMax(b *testing.B) {
+	benchmarkForEachParallel(b, 0)
+}
@@ -0,0 +1,40 @@
+package extended_test
+
+import (
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+func BenchmarkNew_OrderedMap_New_requestScoped(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		om := orderedmap.New[string, Foo]()
+		om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+		om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+		om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+		om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+		om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+		bs, err := om.MarshalJSON()
+		_ = bs
+		_ = err
+	}
+}
+
+func BenchmarkNew_OrderedMap_Pool_requestScoped(b *testing.B) {
+	b.StopTimer()
+	pool := orderedmap.NewPool[string, Foo](orderedmap.PoolOptions{MaxLen: 64})
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om := pool.Get()
+		om.Store("foo-0", Foo{Bar: "bar-0", Baz: 0})
+		om.Store("foo-1", Foo{Bar: "bar-1", Baz: 1})
+		om.Store("foo-2", Foo{Bar: "bar-2", Baz: 2})
+		om.Store("foo-3", Foo{Bar: "bar-3", Baz: 3})
+		om.Store("foo-4", Foo{Bar: "bar-4", Baz: 4})
+		bs, err := om.MarshalJSON()
+		_ = bs
+		_ = err
+		pool.Put(om)
+	}
+}
@@ -0,0 +1,78 @@
+package extended_test
+
+import (
+	"math/rand"
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+// The LRU simulation benchmarks replay a skewed access pattern over a map
+// which holds at most lruCap entries. A hit moves the entry to the back,
+// and a miss inserts the key at the back and evicts the front entry if the
+// map is full. Without positional methods, a move is a Delete followed by a
+// Store, which is the baseline for positional implementations.
+const (
+	lruCap  = 1000
+	lruKeys = 4000
+)
+
+func lruAccesses(n int) []int {
+	rnd := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(rnd, 1.1, 1, lruKeys-1)
+	keys := make([]int, n)
+	for i := range keys {
+		keys[i] = int(zipf.Uint64())
+	}
+	return keys
+}
+
+func BenchmarkNew_OrderedMap_LRU_deleteAndStore(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[int, Foo]()
+	accesses := lruAccesses(1 << 16)
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		k := accesses[i&(len(accesses)-1)]
+		if v, ok := om.LoadAndDelete(k); ok {
+			om.Store(k, v)
+			continue
+		}
+		if om.Len() >= lruCap {
+			om.FrontAndDelete()
+		}
+		om.Store(k, Foo{Bar: "bar", Baz: k})
+	}
+}
+
+func BenchmarkNew_OrderedMap_LRU_withMaxLen(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[int, Foo](orderedmap.WithMaxLen(lruCap))
+	accesses := lruAccesses(1 << 16)
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		k := accesses[i&(len(accesses)-1)]
+		if v, ok := om.LoadAndDelete(k); ok {
+			om.Store(k, v)
+			continue
+		}
+		om.Store(k, Foo{Bar: "bar", Baz: k})
+	}
+}
+
+func BenchmarkNew_OrderedMap_LRU_newLRU(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.NewLRU[int, Foo](lruCap)
+	accesses := lruAccesses(1 << 16)
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		k := accesses[i&(len(accesses)-1)]
+		if _, ok := om.Load(k); ok {
+			continue
+		}
+		om.Store(k, Foo{Bar: "bar", Baz: k})
+	}
+}
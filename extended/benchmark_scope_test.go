@@ -0,0 +1,43 @@
+package extended_test
+
+import (
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+const numSmallMaps = 1000
+
+func BenchmarkNew_OrderedMap_New_manySmallMaps(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		maps := make([]*orderedmap.Map[string, int], numSmallMaps)
+		for j := range maps {
+			om := orderedmap.New[string, int]()
+			om.Store("foo-0", 0)
+			om.Store("foo-1", 1)
+			om.Store("foo-2", 2)
+			om.Store("foo-3", 3)
+			om.Store("foo-4", 4)
+			maps[j] = &om
+		}
+		_ = maps
+	}
+}
+
+func BenchmarkNew_OrderedMap_Scope_manySmallMaps(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		sc := orderedmap.NewScope[string, int](1024)
+		maps := make([]*orderedmap.Map[string, int], numSmallMaps)
+		for j := range maps {
+			om := sc.New()
+			om.Store("foo-0", 0)
+			om.Store("foo-1", 1)
+			om.Store("foo-2", 2)
+			om.Store("foo-3", 3)
+			om.Store("foo-4", 4)
+			maps[j] = om
+		}
+		_ = maps
+		sc.Free()
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	cborTypeArray = 0x80
+	cborTypeMap   = 0xa0
+	cborBreak     = 0xff
+)
+
+// MarshalCBOR is a method which encodes this map as a definite-length CBOR
+// map, for github.com/fxamacker/cbor/v2.
+// The entries are written in the order of key insertions.
+// Keys of string, bool and numeric types are encoded as CBOR items of those
+// types, and keys of other types are encoded as text strings in the same
+// forms as MarshalJSON.
+func (om Map[K, V]) MarshalCBOR() ([]byte, error) {
+	buf := cborAppendHead(nil, cborTypeMap, uint64(om.Len()))
+	for ent := om.Front(); ent != nil; ent = ent.Next() {
+		bs, err := cborMarshalKey(ent.Key())
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, bs...)
+		if bs, err = cbor.Marshal(ent.Value()); err != nil {
+			return nil, err
+		}
+		buf = append(buf, bs...)
+	}
+	return buf, nil
+}
+
+// UnmarshalCBOR is a method which sets the content of this map from a CBOR
+// map in the order of the encoded entries, for github.com/fxamacker/cbor/v2.
+// Both definite-length and indefinite-length maps are accepted.
+// If the map is created with WithNestedOrder and its value type is any,
+// nested maps are decoded into *Map[string, any].
+func (om *Map[K, V]) UnmarshalCBOR(data []byte) error {
+	if len(data) == 1 && (data[0] == 0xf6 || data[0] == 0xf7) { // null, undefined
+		return nil
+	}
+	n, indef, rest, err := cborReadHead(data, cborTypeMap)
+	if err != nil {
+		return err
+	}
+
+	for i := uint64(0); indef || i < n; i++ {
+		if indef {
+			if len(rest) == 0 {
+				return errCborTruncated
+			}
+			if rest[0] == cborBreak {
+				break
+			}
+		}
+		var key K
+		if key, rest, err = cborUnmarshalKey[K](rest); err != nil {
+			return err
+		}
+		var val V
+		if p, ok := any(&val).(*any); ok && om.ext != nil && om.ext.nestedOrder {
+			*p, rest, err = decodeOrderedCBOR(rest)
+		} else {
+			rest, err = cbor.UnmarshalFirst(rest, &val)
+		}
+		if err != nil {
+			return err
+		}
+		om.put(key, val)
+	}
+	return nil
+}
+
+var errCborTruncated = errors.New("orderedmap: unexpected end of CBOR data")
+
+func cborAppendHead(buf []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(buf, major|byte(n))
+	case n <= 0xff:
+		return append(buf, major|24, byte(n))
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(n))
+	case n <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), n)
+	}
+}
+
+func cborReadHead(data []byte, major byte) (n uint64, indef bool, rest []byte, err error) {
+	if len(data) == 0 {
+		err = errCborTruncated
+		return
+	}
+	if data[0]&0xe0 != major {
+		err = fmt.Errorf("orderedmap: cannot unmarshal CBOR major type %d into a map", data[0]>>5)
+		return
+	}
+	info := data[0] & 0x1f
+	rest = data[1:]
+	switch {
+	case info < 24:
+		n = uint64(info)
+	case info == 31:
+		indef = true
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(rest) < size {
+			err = errCborTruncated
+			return
+		}
+		for _, b := range rest[:size] {
+			n = n<<8 | uint64(b)
+		}
+		rest = rest[size:]
+	default:
+		err = fmt.Errorf("orderedmap: invalid CBOR additional information %d", info)
+	}
+	return
+}
+
+func cborMarshalKey(key any) ([]byte, error) {
+	switch key.(type) {
+	case string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		return cbor.Marshal(key)
+	}
+	var buf bytes.Buffer
+	if err := addKeyText(&buf, key); err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(buf.String())
+}
+
+func cborUnmarshalKey[K comparable](data []byte) (key K, rest []byte, err error) {
+	switch any(key).(type) {
+	case string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		rest, err = cbor.UnmarshalFirst(data, &key)
+		return
+	}
+	var s string
+	if rest, err = cbor.UnmarshalFirst(data, &s); err != nil {
+		return
+	}
+	key, err = parseKey[K](s, 0)
+	return
+}
+
+// decodeOrderedCBOR is a function which decodes the first CBOR item in data
+// into an any, except that maps are decoded into *Map[string, any] at every
+// depth.
+func decodeOrderedCBOR(data []byte) (any, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, errCborTruncated
+	}
+	switch data[0] & 0xe0 {
+	case cborTypeMap:
+		om := New[string, any](WithNestedOrder())
+		var raw cbor.RawMessage
+		rest, err := cbor.UnmarshalFirst(data, &raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := om.UnmarshalCBOR(raw); err != nil {
+			return nil, nil, err
+		}
+		return &om, rest, nil
+	case cborTypeArray:
+		n, indef, rest, err := cborReadHead(data, cborTypeArray)
+		if err != nil {
+			return nil, nil, err
+		}
+		arr := make([]any, 0)
+		for i := uint64(0); indef || i < n; i++ {
+			if indef {
+				if len(rest) == 0 {
+					return nil, nil, errCborTruncated
+				}
+				if rest[0] == cborBreak {
+					rest = rest[1:]
+					break
+				}
+			}
+			var v any
+			if v, rest, err = decodeOrderedCBOR(rest); err != nil {
+				return nil, nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, rest, nil
+	default:
+		var v any
+		rest, err := cbor.UnmarshalFirst(data, &v)
+		return v, rest, err
+	}
+}
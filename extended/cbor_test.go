@@ -0,0 +1,74 @@
+package extended_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+func TestMap_MarshalCBOR(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("z", 1)
+	om.Store("a", 2)
+
+	bs, err := cbor.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// {"z": 1, "a": 2} as a definite-length map of two entries.
+	want := []byte{0xa2, 0x61, 'z', 0x01, 0x61, 'a', 0x02}
+	if !bytes.Equal(bs, want) {
+		t.Errorf("cbor.Marshal = % x", bs)
+	}
+
+	om2 := orderedmap.New[string, int]()
+	if err := cbor.Unmarshal(bs, &om2); err != nil {
+		t.Fatal(err)
+	}
+	if om2.String() != "Map[z:1 a:2]" {
+		t.Errorf("om2 = %v", om2)
+	}
+
+	big := orderedmap.New[int, bool]()
+	for i := 300; i > 0; i-- {
+		big.Store(i, i%2 == 0)
+	}
+	if bs, err = cbor.Marshal(big); err != nil {
+		t.Fatal(err)
+	}
+	big2 := orderedmap.New[int, bool]()
+	if err := cbor.Unmarshal(bs, &big2); err != nil {
+		t.Fatal(err)
+	}
+	if big.String() != big2.String() {
+		t.Errorf("round trip of %d entries: %v", big.Len(), big2)
+	}
+}
+
+func TestMap_UnmarshalCBOR(t *testing.T) {
+	// {_ "b": {"y": 1, "x": [_ 2, 3]}, "a": "s"} with indefinite lengths.
+	data := []byte{
+		0xbf,
+		0x61, 'b', 0xa2, 0x61, 'y', 0x01, 0x61, 'x', 0x9f, 0x02, 0x03, 0xff,
+		0x61, 'a', 0x61, 's',
+		0xff,
+	}
+	om := orderedmap.New[string, any](orderedmap.WithNestedOrder())
+	if err := cbor.Unmarshal(data, &om); err != nil {
+		t.Fatal(err)
+	}
+	if om.String() != "Map[b:Map[y:1 x:[2 3]] a:s]" {
+		t.Errorf("om = %v", om)
+	}
+	b, _ := om.Load("b")
+	if _, ok := b.(*orderedmap.Map[string, any]); !ok {
+		t.Errorf("nested map = %T", b)
+	}
+
+	if err := cbor.Unmarshal([]byte{0x82, 0x01, 0x02}, &om); err == nil {
+		t.Error("expected an error for an array")
+	}
+}
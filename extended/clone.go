@@ -0,0 +1,129 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// Clone is a method which returns a copy of this map in O(n). The copy has
+// the same entries in the same order, and the same options and per-entry
+// states: timestamps, expiration times set by StoreWithTTL, the access order,
+// caps, callbacks, history, etc.
+// Values are copied by assignment, so use DeepClone for values which refer
+// to shared data, e.g. slices, maps and pointers.
+//
+// The copy does not belong to the Scope of this map even if this map was
+// created by a Scope, and it does not track dirty keys or record mutations
+// until Checkpoint or StartRecording is called on it. A trace buffer of the
+// copy starts empty.
+func (om *Map[K, V]) Clone() Map[K, V] {
+	return om.clone(nil)
+}
+
+// DeepClone is a method which returns a copy of this map like Clone, but
+// the values of the entries and of the history are copied with the
+// specified function, e.g.:
+//
+//	cp := om.DeepClone(func(v []string) []string {
+//		return append([]string(nil), v...)
+//	})
+func (om *Map[K, V]) DeepClone(copyValue func(V) V) Map[K, V] {
+	return om.clone(copyValue)
+}
+
+func (om *Map[K, V]) clone(copyValue func(V) V) Map[K, V] {
+	if om == nil {
+		return New[K, V]()
+	}
+	om.debugValidate()
+
+	cp := Map[K, V]{
+		m:   make(map[K](*Entry[K, V]), len(om.m)),
+		len: om.len,
+		seq: om.seq,
+	}
+	if om.ext != nil {
+		cp.ext = om.ext.clone(copyValue)
+	}
+
+	copyEntry := func(ent *Entry[K, V]) *Entry[K, V] {
+		e := &Entry[K, V]{key: ent.key, value: ent.value, deleted: ent.deleted, seq: ent.seq}
+		if copyValue != nil {
+			e.value = copyValue(e.value)
+		}
+		if ent.times != nil {
+			times := *ent.times
+			e.times = &times
+		}
+		return e
+	}
+
+	for ent := om.head; ent != nil; ent = ent.next {
+		e := copyEntry(ent)
+		if cp.last == nil {
+			cp.head = e
+		} else {
+			e.prev = cp.last
+			cp.last.next = e
+		}
+		cp.last = e
+		cp.m[e.key] = e
+	}
+	for key, ent := range om.m {
+		if ent.deleted {
+			cp.m[key] = copyEntry(ent)
+		}
+	}
+	cp.peak = len(cp.m)
+	if cp.ext != nil && cp.ext.quota != nil {
+		cp.ext.quota.reset(cp.head)
+	}
+	return cp
+}
+
+// clone is a method which returns a copy of this extension for a cloned map.
+func (ext *extension[K, V]) clone(copyValue func(V) V) *extension[K, V] {
+	cp := *ext
+	cp.scope = nil
+	cp.dirty = nil
+	cp.patch = nil
+
+	if ext.intern != nil {
+		cp.intern = make(map[string]string, len(ext.intern))
+		for k, v := range ext.intern {
+			cp.intern[k] = v
+		}
+	}
+	if ext.fieldOptions != nil {
+		cp.fieldOptions = make(map[K]FieldOptions, len(ext.fieldOptions))
+		for key, opts := range ext.fieldOptions {
+			cp.fieldOptions[key] = opts
+		}
+	}
+	if ext.trace != nil {
+		cp.trace = &tracer[K]{fn: ext.trace.fn}
+		if ext.trace.buf != nil {
+			cp.trace.buf = make([]TraceRecord[K], len(ext.trace.buf))
+		}
+	}
+	if ext.history != nil {
+		cp.history = make(map[K][]Versioned[V], len(ext.history))
+		for key, vers := range ext.history {
+			vs := make([]Versioned[V], len(vers))
+			copy(vs, vers)
+			if copyValue != nil {
+				for i := range vs {
+					vs[i].Value = copyValue(vs[i].Value)
+				}
+			}
+			cp.history[key] = vs
+		}
+	}
+	if ext.limiter != nil {
+		limiter := *ext.limiter
+		cp.limiter = &limiter
+	}
+	if ext.quota != nil {
+		cp.quota = newPrefixQuota[K, V](ext.quota.limits)
+	}
+	return &cp
+}
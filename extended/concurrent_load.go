@@ -0,0 +1,96 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"context"
+	"sync"
+)
+
+// LoadConcurrent is a function which creates a new ordered map with values
+// fetched concurrently for the specified keys. The entries are stored in the
+// order of the keys regardless of the order in which the fetches complete.
+// A key which appears more than once is fetched only once.
+//
+// At most parallelism fetches run at the same time. If parallelism is zero or
+// less, 1 is used. If a fetch returns an error, the context passed to the
+// other fetches is canceled and the first error is returned in a KeyError, in
+// the manner of golang.org/x/sync/errgroup.
+func LoadConcurrent[K comparable, V any](
+	ctx context.Context,
+	keys []K,
+	fetch func(ctx context.Context, key K) (V, error),
+	parallelism int,
+) (Map[K, V], error) {
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+
+	seen := make(map[K]struct{}, len(keys))
+	uniq := make([]K, 0, len(keys))
+	for _, key := range keys {
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		uniq = append(uniq, key)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	values := make([]V, len(uniq))
+	indexes := make(chan int)
+	var firstErr error
+	var once sync.Once
+	var wg sync.WaitGroup
+
+	if parallelism > len(uniq) {
+		parallelism = len(uniq)
+	}
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				v, err := fetch(ctx, uniq[i])
+				if err != nil {
+					once.Do(func() {
+						firstErr = &KeyError{Op: "fetch", Key: uniq[i], Err: err}
+						cancel()
+					})
+					continue
+				}
+				values[i] = v
+			}
+		}()
+	}
+
+	var ctxErr error
+loop:
+	for i := range uniq {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			ctxErr = ctx.Err()
+			break loop
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return Map[K, V]{}, firstErr
+	}
+	if ctxErr != nil {
+		return Map[K, V]{}, ctxErr
+	}
+
+	om := New[K, V]()
+	for i, key := range uniq {
+		om.Store(key, values[i])
+	}
+	return om, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// BindEnv is a function which stores environment variables whose names start
+// with the specified prefix into the specified map, and returns the number of
+// stored variables.
+// The key of an entry is the name of a variable without the prefix, in lower
+// case, e.g. "db_host" for APP_DB_HOST with the prefix "APP_". The variables
+// are stored in the order of their names, so the order of the map does not
+// depend on the environment.
+func BindEnv(om *Map[string, string], prefix string) int {
+	var names []string
+	values := make(map[string]string)
+	for _, kv := range os.Environ() {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			continue
+		}
+		names = append(names, name)
+		values[name] = value
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		om.put(strings.ToLower(name[len(prefix):]), values[name])
+	}
+	return len(names)
+}
+
+// Layer is a function which combines the specified maps into a new map, where
+// a value in a later map takes precedence over the values for the same key
+// in earlier maps, e.g. Layer(defaults, file, env, flags).
+// The keys are in the order of their first appearances in the maps, so keys
+// of the defaults keep their positions even if they are overridden.
+// Nil maps are skipped.
+func Layer[K comparable, V any](layers ...*Map[K, V]) Map[K, V] {
+	om := New[K, V]()
+	for _, layer := range layers {
+		layer.Range(func(key K, value V) bool {
+			om.Store(key, value)
+			return true
+		})
+	}
+	return om
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"context"
+)
+
+// WithLoaderCtx is a function which returns an option like WithLoader, but fn
+// receives the context passed to LoadCtx, or context.Background for Load and
+// TryLoad.
+// The type parameters must be same with the map's, otherwise New panics.
+func WithLoaderCtx[K comparable, V any](fn func(ctx context.Context, key K) (V, error)) Option {
+	return func(o *options) {
+		o.loader = fn
+	}
+}
+
+// WithWriterCtx is a function which returns an option like WithWriter, but fn
+// receives the context passed to StoreCtx, or context.Background for the
+// other methods.
+// The type parameters must be same with the map's, otherwise New panics.
+func WithWriterCtx[K comparable, V any](fn func(ctx context.Context, key K, value V) error) Option {
+	return func(o *options) {
+		o.writer = fn
+	}
+}
+
+// WithOnEvictCtx is a function which returns an option like WithOnEvict, but
+// fn receives the context passed to StoreCtx or LoadCtx when the eviction is
+// triggered by them, otherwise context.Background.
+// The type parameters must be same with the map's, otherwise New panics.
+func WithOnEvictCtx[K comparable, V any](
+	fn func(ctx context.Context, key K, value V, reason EvictReason),
+) Option {
+	return func(o *options) {
+		o.onEvict = fn
+	}
+}
+
+// LoadCtx is a method which returns a value stored in this map for a key like
+// TryLoad, and passes the specified context to the loader and the eviction
+// callback.
+func (om *Map[K, V]) LoadCtx(ctx context.Context, key K) (value V, ok bool, err error) {
+	if om == nil {
+		return
+	}
+	ent, exists := om.m[key]
+	if exists && !ent.deleted && !om.expireIfDue(ent) {
+		om.accessed(ent)
+		return ent.value, true, nil
+	}
+	if om.ext != nil && om.ext.loader != nil {
+		return om.load(ctx, key)
+	}
+	return
+}
+
+// StoreCtx is a method which sets a value for a key like TryStore, and passes
+// the specified context to the writer and the eviction callback.
+// If a rate limit is set by WithRateLimit, this method waits for the limit
+// until the context is done, or fails with ErrRateLimited, according to its
+// mode.
+func (om *Map[K, V]) StoreCtx(ctx context.Context, key K, value V) error {
+	if om.ext != nil && om.ext.limiter != nil {
+		if err := om.ext.limiter.take(ctx); err != nil {
+			return &KeyError{Op: "store", Key: key, Err: err}
+		}
+	}
+	om.storeCtx(ctx, key, value)
+	if om.ext != nil && om.ext.writer != nil {
+		if err := om.ext.writer(ctx, key, value); err != nil {
+			return &KeyError{Op: "store", Key: key, Err: err}
+		}
+	}
+	return nil
+}
+
+// DeleteCtx is a method which deletes a value for a key like Delete.
+// If a rate limit is set by WithRateLimit, this method waits for the limit
+// until the context is done, or fails with ErrRateLimited, according to its
+// mode, and the error is wrapped in a KeyError.
+func (om *Map[K, V]) DeleteCtx(ctx context.Context, key K) error {
+	if om.ext != nil && om.ext.limiter != nil {
+		if err := om.ext.limiter.take(ctx); err != nil {
+			return &KeyError{Op: "delete", Key: key, Err: err}
+		}
+	}
+	om.remove(key)
+	return nil
+}
+
+// storeCtx is a method which sets a value for a key, and makes the specified
+// context visible to the eviction callback while storing.
+func (om *Map[K, V]) storeCtx(ctx context.Context, key K, value V) {
+	if om.ext == nil || om.ext.onEvict == nil {
+		om.store(key, value)
+		return
+	}
+	prev := om.ext.ctx
+	om.ext.ctx = ctx
+	om.store(key, value)
+	om.ext.ctx = prev
+}
+
+// context is a method which returns the context of the operation in progress.
+// This must be called only when om.ext is not nil.
+func (om *Map[K, V]) context() context.Context {
+	if om.ext.ctx != nil {
+		return om.ext.ctx
+	}
+	return context.Background()
+}
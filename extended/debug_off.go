@@ -0,0 +1,9 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+//go:build !orderedmap_debug
+
+package extended
+
+func (om *Map[K, V]) debugValidate() {}
@@ -0,0 +1,46 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+//go:build orderedmap_debug
+
+package extended
+
+import (
+	"fmt"
+)
+
+// When this package is built with the tag: orderedmap_debug, every iteration
+// validates that the entry list is in the order of the sequence numbers which
+// entries get when they are linked to the end of the entry list, and that its
+// links and length are consistent.
+// A violation means that an operation has relinked entries wrongly, so it
+// panics immediately.
+//
+//	go test -tags orderedmap_debug ./...
+
+func (om *Map[K, V]) debugValidate() {
+	n := 0
+	var prev *Entry[K, V]
+	for ent := om.head; ent != nil; ent = ent.next {
+		if ent.prev != prev {
+			panic(fmt.Sprintf("orderedmap: broken prev link at key %v", ent.key))
+		}
+		if ent.deleted {
+			panic(fmt.Sprintf("orderedmap: deleted entry in list at key %v", ent.key))
+		}
+		if prev != nil && prev.seq >= ent.seq {
+			panic(fmt.Sprintf(
+				"orderedmap: key %v (seq:%d) is after key %v (seq:%d)",
+				ent.key, ent.seq, prev.key, prev.seq))
+		}
+		prev = ent
+		n++
+	}
+	if prev != om.last {
+		panic("orderedmap: last entry is not at the end of the list")
+	}
+	if n != om.len {
+		panic(fmt.Sprintf("orderedmap: len is %d but the list has %d entries", om.len, n))
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// ArrayStrategy is a struct which specifies how DeepMerge merges two arrays
+// ([]any) for the same key. Use ArrayReplace, ArrayConcat,
+// ArrayMergeByIndex or ArrayMergeByKey.
+type ArrayStrategy struct {
+	mode     arrayMergeMode
+	keyField string
+}
+
+type arrayMergeMode int
+
+const (
+	arrayReplace arrayMergeMode = iota
+	arrayConcat
+	arrayMergeByIndex
+	arrayMergeByKey
+)
+
+var (
+	// ArrayReplace replaces an array with the array of the other map.
+	ArrayReplace = ArrayStrategy{mode: arrayReplace}
+
+	// ArrayConcat appends the elements of the array of the other map.
+	ArrayConcat = ArrayStrategy{mode: arrayConcat}
+
+	// ArrayMergeByIndex merges elements at the same index, and appends the
+	// elements of the array of the other map beyond the length.
+	ArrayMergeByIndex = ArrayStrategy{mode: arrayMergeByIndex}
+)
+
+// ArrayMergeByKey is a function which returns an ArrayStrategy to merge
+// elements which are objects having the same value of the specified field,
+// e.g. "name" for containers in Kubernetes manifests. Elements of the array
+// of the other map which match no element are appended.
+func ArrayMergeByKey(field string) ArrayStrategy {
+	return ArrayStrategy{mode: arrayMergeByKey, keyField: field}
+}
+
+// DeepMerge is a method which merges the specified map into this map for
+// nested documents, e.g. maps decoded with WithNestedOrder.
+// For a key in both maps, values which are both *Map[string, any] are merged
+// recursively, values which are both []any are merged with the strategy, and
+// otherwise the value of the specified map replaces the value of this map.
+// Keys of this map keep their positions at every depth, and keys only in
+// the specified map are appended in its order.
+// Nested maps and arrays of this map are modified in place, while those of
+// the specified map are copied, so the specified map is not shared.
+// Keys are looked up like Merge, so loaders are not called.
+func (om *Map[K, V]) DeepMerge(other *Map[K, V], strategy ArrayStrategy) {
+	if om.limited() {
+		return
+	}
+	other.Range(func(key K, value V) bool {
+		merged := copyDocValue(any(value))
+		if left, ok := om.peek(key); ok {
+			merged = mergeDocValues(any(left), any(value), strategy)
+		}
+		v, _ := merged.(V)
+		om.put(key, v)
+		return true
+	})
+}
+
+// mergeDocValues is a function which merges two values of nested documents.
+// The result is of the type of either value.
+func mergeDocValues(left, right any, strategy ArrayStrategy) any {
+	switch l := left.(type) {
+	case *Map[string, any]:
+		if r, ok := right.(*Map[string, any]); ok && l != nil {
+			l.DeepMerge(r, strategy)
+			return l
+		}
+	case []any:
+		if r, ok := right.([]any); ok {
+			return mergeDocArrays(l, r, strategy)
+		}
+	}
+	return copyDocValue(right)
+}
+
+func mergeDocArrays(left, right []any, strategy ArrayStrategy) []any {
+	switch strategy.mode {
+	case arrayConcat:
+		for _, elem := range right {
+			left = append(left, copyDocValue(elem))
+		}
+		return left
+	case arrayMergeByIndex:
+		for i, elem := range right {
+			if i < len(left) {
+				left[i] = mergeDocValues(left[i], elem, strategy)
+			} else {
+				left = append(left, copyDocValue(elem))
+			}
+		}
+		return left
+	case arrayMergeByKey:
+		for _, elem := range right {
+			i := indexByKeyField(left, elem, strategy.keyField)
+			if i < 0 {
+				left = append(left, copyDocValue(elem))
+			} else {
+				left[i] = mergeDocValues(left[i], elem, strategy)
+			}
+		}
+		return left
+	}
+	return copyDocValue(right).([]any)
+}
+
+// indexByKeyField is a function which returns the index of the element of
+// the array which is an object having the same value of the key field as the
+// specified element, or -1 if not found.
+func indexByKeyField(arr []any, elem any, field string) int {
+	obj, ok := elem.(*Map[string, any])
+	if !ok {
+		return -1
+	}
+	key, ok := obj.peek(field)
+	if !ok {
+		return -1
+	}
+	for i, e := range arr {
+		if o, ok := e.(*Map[string, any]); ok {
+			if k, ok := o.peek(field); ok && jsonEqual(k, key) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// copyDocValue is a function which copies nested maps and arrays of a value
+// of a nested document.
+func copyDocValue(value any) any {
+	switch v := value.(type) {
+	case *Map[string, any]:
+		if v == nil {
+			return v
+		}
+		cp := v.DeepClone(copyDocValue)
+		return &cp
+	case []any:
+		if v == nil {
+			return v
+		}
+		cp := make([]any, len(v))
+		for i, elem := range v {
+			cp[i] = copyDocValue(elem)
+		}
+		return cp
+	}
+	return value
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// diffContext is the number of unchanged entries which FormatDiff shows
+// around a change.
+const diffContext = 3
+
+const (
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiReset = "\x1b[0m"
+)
+
+// FormatDiff is a function which returns the differences of the specified
+// maps in a text like a unified diff, one entry per line in the form
+// "key: value". A line starting with "-" is an entry removed from a, a line
+// starting with "+" is an entry added to b, and a changed value is shown as a
+// removed line and an added line. A key whose position changed is shown as
+// removed at its position in a and added at its position in b, with the mark
+// "(moved)". Up to 3 unchanged entries around changes are shown starting
+// with a space, and omitted entries are shown as a line "...".
+// Values are compared with reflect.DeepEqual, and the maps are not changed
+// by loads, e.g. in access order. If the maps have the same keys
+// with the same values in the same order, this function returns "".
+func FormatDiff[K comparable, V any](a, b *Map[K, V]) string {
+	return formatDiff(a, b, false)
+}
+
+// FormatDiffColor is a function which returns the differences of the
+// specified maps like FormatDiff, where removed lines are colored red and
+// added lines are colored green with ANSI escape sequences, for terminals.
+func FormatDiffColor[K comparable, V any](a, b *Map[K, V]) string {
+	return formatDiff(a, b, true)
+}
+
+type diffLine struct {
+	mark byte // ' ', '-' or '+'
+	text string
+}
+
+func formatDiff[K comparable, V any](a, b *Map[K, V], color bool) string {
+	as, bs := a.Pairs(), b.Pairs()
+	stable := stableDiffKeys(as, b, bs)
+
+	var lines []diffLine
+	line := func(mark byte, p Pair[K, V], moved bool) {
+		text := fmt.Sprintf("%v: %v", p.Key, p.Value)
+		if moved {
+			text += " (moved)"
+		}
+		lines = append(lines, diffLine{mark: mark, text: text})
+	}
+	changed := false
+	for i, j := 0, 0; i < len(as) || j < len(bs); {
+		if i < len(as) && !stable[as[i].Key] {
+			line('-', as[i], b.liveEntry(as[i].Key) != nil)
+			changed = true
+			i++
+			continue
+		}
+		if j < len(bs) && !stable[bs[j].Key] {
+			line('+', bs[j], a.liveEntry(bs[j].Key) != nil)
+			changed = true
+			j++
+			continue
+		}
+		if reflect.DeepEqual(as[i].Value, bs[j].Value) {
+			line(' ', bs[j], false)
+		} else {
+			line('-', as[i], false)
+			line('+', bs[j], false)
+			changed = true
+		}
+		i++
+		j++
+	}
+	if !changed {
+		return ""
+	}
+
+	show := make([]bool, len(lines))
+	for i, l := range lines {
+		if l.mark == ' ' {
+			continue
+		}
+		for k := i - diffContext; k <= i+diffContext; k++ {
+			if k >= 0 && k < len(lines) {
+				show[k] = true
+			}
+		}
+	}
+
+	var buf strings.Builder
+	skipped := false
+	for i, l := range lines {
+		if !show[i] {
+			if !skipped {
+				buf.WriteString("...\n")
+				skipped = true
+			}
+			continue
+		}
+		skipped = false
+		switch {
+		case color && l.mark == '-':
+			buf.WriteString(ansiRed + "-" + l.text + ansiReset + "\n")
+		case color && l.mark == '+':
+			buf.WriteString(ansiGreen + "+" + l.text + ansiReset + "\n")
+		default:
+			buf.WriteByte(l.mark)
+			buf.WriteString(l.text + "\n")
+		}
+	}
+	return buf.String()
+}
+
+// stableDiffKeys is a function which returns the keys in both maps which are
+// not moved, that is, a longest sequence of the keys in the same relative
+// order in both maps. Because keys are unique, this is the longest increasing
+// subsequence of the positions in a of the keys in the order of b.
+func stableDiffKeys[K comparable, V any](as []Pair[K, V], b *Map[K, V], bs []Pair[K, V]) map[K]bool {
+	pos := make(map[K]int, len(as))
+	for i, p := range as {
+		if b.liveEntry(p.Key) != nil {
+			pos[p.Key] = i
+		}
+	}
+	var seq []int // positions in a of the common keys in the order of b
+	for _, p := range bs {
+		if i, ok := pos[p.Key]; ok {
+			seq = append(seq, i)
+		}
+	}
+
+	// tails[n] is the index in seq of the smallest tail of increasing
+	// subsequences of length n+1, and prev links the subsequences.
+	var tails []int
+	prev := make([]int, len(seq))
+	for i, x := range seq {
+		n := sort.Search(len(tails), func(k int) bool { return seq[tails[k]] >= x })
+		if n > 0 {
+			prev[i] = tails[n-1]
+		} else {
+			prev[i] = -1
+		}
+		if n == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[n] = i
+		}
+	}
+
+	stable := make(map[K]bool, len(tails))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+			stable[as[seq[i]].Key] = true
+		}
+	}
+	return stable
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// dirtyKeys is a struct which holds keys mutated since the last checkpoint,
+// in the order of their first mutation.
+type dirtyKeys[K comparable] struct {
+	set  map[K]struct{}
+	keys []K
+}
+
+func (d *dirtyKeys[K]) add(key K) {
+	if _, exists := d.set[key]; exists {
+		return
+	}
+	d.set[key] = struct{}{}
+	d.keys = append(d.keys, key)
+}
+
+// Checkpoint is a method which starts tracking keys mutated in this map, or
+// resets the tracked keys if tracking has been already started.
+// A key is tracked when its entry is inserted, updated, or deleted.
+func (om *Map[K, V]) Checkpoint() {
+	if om.ext == nil {
+		om.ext = &extension[K, V]{}
+	}
+	om.ext.dirty = &dirtyKeys[K]{set: make(map[K]struct{})}
+}
+
+// DirtyKeys is a method which returns keys mutated since the last call of
+// Checkpoint, in the order of their first mutation.
+// If Checkpoint has never been called, this method returns nil.
+func (om *Map[K, V]) DirtyKeys() []K {
+	if om == nil || om.ext == nil || om.ext.dirty == nil {
+		return nil
+	}
+	keys := make([]K, len(om.ext.dirty.keys))
+	copy(keys, om.ext.dirty.keys)
+	return keys
+}
+
+// touched is a method which tracks a mutated key. This must be called only
+// when om.ext is not nil.
+func (om *Map[K, V]) touched(key K) {
+	if om.ext.dirty != nil {
+		om.ext.dirty.add(key)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// Equal is a function which reports whether the specified maps have the same
+// keys with the same values in the same order. Values are compared with ==.
+// A nil map is equal to an empty map.
+// This is a function, not a method, because it needs comparable values.
+func Equal[K comparable, V comparable](a, b *Map[K, V]) bool {
+	return EqualFunc(a, b, func(x, y V) bool { return x == y })
+}
+
+// EqualFunc is a function which reports whether the specified maps have the
+// same keys in the same order, and eq returns true for the values of each
+// key.
+func EqualFunc[K comparable, V1 any, V2 any](a *Map[K, V1], b *Map[K, V2], eq func(V1, V2) bool) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	ea, eb := a.Front(), b.Front()
+	for ea != nil && eb != nil {
+		if ea.key != eb.key || !eq(ea.value, eb.value) {
+			return false
+		}
+		ea, eb = ea.next, eb.next
+	}
+	return ea == nil && eb == nil
+}
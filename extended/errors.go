@@ -0,0 +1,90 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors of this package and its subpackages wrap the following sentinel
+// errors, so that they can be checked with errors.Is regardless of where
+// they are returned.
+var (
+	// ErrKeyNotFound is an error which is wrapped by errors reporting that a
+	// key is not in a map, e.g. MissingKeyError.
+	ErrKeyNotFound = errors.New("orderedmap: key not found")
+
+	// ErrClosed is an error which is wrapped by errors returned by a map or a
+	// backend used after it was closed.
+	ErrClosed = errors.New("orderedmap: closed")
+
+	// ErrLimitExceeded is an error which is wrapped by errors reporting that
+	// an input exceeds a configured limit.
+	ErrLimitExceeded = errors.New("orderedmap: limit exceeded")
+
+	// ErrRateLimited is an error which is returned by mutations exceeding the
+	// rate set by WithRateLimit. It wraps ErrLimitExceeded.
+	ErrRateLimited = fmt.Errorf("orderedmap: rate limited: %w", ErrLimitExceeded)
+)
+
+// KeyError is an error type which wraps an error returned by a loader, a
+// writer, a fetch function or a file operation for a key.
+// Op is the name of the operation, e.g. "load", "store" or "fetch".
+type KeyError struct {
+	Op  string
+	Key any
+	Err error
+}
+
+func (err *KeyError) Error() string {
+	return fmt.Sprintf("orderedmap: %s %v: %v", err.Op, err.Key, err.Err)
+}
+
+func (err *KeyError) Unwrap() error {
+	return err.Err
+}
+
+// ErrorMap is an error type which holds errors keyed by where they occurred,
+// e.g. JSON pointers, in the order they were added.
+// The zero value is an empty ErrorMap ready to use. Errors for a key which
+// already has an error are joined with errors.Join.
+type ErrorMap[K comparable] struct {
+	Map[K, error]
+}
+
+// Add is a method which adds an error for a key.
+func (err *ErrorMap[K]) Add(key K, e error) {
+	if prev, exists := err.Load(key); exists {
+		e = errors.Join(prev, e)
+	}
+	err.Store(key, e)
+}
+
+func (err *ErrorMap[K]) Error() string {
+	var buf strings.Builder
+	buf.WriteString("orderedmap: ")
+	err.Range(func(key K, e error) bool {
+		if buf.Len() > len("orderedmap: ") {
+			buf.WriteString("; ")
+		}
+		buf.WriteString(fmt.Sprintf("%#v: ", key))
+		buf.WriteString(strings.ReplaceAll(e.Error(), "\n", "; "))
+		return true
+	})
+	return buf.String()
+}
+
+// Unwrap is a method which returns the errors in this map in order, so that
+// errors.Is and errors.As look into them.
+func (err *ErrorMap[K]) Unwrap() []error {
+	errs := make([]error, 0, err.Len())
+	err.Range(func(_ K, e error) bool {
+		errs = append(errs, e)
+		return true
+	})
+	return errs
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// EvictReason is a type which represents why an entry was evicted.
+type EvictReason int
+
+const (
+	// EvictMaxLen means that an entry was evicted because the number of
+	// entries exceeded the cap set by WithMaxLen.
+	EvictMaxLen EvictReason = iota + 1
+
+	// EvictClear means that an entry was removed by Clear.
+	EvictClear
+
+	// EvictMaxWeight means that an entry was evicted because the total weight
+	// of entries exceeded the budget set by WithMaxWeight.
+	EvictMaxWeight
+
+	// EvictPrefixQuota means that an entry was evicted because the number of
+	// entries of its key prefix exceeded the quota set by WithPrefixQuota.
+	EvictPrefixQuota
+
+	// EvictExpired means that an entry was removed because its time to live
+	// set by StoreWithTTL had passed.
+	EvictExpired
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictMaxLen:
+		return "MaxLen"
+	case EvictClear:
+		return "Clear"
+	case EvictMaxWeight:
+		return "MaxWeight"
+	case EvictPrefixQuota:
+		return "PrefixQuota"
+	case EvictExpired:
+		return "Expired"
+	default:
+		return "Unknown"
+	}
+}
+
+func (om *Map[K, V]) evictOverweight() {
+	if om.ext.weigh == nil {
+		return
+	}
+	for om.ext.weight > om.ext.maxWeight && om.head != nil {
+		om.evictFront(EvictMaxWeight)
+	}
+}
+
+// evictFront is a method which deletes the first entry and calls the eviction
+// callback with it.
+func (om *Map[K, V]) evictFront(reason EvictReason) {
+	om.evict(om.head, reason)
+}
+
+// evict is a method which deletes an entry and calls the eviction callback
+// with it.
+func (om *Map[K, V]) evict(ent *Entry[K, V], reason EvictReason) {
+	delete(om.m, ent.key)
+	if om.ext.trace != nil {
+		om.ext.trace.pending = TraceEvict
+	}
+	om.unlink(ent)
+	if om.ext.onEvict != nil {
+		om.ext.onEvict(om.context(), ent.key, ent.value, reason)
+	}
+}
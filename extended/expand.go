@@ -0,0 +1,131 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// MissingKeyPolicy is a type which specifies how Expand handles a placeholder
+// whose key is not in a map.
+type MissingKeyPolicy int
+
+const (
+	// MissingKeyKeep keeps the placeholder as it is.
+	MissingKeyKeep MissingKeyPolicy = iota
+
+	// MissingKeyEmpty replaces the placeholder with an empty string.
+	MissingKeyEmpty
+
+	// MissingKeyFail makes ExpandWith return a MissingKeyError.
+	MissingKeyFail
+)
+
+// MissingKeyError is an error type which is returned by ExpandWith when a
+// placeholder's key is not in a map and the policy is MissingKeyFail.
+type MissingKeyError struct {
+	Key string
+}
+
+func (err MissingKeyError) Error() string {
+	return "orderedmap: missing key for ${" + err.Key + "}"
+}
+
+// Is is a method which reports whether this error matches ErrKeyNotFound.
+func (err MissingKeyError) Is(target error) bool {
+	return target == ErrKeyNotFound
+}
+
+// Expand is a function which replaces ${key} placeholders in s with values of
+// the specified map. Placeholders whose keys are not in the map are kept.
+func Expand[V any](om *Map[string, V], s string) string {
+	str, _ := ExpandWith(om, s, MissingKeyKeep)
+	return str
+}
+
+// ExpandWith is a function which replaces ${key} placeholders in s with
+// values of the specified map, handling missing keys by the specified policy.
+// Values which are not strings are formatted with fmt.Sprint.
+func ExpandWith[V any](
+	om *Map[string, V],
+	s string,
+	policy MissingKeyPolicy,
+) (string, error) {
+	var buf strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(s[i+2:], '}')
+		if j < 0 {
+			break
+		}
+		buf.WriteString(s[:i])
+
+		key := s[i+2 : i+2+j]
+		placeholder := s[i : i+3+j]
+		s = s[i+3+j:]
+
+		v, ok := om.Load(key)
+		if ok {
+			buf.WriteString(formatValue(v))
+			continue
+		}
+		switch policy {
+		case MissingKeyEmpty:
+		case MissingKeyFail:
+			return "", MissingKeyError{Key: key}
+		default:
+			buf.WriteString(placeholder)
+		}
+	}
+	buf.WriteString(s)
+	return buf.String(), nil
+}
+
+// Mapping is a function which returns a mapping function for os.Expand,
+// which returns the formatted value for a key or an empty string.
+func Mapping[V any](om *Map[string, V]) func(string) string {
+	return func(key string) string {
+		v, ok := om.Load(key)
+		if !ok {
+			return ""
+		}
+		return formatValue(v)
+	}
+}
+
+// FuncMap is a function which returns functions for text/template, which
+// expand placeholders and look up values with the specified map:
+//
+//   - expand replaces ${key} placeholders in a string like Expand.
+//   - expandStrict is same with expand, but fails with a MissingKeyError for
+//     a missing key like ExpandWith with MissingKeyFail, which stops the
+//     execution of the template.
+//   - lookup returns the formatted value for a key like the function returned
+//     by Mapping.
+//
+// The result can be converted to html/template.FuncMap.
+func FuncMap[V any](om *Map[string, V]) template.FuncMap {
+	return template.FuncMap{
+		"expand": func(s string) string {
+			return Expand(om, s)
+		},
+		"expandStrict": func(s string) (string, error) {
+			return ExpandWith(om, s, MissingKeyFail)
+		},
+		"lookup": Mapping(om),
+	}
+}
+
+func formatValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// FieldOptions is a struct which holds options to encode the value of a key
+// into JSON, like the options of the json tags of struct fields.
+//
+// OmitIfZero omits the entry if its value is the zero value of its type.
+// StringifyNumbers writes a number or a boolean value as a JSON string, like
+// the ",string" option of a json tag.
+// RawOutput writes a string, []byte or json.RawMessage value as JSON text
+// as it is; the value must be valid JSON.
+type FieldOptions struct {
+	OmitIfZero       bool
+	StringifyNumbers bool
+	RawOutput        bool
+}
+
+// SetFieldOptions is a method which sets the options to encode the value of
+// the specified key by MarshalJSON and EncodeJSON. The options are kept even
+// if the key is deleted. The zero FieldOptions removes the options of the
+// key.
+func (om *Map[K, V]) SetFieldOptions(key K, opts FieldOptions) {
+	if opts == (FieldOptions{}) {
+		if om.ext != nil {
+			delete(om.ext.fieldOptions, key)
+		}
+		return
+	}
+	if om.ext == nil {
+		om.ext = &extension[K, V]{}
+	}
+	if om.ext.fieldOptions == nil {
+		om.ext.fieldOptions = make(map[K]FieldOptions)
+	}
+	om.ext.fieldOptions[key] = opts
+}
+
+// fieldOptionsOf is a method which returns the field options of a key.
+func (om *Map[K, V]) fieldOptionsOf(key K) (FieldOptions, bool) {
+	if om.ext == nil || om.ext.fieldOptions == nil {
+		return FieldOptions{}, false
+	}
+	opts, ok := om.ext.fieldOptions[key]
+	return opts, ok
+}
+
+// omitsField is a function which reports whether an entry is omitted by its
+// field options.
+func omitsField[V any](opts FieldOptions, val V) bool {
+	if !opts.OmitIfZero {
+		return false
+	}
+	rv := reflect.ValueOf(&val).Elem()
+	if rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return true
+		}
+		rv = rv.Elem()
+	}
+	return rv.IsZero()
+}
+
+// addJsonFieldValue is a function which writes a value of an entry
+// following its field options.
+func addJsonFieldValue[K comparable, V any](buf *bytes.Buffer, key K, val V, opts FieldOptions) error {
+	if opts.RawOutput {
+		var raw []byte
+		switch v := any(val).(type) {
+		case string:
+			raw = []byte(v)
+		case []byte:
+			raw = v
+		case json.RawMessage:
+			raw = v
+		default:
+			return fmt.Errorf("orderedmap: raw output of key %v needs a string or bytes, but got %T", key, val)
+		}
+		if !json.Valid(raw) {
+			return fmt.Errorf("orderedmap: raw output of key %v is not valid JSON", key)
+		}
+		buf.Write(raw)
+		return nil
+	}
+
+	bs, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	if opts.StringifyNumbers && len(bs) > 0 && bs[0] != '"' && bs[0] != '{' &&
+		bs[0] != '[' && bs[0] != 'n' {
+		buf.WriteByte('"')
+		buf.Write(bs)
+		buf.WriteByte('"')
+		return nil
+	}
+	buf.Write(bs)
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RegisterFlags is a function which registers entries of the specified map
+// whose values are strings, ints or bools as flags of the specified flag set.
+// The name of a flag is the key of an entry with the prefix, its default is
+// the value of the entry, and its parsed value is stored back into the map.
+// This also sets fs.Usage to print the registered flags in the order of the
+// map, followed by other flags of the flag set in lexicographical order.
+// If the value of an entry has another type, this function returns an error
+// without registering any flags.
+func RegisterFlags[V any](om *Map[string, V], fs *flag.FlagSet, prefix string) error {
+	var flags []*entryFlag[V]
+	var err error
+	om.Range(func(key string, value V) bool {
+		switch any(value).(type) {
+		case string, int, bool:
+		default:
+			err = fmt.Errorf("orderedmap: cannot register %q of type %T as a flag", key, value)
+			return false
+		}
+		flags = append(flags, &entryFlag[V]{om: om, key: key})
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, len(flags))
+	registered := make(map[string]bool, len(flags))
+	for i, f := range flags {
+		names[i] = prefix + f.key
+		registered[names[i]] = true
+		fs.Var(f, names[i], "")
+	}
+
+	fs.Usage = func() {
+		out := fs.Output()
+		if fs.Name() == "" {
+			fmt.Fprintf(out, "Usage:\n")
+		} else {
+			fmt.Fprintf(out, "Usage of %s:\n", fs.Name())
+		}
+		for _, name := range names {
+			printFlagDefault(fs, fs.Lookup(name))
+		}
+		fs.VisitAll(func(f *flag.Flag) {
+			if !registered[f.Name] {
+				printFlagDefault(fs, f)
+			}
+		})
+	}
+	return nil
+}
+
+// entryFlag is a struct which is a flag.Value bound to an entry of a map.
+type entryFlag[V any] struct {
+	om  *Map[string, V]
+	key string
+}
+
+func (f *entryFlag[V]) String() string {
+	if f == nil || f.om == nil {
+		return ""
+	}
+	v, _ := f.om.Load(f.key)
+	return fmt.Sprint(v)
+}
+
+func (f *entryFlag[V]) Set(s string) error {
+	v, _ := f.om.Load(f.key)
+	var parsed any
+	switch any(v).(type) {
+	case int:
+		n, err := strconv.ParseInt(s, 0, strconv.IntSize)
+		if err != nil {
+			return err
+		}
+		parsed = int(n)
+	case bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		parsed = b
+	default:
+		parsed = s
+	}
+	f.om.Store(f.key, parsed.(V))
+	return nil
+}
+
+// IsBoolFlag is a method which reports whether this flag can be specified
+// without a value, for the flag package.
+func (f *entryFlag[V]) IsBoolFlag() bool {
+	v, _ := f.om.Load(f.key)
+	_, ok := any(v).(bool)
+	return ok
+}
+
+func (f *entryFlag[V]) typeName() string {
+	v, _ := f.om.Load(f.key)
+	switch any(v).(type) {
+	case int:
+		return "int"
+	case bool:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// printFlagDefault is a function which prints the usage of a flag in the same
+// format as flag.PrintDefaults.
+func printFlagDefault(fs *flag.FlagSet, f *flag.Flag) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  -%s", f.Name)
+	name, usage := flag.UnquoteUsage(f)
+	if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+		name = ""
+	} else if ef, ok := f.Value.(interface{ typeName() string }); ok {
+		name = ef.typeName()
+	}
+	if len(name) > 0 {
+		b.WriteString(" ")
+		b.WriteString(name)
+	}
+	if b.Len() <= 4 {
+		b.WriteString("\t")
+	} else {
+		b.WriteString("\n    \t")
+	}
+	b.WriteString(strings.ReplaceAll(usage, "\n", "\n    \t"))
+	if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "0" {
+		if name == "string" {
+			fmt.Fprintf(&b, " (default %q)", f.DefValue)
+		} else {
+			fmt.Fprintf(&b, " (default %v)", f.DefValue)
+		}
+	}
+	fmt.Fprint(fs.Output(), b.String(), "\n")
+}
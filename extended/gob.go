@@ -0,0 +1,75 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"encoding/gob"
+	"time"
+)
+
+// GobEncode is a method which encodes this map for encoding/gob.
+// The number of entries is followed by the keys and values in the order of
+// key insertions. If the key or value type is an interface type, the concrete
+// types must be registered with gob.Register.
+// Expired entries and states of this map are not encoded, as MarshalJSON.
+func (om Map[K, V]) GobEncode() ([]byte, error) {
+	return om.gobEncodeIf(nil)
+}
+
+// gobEncodeIf is a method which encodes the entries of this map for which
+// include returns true, or all entries if include is nil.
+func (om *Map[K, V]) gobEncodeIf(include func(key K, value V) bool) ([]byte, error) {
+	now := time.Now()
+	skip := func(ent *Entry[K, V]) bool {
+		return ent.expiredAt(now) || (include != nil && !include(ent.key, ent.value))
+	}
+	n := 0
+	for ent := om.Front(); ent != nil; ent = ent.Next() {
+		if !skip(ent) {
+			n++
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	if err := enc.Encode(n); err != nil {
+		return nil, err
+	}
+	for ent := om.Front(); ent != nil; ent = ent.Next() {
+		if skip(ent) {
+			continue
+		}
+		if err := enc.Encode(&ent.key); err != nil {
+			return nil, err
+		}
+		if err := enc.Encode(&ent.value); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode is a method which sets the content of this map from data encoded
+// by GobEncode, in the order of the encoded entries.
+func (om *Map[K, V]) GobDecode(data []byte) error {
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	var n int
+	if err := dec.Decode(&n); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		var key K
+		var val V
+		if err := dec.Decode(&key); err != nil {
+			return err
+		}
+		if err := dec.Decode(&val); err != nil {
+			return err
+		}
+		om.put(key, val)
+	}
+	return nil
+}
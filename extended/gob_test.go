@@ -0,0 +1,56 @@
+package extended_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+func TestMap_GobEncode(t *testing.T) {
+	type Msg struct {
+		ID    int
+		Attrs orderedmap.Map[string, Foo]
+	}
+
+	msg := Msg{ID: 1, Attrs: orderedmap.New[string, Foo]()}
+	msg.Attrs.Store("z", Foo{Bar: "a", Baz: 1})
+	msg.Attrs.Store("a", Foo{Bar: "b", Baz: 2})
+	msg.Attrs.Store("m", Foo{Bar: "c", Baz: 3})
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(msg); err != nil {
+		t.Fatal(err)
+	}
+	var got Msg
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != 1 || got.Attrs.String() != msg.Attrs.String() {
+		t.Errorf("decoded = %d %v", got.ID, got.Attrs)
+	}
+}
+
+func TestMap_GobEncode_any(t *testing.T) {
+	nested := orderedmap.New[string, any]()
+	nested.Store("y", 1)
+	om := orderedmap.New[int, any]()
+	om.Store(3, "c")
+	om.Store(1, &nested)
+	om.Store(2, nil)
+
+	gob.Register(&nested)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(om); err != nil {
+		t.Fatal(err)
+	}
+	got := orderedmap.New[int, any]()
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != "Map[3:c 1:Map[y:1] 2:<nil>]" {
+		t.Errorf("decoded = %v", got)
+	}
+}
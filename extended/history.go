@@ -0,0 +1,54 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"time"
+)
+
+// Versioned is a struct which holds a previous value of a key kept by
+// WithHistory, and the time when the value was replaced.
+type Versioned[V any] struct {
+	Value      V
+	ReplacedAt time.Time
+}
+
+// WithHistory is a function which returns an option to keep up to n previous
+// values per key, which are returned by History.
+// A value is kept when it is replaced by Store, Swap or the package functions
+// which update a value in place. The previous values of a key are discarded
+// when its entry is deleted, logically deleted, evicted or cleared.
+// If n is zero or less, no value is kept.
+func WithHistory(n int) Option {
+	return func(o *options) {
+		o.historyLen = n
+	}
+}
+
+// History is a method which returns the previous values of a key, from the
+// oldest to the newest, which do not include the current value.
+// If this map is not created with WithHistory or the value of the key has
+// never been replaced, this method returns nil.
+func (om *Map[K, V]) History(key K) []Versioned[V] {
+	if om == nil || om.ext == nil || om.ext.history == nil {
+		return nil
+	}
+	vers := om.ext.history[key]
+	if len(vers) == 0 {
+		return nil
+	}
+	return append([]Versioned[V](nil), vers...)
+}
+
+// keepHistory is a method which keeps a replaced value of an entry.
+// This must be called only when om.ext.history is not nil.
+func (om *Map[K, V]) keepHistory(key K, old V) {
+	vers := om.ext.history[key]
+	if len(vers) == om.ext.historyLen {
+		copy(vers, vers[1:])
+		vers = vers[:len(vers)-1]
+	}
+	om.ext.history[key] = append(vers, Versioned[V]{Value: old, ReplacedAt: time.Now()})
+}
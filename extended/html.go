@@ -0,0 +1,50 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bufio"
+	"html"
+	"io"
+)
+
+// WriteHTMLTable is a method which writes keys and values of this map to the
+// specified writer as an HTML <table> of two columns, in the order of key
+// insertions. Keys, values and headers are HTML-escaped.
+// The Markdown field of the options is ignored.
+func (om *Map[K, V]) WriteHTMLTable(w io.Writer, opts TableOptions) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("<table>\n")
+	if !opts.NoHeader {
+		kh, vh := opts.headers()
+		bw.WriteString("<thead><tr><th>" + html.EscapeString(kh) + "</th><th>" +
+			html.EscapeString(vh) + "</th></tr></thead>\n")
+	}
+	bw.WriteString("<tbody>\n")
+	om.Range(func(key K, value V) bool {
+		bw.WriteString("<tr><td>" + html.EscapeString(opts.format(key)) + "</td><td>" +
+			html.EscapeString(opts.format(value)) + "</td></tr>\n")
+		return true
+	})
+	bw.WriteString("</tbody>\n</table>\n")
+	return bw.Flush()
+}
+
+// WriteHTMLDefinitionList is a method which writes keys and values of this
+// map to the specified writer as an HTML <dl>, where keys are <dt> and values
+// are <dd>, in the order of key insertions. Keys and values are
+// HTML-escaped.
+// Only the Format field of the options is used.
+func (om *Map[K, V]) WriteHTMLDefinitionList(w io.Writer, opts TableOptions) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("<dl>\n")
+	om.Range(func(key K, value V) bool {
+		bw.WriteString("<dt>" + html.EscapeString(opts.format(key)) + "</dt><dd>" +
+			html.EscapeString(opts.format(value)) + "</dd>\n")
+		return true
+	})
+	bw.WriteString("</dl>\n")
+	return bw.Flush()
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// ReadOnly is an interface of the read operations of an ordered map.
+// Map, SyncMap, View and ChainView implement this interface.
+// Range must call fn in the order of key insertions and stop when fn returns
+// false.
+type ReadOnly[K comparable, V any] interface {
+	Len() int
+	Load(key K) (value V, ok bool)
+	Range(fn func(key K, value V) bool)
+}
+
+// Interface is an interface of the core operations of an in-memory ordered
+// map, which Map and SyncMap implement.
+// Application code can depend on this interface and tests can replace it
+// with a lightweight fake.
+type Interface[K comparable, V any] interface {
+	ReadOnly[K, V]
+	Store(key K, value V)
+	Delete(key K)
+}
+
+// Fallible is an interface of the core operations of an ordered map whose
+// entries are held outside of memory and whose operations can fail, which
+// BackedMap and TieredMap implement.
+type Fallible[K comparable, V any] interface {
+	Len() (int, error)
+	Load(key K) (value V, ok bool, err error)
+	Store(key K, value V) error
+	Delete(key K) error
+	Range(fn func(key K, value V) bool) error
+}
+
+var (
+	_ Interface[string, any] = (*Map[string, any])(nil)
+	_ Interface[string, any] = (*SyncMap[string, any])(nil)
+	_ ReadOnly[string, any]  = View[string, any]{}
+	_ ReadOnly[string, any]  = ChainView[string, any]{}
+	_ Fallible[string, any]  = (*BackedMap[string, any])(nil)
+	_ Fallible[string, any]  = (*TieredMap[string, any])(nil)
+)
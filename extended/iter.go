@@ -0,0 +1,83 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+//go:build go1.23
+
+package extended
+
+import (
+	"iter"
+)
+
+// All is a method which returns an iterator over keys and values of this map
+// in the order of key insertions, for range-over-func loops:
+//
+//	for k, v := range om.All() {
+//		...
+//	}
+//
+// The iterator behaves same with Range.
+func (om *Map[K, V]) All() iter.Seq2[K, V] {
+	return om.Range
+}
+
+// Keys is a method which returns an iterator over keys of this map in the
+// order of key insertions.
+func (om *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		om.Range(func(key K, _ V) bool {
+			return yield(key)
+		})
+	}
+}
+
+// Values is a method which returns an iterator over values of this map in
+// the order of key insertions.
+func (om *Map[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		om.Range(func(_ K, value V) bool {
+			return yield(value)
+		})
+	}
+}
+
+// Backward is a method which returns an iterator over keys and values of this
+// map in the reverse order of key insertions, from the last entry to the
+// head entry, like walking with Back and Entry.Prev:
+//
+//	for k, v := range om.Backward() {
+//		...
+//	}
+func (om *Map[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if om == nil {
+			return
+		}
+		om.debugValidate()
+		for entry := om.last; entry != nil; entry = entry.prev {
+			if !yield(entry.key, entry.value) {
+				return
+			}
+		}
+	}
+}
+
+// Rows is a function which returns an iterator over rows of the specified
+// maps, for range-over-func loops:
+//
+//	for row := range orderedmap.Rows(docs, columns, orderedmap.RowFillZero) {
+//		...
+//	}
+//
+// The iterator behaves same with EachRow, so a row must not be retained after
+// the loop body for it ends.
+func Rows[K comparable, V any](
+	maps []*Map[K, V],
+	schemaKeys []K,
+	policy RowFillPolicy,
+) iter.Seq[[]V] {
+	return func(yield func([]V) bool) {
+		EachRow(maps, schemaKeys, policy, yield)
+	}
+}
@@ -0,0 +1,134 @@
+//go:build go1.23
+
+package extended_test
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+func TestMap_All(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("c", 1)
+	om.Store("a", 2)
+	om.Store("b", 3)
+
+	s := ""
+	for k, v := range om.All() {
+		s += k + ":" + strconv.Itoa(v) + " "
+		if k == "a" {
+			break
+		}
+	}
+	if s != "c:1 a:2 " {
+		t.Errorf("All = %s", s)
+	}
+
+	if keys := slices.Collect(om.Keys()); !slices.Equal(keys, []string{"c", "a", "b"}) {
+		t.Errorf("Keys = %v", keys)
+	}
+	if values := slices.Collect(om.Values()); !slices.Equal(values, []int{1, 2, 3}) {
+		t.Errorf("Values = %v", values)
+	}
+	if m := maps.Collect(om.All()); len(m) != 3 || m["b"] != 3 {
+		t.Errorf("maps.Collect = %v", m)
+	}
+
+	var nilMap *orderedmap.Map[string, int]
+	for range nilMap.All() {
+		t.Errorf("All of a nil map yielded an entry")
+	}
+}
+
+func TestMap_Backward(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("c", 1)
+	om.Store("a", 2)
+	om.Store("b", 3)
+
+	s := ""
+	for k, v := range om.Backward() {
+		s += k + ":" + strconv.Itoa(v) + " "
+	}
+	if s != "b:3 a:2 c:1 " {
+		t.Errorf("Backward = %s", s)
+	}
+
+	s = ""
+	for k := range om.Backward() {
+		s += k
+		if k == "a" {
+			break
+		}
+	}
+	if s != "ba" {
+		t.Errorf("Backward with break = %s", s)
+	}
+
+	om.Store("c", 4)
+	om.Delete("b")
+	s = ""
+	for ent := om.Back(); ent != nil; ent = ent.Prev() {
+		s += ent.Key()
+	}
+	for k := range om.Backward() {
+		s += k
+	}
+	if s != "acac" {
+		t.Errorf("Backward after updates = %s", s)
+	}
+
+	var nilMap *orderedmap.Map[string, int]
+	for range nilMap.Backward() {
+		t.Errorf("Backward of a nil map yielded an entry")
+	}
+}
+
+func TestRows(t *testing.T) {
+	doc1 := orderedmap.New[string, any]()
+	doc1.Store("name", "x")
+	doc1.Store("age", 1)
+	doc2 := orderedmap.New[string, any]()
+	doc2.Store("age", 2)
+	doc2.Store("city", "p")
+	doc2.Store("name", "y")
+	doc3 := orderedmap.New[string, any]()
+	doc3.Store("name", "z")
+	docs := []*orderedmap.Map[string, any]{&doc1, &doc2, &doc3, nil}
+	schema := []string{"name", "age"}
+
+	var rows [][]any
+	for row := range orderedmap.Rows(docs, schema, orderedmap.RowFillZero) {
+		rows = append(rows, slices.Clone(row))
+	}
+	want := [][]any{{"x", 1}, {"y", 2}, {"z", nil}, {nil, nil}}
+	if len(rows) != len(want) {
+		t.Fatalf("Rows = %v", rows)
+	}
+	for i := range want {
+		if !slices.Equal(rows[i], want[i]) {
+			t.Errorf("row %d = %v", i, rows[i])
+		}
+	}
+
+	rows = rows[:0]
+	for row := range orderedmap.Rows(docs, schema, orderedmap.RowSkipIncomplete) {
+		rows = append(rows, slices.Clone(row))
+		break
+	}
+	if len(rows) != 1 || !slices.Equal(rows[0], []any{"x", 1}) {
+		t.Errorf("Rows with RowSkipIncomplete = %v", rows)
+	}
+
+	n := 0
+	for range orderedmap.Rows(docs, schema, orderedmap.RowSkipIncomplete) {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("Rows with RowSkipIncomplete yielded %d rows", n)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// JanitorOptions is a struct which holds options of a janitor started by
+// SyncMap#StartJanitor.
+//
+// Interval is the time between sweeps, and must be positive. If Jitter is
+// positive, a random duration up to Jitter is added to each interval, so that
+// janitors of many maps do not sweep at the same time.
+//
+// BatchSize is the maximum number of entries visited while the map is
+// locked. A sweep releases the lock after each batch, so that other
+// goroutines are not blocked during a sweep of a large map. If BatchSize is
+// zero or less, a sweep visits all entries at once.
+type JanitorOptions struct {
+	Interval  time.Duration
+	Jitter    time.Duration
+	BatchSize int
+}
+
+func (opts JanitorOptions) delay() time.Duration {
+	if opts.Jitter <= 0 {
+		return opts.Interval
+	}
+	return opts.Interval + time.Duration(rand.Int63n(int64(opts.Jitter)+1))
+}
+
+// StartJanitor is a method which starts a goroutine which removes expired
+// entries at the interval specified by the options, and returns a function
+// to stop it.
+// The goroutine is also stopped by Close, and this method returns ErrClosed
+// after Close.
+// An entry moved during a batched sweep may be skipped until the next sweep.
+func (sm *SyncMap[K, V]) StartJanitor(opts JanitorOptions) (stop func(), err error) {
+	if opts.Interval <= 0 {
+		panic("orderedmap: the janitor interval is not positive")
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.closed {
+		return nil, ErrClosed
+	}
+	if sm.done == nil {
+		sm.done = make(chan struct{})
+	}
+
+	stopped := make(chan struct{})
+	sm.janitors.Add(1)
+	go func(done <-chan struct{}) {
+		defer sm.janitors.Done()
+		timer := time.NewTimer(opts.delay())
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C:
+				sm.sweep(opts.BatchSize)
+				timer.Reset(opts.delay())
+			case <-stopped:
+				return
+			case <-done:
+				return
+			}
+		}
+	}(sm.done)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stopped)
+		})
+	}, nil
+}
+
+// sweep is a method which removes expired entries in batches of the
+// specified size, and returns the number of removed entries.
+func (sm *SyncMap[K, V]) sweep(batch int) int {
+	now := time.Now()
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	total := 0
+	ent := sm.om.head
+	for !sm.closed {
+		n, resume := sm.om.purgeFrom(ent, now, batch)
+		total += n
+		sm.reclaimed += uint64(n)
+		if resume == nil {
+			break
+		}
+		sm.mu.Unlock()
+		sm.mu.Lock()
+		if !sm.om.linked(resume) {
+			break
+		}
+		ent = resume
+	}
+	return total
+}
+
+// Reclaimed is a method which returns the number of expired entries removed
+// by PurgeExpired and janitors of this map.
+// Expired entries removed lazily by Load and other methods are not counted.
+func (sm *SyncMap[K, V]) Reclaimed() uint64 {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.reclaimed
+}
+
+// Close is a method which stops the janitors of this map and waits until
+// they exit. The entries of this map are kept and can still be used.
+// Calling this method again after the first call does nothing and returns nil.
+func (sm *SyncMap[K, V]) Close() error {
+	sm.mu.Lock()
+	if sm.closed {
+		sm.mu.Unlock()
+		return nil
+	}
+	sm.closed = true
+	if sm.done != nil {
+		close(sm.done)
+	}
+	sm.mu.Unlock()
+
+	sm.janitors.Wait()
+	return nil
+}
@@ -0,0 +1,636 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// MarshalJSON returns a byte array of JSON string which expresses the content
+// of this map.
+//
+// The output is deterministic: for the same map it is byte-identical on every
+// OS, architecture and supported Go version. Entries are written in the order
+// of this map without spaces, keys are written in the text forms of their
+// types (floats in the shortest form by strconv.FormatFloat with 'g'), and
+// values are encoded by encoding/json, which also sorts keys of Go maps.
+// This is checked by testdata/marshal_golden.json.
+//
+// Entries which have expired by StoreWithTTL but are not removed yet are
+// skipped. Expiration times, options and other states of this map are not
+// encoded, so entries decoded by UnmarshalJSON never expire, and they are
+// stored with Store, so caps of the decoding map like WithMaxLen apply as
+// usual, e.g. a map created by NewLRU keeps only the last entries.
+// The other encodings (GobEncode, and MarshalJSON and GobEncode of SyncMap,
+// View and ChainView) follow the same rules.
+func (om Map[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	err := om.encodeJSON(&buf, nil)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeChunkSize is the size of a chunk which EncodeJSON writes at once.
+const encodeChunkSize = 32 * 1024
+
+// EncodeJSON is a method which writes the JSON string which expresses the
+// content of this map to the specified writer. The output is same with
+// MarshalJSON, but it is written in chunks of about 32KiB while entries are
+// encoded, so the whole output is not held in memory.
+// If an error occurs, a part of the output may have been written.
+func (om *Map[K, V]) EncodeJSON(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.Grow(encodeChunkSize)
+	flush := func() error {
+		_, err := w.Write(buf.Bytes())
+		buf.Reset()
+		return err
+	}
+	err := om.encodeJSON(&buf, flush)
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// encodeJSON is a method which writes the JSON of this map to the buffer.
+// If flush is not nil, it is called whenever the buffer exceeds
+// encodeChunkSize.
+func (om *Map[K, V]) encodeJSON(buf *bytes.Buffer, flush func() error) error {
+	return om.encodeJSONIf(buf, flush, nil)
+}
+
+// encodeJSONIf is a method which writes the JSON of the entries of this map
+// for which include returns true, or of all entries if include is nil.
+func (om *Map[K, V]) encodeJSONIf(
+	buf *bytes.Buffer, flush func() error, include func(key K, value V) bool,
+) error {
+	buf.WriteString("{")
+
+	now := time.Now()
+	first := true
+	for ent := om.Front(); ent != nil; ent = ent.Next() {
+		if ent.expiredAt(now) || (include != nil && !include(ent.key, ent.value)) {
+			continue
+		}
+		opts, hasOpts := om.fieldOptionsOf(ent.Key())
+		if hasOpts && omitsField(opts, ent.Value()) {
+			continue
+		}
+		if !first {
+			buf.WriteString(",")
+		}
+		first = false
+		err := addJsonKey(buf, ent.Key())
+		if err != nil {
+			return err
+		}
+		buf.WriteString(":")
+		if hasOpts {
+			err = addJsonFieldValue(buf, ent.Key(), ent.Value(), opts)
+		} else {
+			err = addJsonValue(buf, ent.Value())
+		}
+		if err != nil {
+			return err
+		}
+		if flush != nil && buf.Len() >= encodeChunkSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	buf.WriteString("}")
+	return nil
+}
+
+// UnsupportedTypeError is an error type which is returned by Marshal when
+// attempting to encode an unsupported key type.
+type UnsupportedKeyTypeError struct {
+	Type reflect.Type
+}
+
+func (err UnsupportedKeyTypeError) Error() string {
+	if err.Type == nil {
+		return "json: unsupported key type: any"
+	} else {
+		return "json: unsupported key type: " + err.Type.String()
+	}
+}
+
+// SyntaxError is an error stype which is returned by Unmarshal when an input
+// json does not start with "{" or end with "}", or there are value type
+// mismatches.
+type SyntaxError struct {
+	Offset int64
+	msg    string
+}
+
+func (err SyntaxError) Error() string {
+	return err.msg + " (offset:" + strconv.FormatInt(err.Offset, 10) + ")"
+}
+
+// addJsonKey writes the text form of a key as a JSON string, which is
+// escaped in the same way as a key of a Go map by encoding/json.
+func addJsonKey(buf *bytes.Buffer, key any) error {
+	buf.WriteString(`"`)
+	start := buf.Len()
+	err := addKeyText(buf, key)
+	if err == nil && needsJsonEscape(buf.Bytes()[start:]) {
+		text := string(buf.Bytes()[start:])
+		buf.Truncate(start)
+		bs, _ := json.Marshal(text)
+		buf.Write(bs[1 : len(bs)-1])
+	}
+	buf.WriteString(`"`)
+	return err
+}
+
+// needsJsonEscape is a function which reports whether a text has a byte
+// which may be escaped or replaced in a JSON string by encoding/json.
+func needsJsonEscape(text []byte) bool {
+	for _, b := range text {
+		switch {
+		case b < 0x20, b >= 0x80, b == '"', b == '\\', b == '<', b == '>', b == '&':
+			return true
+		}
+	}
+	return false
+}
+
+// addKeyText writes the text form of a key, which is used as a JSON object
+// key without quotes.
+func addKeyText(buf *bytes.Buffer, key any) error {
+	switch key.(type) {
+	case string:
+		buf.WriteString(key.(string))
+	case *string:
+		if key == (*string)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(*(key.(*string)))
+		}
+	case bool:
+		buf.WriteString(strconv.FormatBool(key.(bool)))
+	case int:
+		buf.WriteString(strconv.FormatInt(int64(key.(int)), 10))
+	case int8:
+		buf.WriteString(strconv.FormatInt(int64(key.(int8)), 10))
+	case int16:
+		buf.WriteString(strconv.FormatInt(int64(key.(int16)), 10))
+	case int32:
+		buf.WriteString(strconv.FormatInt(int64(key.(int32)), 10))
+	case int64:
+		buf.WriteString(strconv.FormatInt(int64(key.(int64)), 10))
+	case uint:
+		buf.WriteString(strconv.FormatUint(uint64(key.(uint)), 10))
+	case uint8:
+		buf.WriteString(strconv.FormatUint(uint64(key.(uint8)), 10))
+	case uint16:
+		buf.WriteString(strconv.FormatUint(uint64(key.(uint16)), 10))
+	case uint32:
+		buf.WriteString(strconv.FormatUint(uint64(key.(uint32)), 10))
+	case uint64:
+		buf.WriteString(strconv.FormatUint(uint64(key.(uint64)), 10))
+	case float32:
+		buf.WriteString(strconv.FormatFloat(float64(key.(float32)), 'g', -1, 32))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(key.(float64), 'g', -1, 64))
+	case *bool:
+		if key == (*bool)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatBool(*(key.(*bool))))
+		}
+	case *int:
+		if key == (*int)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatInt(int64(*(key.(*int))), 10))
+		}
+	case *int8:
+		if key == (*int8)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatInt(int64(*(key.(*int8))), 10))
+		}
+	case *int16:
+		if key == (*int16)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatInt(int64(*(key.(*int16))), 10))
+		}
+	case *int32:
+		if key == (*int32)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatInt(int64(*(key.(*int32))), 10))
+		}
+	case *int64:
+		if key == (*int64)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatInt(int64(*(key.(*int64))), 10))
+		}
+	case *uint:
+		if key == (*uint)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatUint(uint64(*(key.(*uint))), 10))
+		}
+	case *uint8:
+		if key == (*uint8)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatUint(uint64(*(key.(*uint8))), 10))
+		}
+	case *uint16:
+		if key == (*uint16)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatUint(uint64(*(key.(*uint16))), 10))
+		}
+	case *uint32:
+		if key == (*uint32)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatUint(uint64(*(key.(*uint32))), 10))
+		}
+	case *uint64:
+		if key == (*uint64)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatUint(uint64(*(key.(*uint64))), 10))
+		}
+	case *float32:
+		if key == (*float32)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatFloat(float64(*(key.(*float32))), 'g', -1, 32))
+		}
+	case *float64:
+		if key == (*float64)(nil) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatFloat(*(key.(*float64)), 'g', -1, 64))
+		}
+	case time.Time:
+		buf.WriteString(key.(time.Time).Format(time.RFC3339Nano))
+	case time.Duration:
+		buf.WriteString(key.(time.Duration).String())
+	case [16]byte:
+		addUUID(buf, key.([16]byte))
+	case *big.Int:
+		buf.WriteString(BigIntKey(key.(*big.Int)))
+	case *big.Rat:
+		buf.WriteString(BigRatKey(key.(*big.Rat)))
+	case encoding.TextMarshaler:
+		text, err := key.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		buf.Write(text)
+	default:
+		// Named 16-byte array types which are not encoding.TextMarshaler are
+		// written in the same form as [16]byte.
+		t := reflect.TypeOf(key)
+		if !isUUIDType(t) {
+			return UnsupportedKeyTypeError{Type: t}
+		}
+		addUUID(buf, reflect.ValueOf(key).Convert(uuidType).Interface().([16]byte))
+	}
+	return nil
+}
+
+var uuidType = reflect.TypeOf([16]byte{})
+
+func isUUIDType(t reflect.Type) bool {
+	return t != nil && t.Kind() == reflect.Array && t.Len() == 16 &&
+		t.Elem().Kind() == reflect.Uint8
+}
+
+const hexDigits = "0123456789abcdef"
+
+// addUUID writes a 16-byte array in the text form of UUID:
+// xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
+func addUUID(buf *bytes.Buffer, u [16]byte) {
+	var text [36]byte
+	j := 0
+	for i, b := range u {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			text[j] = '-'
+			j++
+		}
+		text[j] = hexDigits[b>>4]
+		text[j+1] = hexDigits[b&0x0f]
+		j += 2
+	}
+	buf.Write(text[:])
+}
+
+// parseUUID reads a 16-byte array from the text form of UUID. The form
+// without hyphens is also accepted.
+func parseUUID(s string) (u [16]byte, ok bool) {
+	if len(s) != 36 && len(s) != 32 {
+		return
+	}
+	j := 0
+	for i := range u {
+		if len(s) == 36 && (i == 4 || i == 6 || i == 8 || i == 10) {
+			if s[j] != '-' {
+				return
+			}
+			j++
+		}
+		hi, ok1 := fromHexChar(s[j])
+		lo, ok2 := fromHexChar(s[j+1])
+		if !ok1 || !ok2 {
+			return
+		}
+		u[i] = hi<<4 | lo
+		j += 2
+	}
+	ok = true
+	return
+}
+
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+func addJsonValue[V any](buf *bytes.Buffer, val V) error {
+	bs, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	buf.Write(bs)
+	return nil
+}
+
+// UnmarshalJSON sets the content of this map from a JSON data.
+func (om *Map[K, V]) UnmarshalJSON(data []byte) error {
+	err := om.decodeJSON(json.NewDecoder(bytes.NewReader(data)), true)
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// DecodeJSON is a method which sets the content of this map from a JSON
+// object read from the specified reader. Tokens are consumed incrementally,
+// so the whole input is not held in memory; only the value of one entry is
+// decoded at once.
+// This method returns at the closing brace of the object, but the reader may
+// have been read ahead of it. To decode consecutive objects in a stream, use
+// DecodeJSONFrom with one json.Decoder. If the reader has no object, this
+// method returns io.EOF.
+func (om *Map[K, V]) DecodeJSON(r io.Reader) error {
+	return om.DecodeJSONFrom(json.NewDecoder(r))
+}
+
+// DecodeJSONFrom is a method which sets the content of this map from the next
+// JSON object of the specified decoder, like DecodeJSON. Because the decoder
+// keeps its buffer, this is the way to decode consecutive objects in a stream
+// without losing read-ahead bytes.
+func (om *Map[K, V]) DecodeJSONFrom(dec *json.Decoder) error {
+	return om.decodeJSON(dec, false)
+}
+
+// decodeJSON is a method which reads a JSON object from the decoder into this
+// map. If whole is true, the decoder is read until its end, otherwise this
+// method stops at the closing brace of the object.
+func (om *Map[K, V]) decodeJSON(dec *json.Decoder, whole bool) error {
+	// Open bracket
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	ok := false
+	switch tok.(type) {
+	case json.Delim:
+		if tok.(json.Delim).String() == "{" {
+			ok = true
+		}
+	}
+	if !ok {
+		return SyntaxError{
+			Offset: 0,
+			msg:    "The input JSON does not start with '{'",
+		}
+	}
+
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch tok.(type) {
+		case json.Delim:
+			switch tok.(json.Delim).String() {
+			case "{":
+				return SyntaxError{
+					Offset: dec.InputOffset(),
+					msg:    "Invalid character '" + tok.(json.Delim).String() + "'",
+				}
+			case "}":
+				depth--
+				if !whole {
+					return nil
+				}
+			}
+			continue
+		}
+
+		if depth == 0 {
+			key, err := parseKey[K](tok.(string), dec.InputOffset())
+			if err != nil {
+				return err
+			}
+			// Decode the value with dec.Decode, not token by token, so that
+			// UnmarshalJSON of V and of its fields (e.g. nested *Map fields) is
+			// invoked with the whole value.
+			var val V
+			if p, ok := any(&val).(*any); ok && om.ext != nil && om.ext.nestedOrder {
+				*p, err = decodeOrderedAny(dec)
+			} else {
+				err = dec.Decode(&val)
+			}
+			if err != nil {
+				return err
+			}
+			om.put(key, val)
+		}
+	}
+
+	if depth >= 0 {
+		return SyntaxError{
+			Offset: dec.InputOffset(),
+			msg:    "The input JSON does not end with '}'",
+		}
+	}
+	return nil
+}
+
+// decodeOrderedAny is a function which decodes the next JSON value like
+// dec.Decode into an any, except that objects are decoded into
+// *Map[string, any] at every depth.
+func decodeOrderedAny(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		om := New[string, any](WithNestedOrder())
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			val, err := decodeOrderedAny(dec)
+			if err != nil {
+				return nil, err
+			}
+			om.Store(tok.(string), val)
+		}
+		_, err = dec.Token()
+		return &om, err
+	default:
+		arr := []any{}
+		for dec.More() {
+			val, err := decodeOrderedAny(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, val)
+		}
+		_, err = dec.Token()
+		return arr, err
+	}
+}
+
+// parseKey is a function which reads a key from the text form which
+// addKeyText writes. The offset is used for SyntaxError.
+func parseKey[K comparable](text string, offset int64) (key K, err error) {
+	switch any(key).(type) {
+	case string:
+		key = any(text).(K)
+	case *string:
+		if text == "null" {
+			key = *new(K)
+		} else {
+			str := text
+			key = any(&str).(K)
+		}
+	case bool, int, int8, int16, int32, int64, uint, uint8,
+		uint16, uint32, uint64, float32, float64:
+		err = json.Unmarshal([]byte(text), &key)
+		if err != nil {
+			return key, err
+		}
+	case *bool, *int, *int8, *int16, *int32, *int64, *uint, *uint8,
+		*uint16, *uint32, *uint64, *float32, *float64:
+		tt := reflect.TypeOf(key).Elem()
+		key = reflect.New(tt).Interface().(K)
+		err = json.Unmarshal([]byte(text), key)
+		if err != nil {
+			return key, err
+		}
+	case time.Time:
+		t, err := time.Parse(time.RFC3339Nano, text)
+		if err != nil {
+			return key, err
+		}
+		key = any(t).(K)
+	case time.Duration:
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return key, err
+		}
+		key = any(d).(K)
+	case [16]byte:
+		u, ok := parseUUID(text)
+		if !ok {
+			return key, SyntaxError{
+				Offset: offset,
+				msg:    "Invalid UUID key '" + text + "'",
+			}
+		}
+		key = any(u).(K)
+	case *big.Int:
+		if text != "null" {
+			x, ok := new(big.Int).SetString(text, 10)
+			if !ok {
+				return key, SyntaxError{
+					Offset: offset,
+					msg:    "Invalid big.Int key '" + text + "'",
+				}
+			}
+			key = any(x).(K)
+		}
+	case *big.Rat:
+		if text != "null" {
+			x, ok := new(big.Rat).SetString(text)
+			if !ok {
+				return key, SyntaxError{
+					Offset: offset,
+					msg:    "Invalid big.Rat key '" + text + "'",
+				}
+			}
+			key = any(x).(K)
+		}
+	default:
+		if u, ok := any(&key).(encoding.TextUnmarshaler); ok {
+			err = u.UnmarshalText([]byte(text))
+			return key, err
+		}
+		t := reflect.TypeOf(key)
+		if t != nil && t.Kind() == reflect.Pointer {
+			if u, ok := reflect.New(t.Elem()).Interface().(encoding.TextUnmarshaler); ok {
+				err = u.UnmarshalText([]byte(text))
+				return any(u).(K), err
+			}
+		}
+		if !isUUIDType(t) {
+			return key, UnsupportedKeyTypeError{Type: t}
+		}
+		u, ok := parseUUID(text)
+		if !ok {
+			return key, SyntaxError{
+				Offset: offset,
+				msg:    "Invalid UUID key '" + text + "'",
+			}
+		}
+		key = reflect.ValueOf(u).Convert(t).Interface().(K)
+	}
+	return key, nil
+}
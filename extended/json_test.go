@@ -0,0 +1,522 @@
+package extended_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+type nested struct {
+	Name  string
+	Attrs *orderedmap.Map[string, int]
+	Tags  orderedmap.Map[string, string]
+}
+
+func TestMap_UnmarshalJSON_nestedMapFields(t *testing.T) {
+	bs := []byte(`{
+		"a":{"Name":"x","Attrs":{"z":1,"y":2},"Tags":{"q":"1","p":"2"}},
+		"b":{"Name":"y"}}`)
+
+	om := orderedmap.New[string, nested]()
+	if err := om.UnmarshalJSON(bs); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := om.Load("a")
+	if a.Attrs.String() != "Map[z:1 y:2]" {
+		t.Errorf("a.Attrs = %s", a.Attrs.String())
+	}
+	if a.Tags.String() != "Map[q:1 p:2]" {
+		t.Errorf("a.Tags = %s", a.Tags.String())
+	}
+	b, _ := om.Load("b")
+	if b.Name != "y" || b.Attrs != nil || b.Tags.Len() != 0 {
+		t.Errorf("b = %v", b)
+	}
+
+	omp := orderedmap.New[string, *nested]()
+	if err := omp.UnmarshalJSON(bs); err != nil {
+		t.Fatal(err)
+	}
+	ap, _ := omp.Load("a")
+	if ap.Attrs.String() != "Map[z:1 y:2]" || ap.Tags.String() != "Map[q:1 p:2]" {
+		t.Errorf("a = %v", ap)
+	}
+
+	bs2, err := json.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs2) != `{"a":{"Name":"x","Attrs":{"z":1,"y":2},"Tags":{"q":"1","p":"2"}},"b":{"Name":"y","Attrs":null,"Tags":{}}}` {
+		t.Errorf("MarshalJSON = %s", bs2)
+	}
+}
+
+func TestMap_UnmarshalJSON_valueError(t *testing.T) {
+	om := orderedmap.New[string, nested]()
+	err := om.UnmarshalJSON([]byte(`{"a":{"Name":1}}`))
+	if _, ok := err.(*json.UnmarshalTypeError); !ok {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestMap_JSON_timeKeys(t *testing.T) {
+	t0 := time.Date(2023, 4, 1, 12, 30, 0, 500, time.UTC)
+	om := orderedmap.New[time.Time, int]()
+	om.Store(t0, 1)
+	om.Store(t0.Add(time.Hour), 2)
+
+	bs, err := json.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != `{"2023-04-01T12:30:00.0000005Z":1,"2023-04-01T13:30:00.0000005Z":2}` {
+		t.Errorf("MarshalJSON = %s", bs)
+	}
+
+	om2 := orderedmap.New[time.Time, int]()
+	if err := json.Unmarshal(bs, &om2); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := om2.Load(t0); v != 1 || !ok {
+		t.Errorf("Load = (%d, %t)", v, ok)
+	}
+}
+
+func TestMap_JSON_durationKeys(t *testing.T) {
+	om := orderedmap.New[time.Duration, string]()
+	om.Store(90*time.Second, "a")
+	om.Store(time.Millisecond, "b")
+
+	bs, err := json.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != `{"1m30s":"a","1ms":"b"}` {
+		t.Errorf("MarshalJSON = %s", bs)
+	}
+
+	om2 := orderedmap.New[time.Duration, string]()
+	if err := json.Unmarshal(bs, &om2); err != nil {
+		t.Fatal(err)
+	}
+	if om2.String() != om.String() {
+		t.Errorf("Unmarshal = %s", om2.String())
+	}
+}
+
+func TestMapFunc_bigIntKeys(t *testing.T) {
+	mf := orderedmap.NewBigIntMap[string]()
+	mf.Store(big.NewInt(100), "a")
+	mf.Store(big.NewInt(-5), "b")
+	mf.Store(big.NewInt(100), "c")
+
+	if v, ok := mf.Load(big.NewInt(100)); v != "c" || !ok {
+		t.Errorf("Load = (%s, %t)", v, ok)
+	}
+	if mf.Len() != 2 {
+		t.Errorf("Len = %d", mf.Len())
+	}
+
+	bs, err := json.Marshal(mf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != `{"100":"c","-5":"b"}` {
+		t.Errorf("MarshalJSON = %s", bs)
+	}
+
+	mf2 := orderedmap.NewBigIntMap[string]()
+	if err := json.Unmarshal(bs, &mf2); err != nil {
+		t.Fatal(err)
+	}
+	if mf2.String() != "MapFunc[100:c -5:b]" {
+		t.Errorf("Unmarshal = %s", mf2.String())
+	}
+}
+
+func TestMapFunc_bigRatKeys(t *testing.T) {
+	mf := orderedmap.NewBigRatMap[int]()
+	mf.Store(big.NewRat(1, 2), 1)
+	mf.Store(big.NewRat(2, 4), 2)
+
+	if mf.Len() != 1 {
+		t.Errorf("Len = %d", mf.Len())
+	}
+	bs, err := json.Marshal(mf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != `{"1/2":2}` {
+		t.Errorf("MarshalJSON = %s", bs)
+	}
+
+	om := orderedmap.New[*big.Rat, int]()
+	if err := json.Unmarshal([]byte(`{"x":1}`), &om); err == nil {
+		t.Errorf("Unmarshal of an invalid key succeeded")
+	}
+}
+
+type uuid [16]byte
+
+func TestMap_JSON_uuidKeys(t *testing.T) {
+	u := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3,
+		0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+
+	om := orderedmap.New[[16]byte, int]()
+	om.Store(u, 1)
+	bs, err := json.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != `{"123e4567-e89b-12d3-a456-426614174000":1}` {
+		t.Errorf("MarshalJSON = %s", bs)
+	}
+	om2 := orderedmap.New[[16]byte, int]()
+	if err := json.Unmarshal(bs, &om2); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := om2.Load(u); v != 1 || !ok {
+		t.Errorf("Load = (%d, %t)", v, ok)
+	}
+
+	om3 := orderedmap.New[uuid, int]()
+	om3.Store(uuid(u), 2)
+	bs, err = json.Marshal(om3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != `{"123e4567-e89b-12d3-a456-426614174000":2}` {
+		t.Errorf("MarshalJSON = %s", bs)
+	}
+	om4 := orderedmap.New[uuid, int]()
+	if err := json.Unmarshal([]byte(`{"123E4567E89B12D3A456426614174000":2}`), &om4); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := om4.Load(uuid(u)); v != 2 || !ok {
+		t.Errorf("Load = (%d, %t)", v, ok)
+	}
+	if err := json.Unmarshal([]byte(`{"123e4567":2}`), &om4); err == nil {
+		t.Errorf("Unmarshal of an invalid key succeeded")
+	}
+}
+
+func TestMap_JSON_tupleKeys(t *testing.T) {
+	om := orderedmap.New[orderedmap.Key2[string, int], float64]()
+	om.Store(orderedmap.MakeKey2("tokyo", 2023), 1.5)
+	om.Store(orderedmap.MakeKey2("osaka", 2024), 2.5)
+
+	bs, err := json.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != `{"tokyo|2023":1.5,"osaka|2024":2.5}` {
+		t.Errorf("MarshalJSON = %s", bs)
+	}
+
+	om2 := orderedmap.New[orderedmap.Key2[string, int], float64]()
+	if err := json.Unmarshal(bs, &om2); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := om2.Load(orderedmap.MakeKey2("osaka", 2024)); v != 2.5 || !ok {
+		t.Errorf("Load = (%g, %t)", v, ok)
+	}
+
+	om3 := orderedmap.New[orderedmap.Key3[string, int, bool], int]()
+	if err := json.Unmarshal([]byte(`{"a|1|true":1}`), &om3); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := om3.Load(orderedmap.MakeKey3("a", 1, true)); v != 1 || !ok {
+		t.Errorf("Load = (%d, %t)", v, ok)
+	}
+	if err := json.Unmarshal([]byte(`{"a|1":1}`), &om3); err == nil {
+		t.Errorf("Unmarshal of an invalid key succeeded")
+	}
+}
+
+func TestMap_JSON_escapedKeys(t *testing.T) {
+	for _, key := range []string{
+		`a"b`, `c\d`, "e\tf\n", "<g&h>", "i\u2028j", "k\xffl", "\u00e9", "/m~",
+	} {
+		om := orderedmap.New[string, int]()
+		om.Store(key, 1)
+		bs, err := json.Marshal(om)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := json.Marshal(map[string]int{key: 1})
+		if string(bs) != string(want) {
+			t.Errorf("MarshalJSON of %q = %s, want %s", key, bs, want)
+		}
+
+		om2 := orderedmap.New[string, int]()
+		if err := json.Unmarshal(bs, &om2); err != nil {
+			t.Fatal(err)
+		}
+		if want := strings.ToValidUTF8(key, "\ufffd"); om2.Front().Key() != want {
+			t.Errorf("Unmarshal of %s = %q", bs, om2.Front().Key())
+		}
+	}
+
+	om := orderedmap.New[orderedmap.Key2[string, int], int]()
+	om.Store(orderedmap.MakeKey2(`"x"`, 1), 1)
+	bs, err := json.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != `{"\"x\"|1":1}` {
+		t.Errorf("MarshalJSON = %s", bs)
+	}
+	om2 := orderedmap.New[orderedmap.Key2[string, int], int]()
+	if err := json.Unmarshal(bs, &om2); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := om2.Load(orderedmap.MakeKey2(`"x"`, 1)); !ok {
+		t.Errorf("Unmarshal = %v", om2)
+	}
+}
+
+// goldenMaps returns the maps whose MarshalJSON outputs are fixed in
+// testdata/marshal_golden.json, one line per map. The values cover the
+// formats which could differ among platforms: float formatting at the edges
+// of the exponent notation, float32, the widest integers, integer keys
+// wider than 32 bits, float keys, HTML characters and non-ASCII strings,
+// byte slices, and Go maps whose keys are sorted by encoding/json.
+func goldenMaps() []json.Marshaler {
+	type record struct {
+		F64   float64
+		F32   float32
+		I64   int64
+		U64   uint64
+		Str   string
+		Bytes []byte
+		Set   map[string]bool
+	}
+
+	om0 := orderedmap.New[string, float64]()
+	for i, f := range []float64{
+		0, math.Copysign(0, -1), 0.1, 1.0 / 3, 1e-6, 1e-7, 1e20, 1e21,
+		123456789.125, math.MaxFloat64, math.SmallestNonzeroFloat64,
+	} {
+		om0.Store("f"+strconv.Itoa(i), f)
+	}
+
+	om1 := orderedmap.New[int64, record]()
+	om1.Store(math.MaxInt64, record{
+		F64:   -2.5e-10,
+		F32:   0.1,
+		I64:   math.MinInt64,
+		U64:   math.MaxUint64,
+		Str:   "<a&b> \u00e9\u2028\U0001F600",
+		Bytes: []byte{0, 1, 0xfe, 0xff},
+		Set:   map[string]bool{"z": true, "a": false, "m": true},
+	})
+	om1.Store(-1, record{})
+
+	om2 := orderedmap.New[float64, uint64]()
+	om2.Store(0.1, 1)
+	om2.Store(1e21, 2)
+	om2.Store(-1.5, 3)
+
+	om3 := orderedmap.New[string, *orderedmap.Map[string, any]]()
+	inner := orderedmap.New[string, any]()
+	inner.Store("y", []any{1.5, "x", nil, true})
+	inner.Store("x", map[string]int{"b": 2, "a": 1})
+	om3.Store("inner", &inner)
+	om3.Store("nil", nil)
+
+	return []json.Marshaler{om0, om1, om2, om3}
+}
+
+// TestMap_MarshalJSON_golden checks that the outputs of MarshalJSON are
+// byte-identical to the golden file, which is shared by all OS, architectures
+// and Go versions. Run it with GOARCH=386 too to check 32-bit platforms.
+func TestMap_MarshalJSON_golden(t *testing.T) {
+	want, err := os.ReadFile("testdata/marshal_golden.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	for _, m := range goldenMaps() {
+		bs, err := m.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(bs)
+		buf.WriteString("\n")
+	}
+
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("MarshalJSON differs from the golden file:\n%s", buf.Bytes())
+	}
+}
+
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errors.New("write failed")
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestMap_EncodeJSON(t *testing.T) {
+	om := orderedmap.New[int, string]()
+	for i := 0; i < 10000; i++ {
+		om.Store(i, strings.Repeat("x", i%10))
+	}
+	want, err := om.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := om.EncodeJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("EncodeJSON differs from MarshalJSON")
+	}
+
+	if err := om.EncodeJSON(&failingWriter{n: 1}); err == nil || err.Error() != "write failed" {
+		t.Errorf("EncodeJSON to a failing writer = %v", err)
+	}
+
+	empty := orderedmap.New[int, string]()
+	buf.Reset()
+	if err := empty.EncodeJSON(&buf); err != nil || buf.String() != "{}" {
+		t.Errorf("EncodeJSON of an empty map = (%s, %v)", buf.String(), err)
+	}
+}
+
+func TestMap_DecodeJSON(t *testing.T) {
+	r := iotest.OneByteReader(strings.NewReader(`{"c":{"Bar":"x","Baz":1},"a":{"Bar":"y","Baz":2}}`))
+	om := orderedmap.New[string, Foo]()
+	if err := om.DecodeJSON(r); err != nil {
+		t.Fatal(err)
+	}
+	if om.String() != "Map[c:{x 1} a:{y 2}]" {
+		t.Errorf("String = %s", om.String())
+	}
+
+	dec := json.NewDecoder(strings.NewReader(`{"a":1} {"b":2,"c":3}` + "\n"))
+	keys := []string{}
+	for {
+		om := orderedmap.New[string, int]()
+		err := om.DecodeJSONFrom(dec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, om.String())
+	}
+	if fmt.Sprint(keys) != "[Map[a:1] Map[b:2 c:3]]" {
+		t.Errorf("decoded = %v", keys)
+	}
+
+	om2 := orderedmap.New[string, int]()
+	err := om2.DecodeJSON(strings.NewReader(`{"a":1,"b":`))
+	if err == nil {
+		t.Errorf("DecodeJSON of a truncated object did not fail")
+	}
+	if err := om2.DecodeJSON(strings.NewReader(`[1]`)); err == nil {
+		t.Errorf("DecodeJSON of an array did not fail")
+	}
+}
+
+func TestMap_WithNestedOrder(t *testing.T) {
+	data := `{"a":{"y":1,"x":{"q":true,"p":null}},"b":[{"z":"s","c":2.5},3],"c":"x"}`
+	om := orderedmap.New[string, any](orderedmap.WithNestedOrder())
+	if err := json.Unmarshal([]byte(data), &om); err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := om.Load("a")
+	nested, ok := a.(*orderedmap.Map[string, any])
+	if !ok {
+		t.Fatalf("nested object = %T", a)
+	}
+	if y, _ := nested.Load("y"); y != float64(1) {
+		t.Errorf("y = %v (%T)", y, y)
+	}
+
+	bs, err := json.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != data {
+		t.Errorf("MarshalJSON = %s", bs)
+	}
+
+	om2 := orderedmap.New[string, any]()
+	if err := json.Unmarshal([]byte(data), &om2); err != nil {
+		t.Fatal(err)
+	}
+	if a, _ := om2.Load("a"); fmt.Sprintf("%T", a) != "map[string]interface {}" {
+		t.Errorf("nested object without WithNestedOrder = %T", a)
+	}
+}
+
+func TestMap_SetFieldOptions(t *testing.T) {
+	om := orderedmap.New[string, any]()
+	om.Store("id", 12345678901)
+	om.Store("note", "")
+	om.Store("active", true)
+	om.Store("extra", `{"b":1,"a":[2]}`)
+	om.Store("tags", nil)
+	om.Store("ratio", 0.5)
+
+	om.SetFieldOptions("id", orderedmap.FieldOptions{StringifyNumbers: true})
+	om.SetFieldOptions("note", orderedmap.FieldOptions{OmitIfZero: true})
+	om.SetFieldOptions("active", orderedmap.FieldOptions{StringifyNumbers: true})
+	om.SetFieldOptions("extra", orderedmap.FieldOptions{RawOutput: true})
+	om.SetFieldOptions("tags", orderedmap.FieldOptions{OmitIfZero: true, StringifyNumbers: true})
+
+	b, err := om.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":"12345678901","active":"true","extra":{"b":1,"a":[2]},"ratio":0.5}`
+	if string(b) != want {
+		t.Errorf("MarshalJSON = %s", b)
+	}
+
+	var buf bytes.Buffer
+	if err := om.EncodeJSON(&buf); err != nil || buf.String() != want {
+		t.Errorf("EncodeJSON = %s, %v", buf.String(), err)
+	}
+
+	om.SetFieldOptions("id", orderedmap.FieldOptions{})
+	om.Store("note", "n")
+	b, _ = om.MarshalJSON()
+	if string(b) != `{"id":12345678901,"note":"n","active":"true","extra":{"b":1,"a":[2]},"ratio":0.5}` {
+		t.Errorf("MarshalJSON = %s", b)
+	}
+
+	om.Store("extra", `{"b":`)
+	if _, err := om.MarshalJSON(); err == nil {
+		t.Errorf("MarshalJSON with invalid raw output succeeded")
+	}
+	om.SetFieldOptions("ratio", orderedmap.FieldOptions{RawOutput: true})
+	om.Store("extra", "[]")
+	if _, err := om.MarshalJSON(); err == nil {
+		t.Errorf("MarshalJSON with raw output of a float succeeded")
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// NewLRU is a function which creates a new ordered map which works as a
+// cache with the least-recently-used eviction policy, which is same with New
+// with WithAccessOrder and WithMaxLen(capacity).
+// When an insertion makes the map exceed capacity entries, the
+// least-recently-used entries at the front are evicted with the reason
+// EvictMaxLen and passed to the callback set by WithOnEvict.
+// If capacity is zero or less, the number of entries is not capped.
+func NewLRU[K comparable, V any](capacity int, opts ...Option) Map[K, V] {
+	opts = append(opts[:len(opts):len(opts)], WithMaxLen(capacity), WithAccessOrder())
+	return New[K, V](opts...)
+}
+
+// WithAccessOrder is a function which returns an option to make the entry
+// list of the map in the order of accesses instead of key insertions, like
+// LinkedHashMap of Java with accessOrder.
+// An access to an entry by Load, LoadCtx, TryLoad, LoadOrStore,
+// LoadOrStoreFunc or an update of its value moves the entry to the back of
+// the entry list, so the front entry is the least-recently-used one.
+// Range, Front, Back and other iterations do not count as accesses.
+// With this option, a load of a SyncMap locks the map for writing.
+// This is same with WithOrder(OrderAccess).
+func WithAccessOrder() Option {
+	return WithOrder(OrderAccess)
+}
+
+// accessed is a method which moves an accessed entry to the back of the entry
+// list if this map is in access order.
+func (om *Map[K, V]) accessed(ent *Entry[K, V]) {
+	if om.ext != nil && om.ext.accessOrder {
+		om.moveToBack(ent)
+	}
+}
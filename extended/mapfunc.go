@@ -0,0 +1,164 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// MapFunc is a struct which is an ordered map whose key equality is decided
+// by a canonical form of keys instead of Go's == operator.
+// This is for keys like *big.Int and *big.Rat, which are comparable as
+// pointers but whose equality is the equality of the numbers they point.
+//
+// A MapFunc keeps the first key stored for a canonical form, and its order is
+// the order of insertions of the canonical forms.
+type MapFunc[K comparable, C comparable, V any] struct {
+	om    Map[C, funcEntry[K, V]]
+	canon func(K) C
+}
+
+type funcEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewFunc is a function which creates a new ordered map, which is empty and
+// compares keys by the canonical forms which canon returns.
+func NewFunc[K comparable, C comparable, V any](
+	canon func(K) C,
+) MapFunc[K, C, V] {
+	return MapFunc[K, C, V]{om: New[C, funcEntry[K, V]](), canon: canon}
+}
+
+// NewBigIntMap is a function which creates a new ordered map which has
+// *big.Int keys compared by their values.
+func NewBigIntMap[V any]() MapFunc[*big.Int, string, V] {
+	return NewFunc[*big.Int, string, V](BigIntKey)
+}
+
+// NewBigRatMap is a function which creates a new ordered map which has
+// *big.Rat keys compared by their values.
+func NewBigRatMap[V any]() MapFunc[*big.Rat, string, V] {
+	return NewFunc[*big.Rat, string, V](BigRatKey)
+}
+
+// BigIntKey is a function which returns the canonical form of a *big.Int.
+func BigIntKey(x *big.Int) string {
+	if x == nil {
+		return "null"
+	}
+	return x.String()
+}
+
+// BigRatKey is a function which returns the canonical form of a *big.Rat.
+func BigRatKey(x *big.Rat) string {
+	if x == nil {
+		return "null"
+	}
+	return x.RatString()
+}
+
+// Len is a method which returns the number of entries in this map.
+func (mf *MapFunc[K, C, V]) Len() int {
+	return mf.om.Len()
+}
+
+// Store is a method which sets a value for a key.
+func (mf *MapFunc[K, C, V]) Store(key K, value V) {
+	c := mf.canon(key)
+	if fe, ok := mf.om.Load(c); ok {
+		key = fe.key
+	}
+	mf.om.Store(c, funcEntry[K, V]{key: key, value: value})
+}
+
+// Load is a method which returns a value stored in this map for a key.
+// If no value was found for a key, the ok result is false.
+func (mf *MapFunc[K, C, V]) Load(key K) (value V, ok bool) {
+	fe, ok := mf.om.Load(mf.canon(key))
+	return fe.value, ok
+}
+
+// LoadOrStore is a method which returns a value for a key if presents,
+// otherwise stores and returns a given value.
+// The loaded flag is true if the value was loaded, false if stored.
+func (mf *MapFunc[K, C, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	fe, loaded := mf.om.LoadOrStore(
+		mf.canon(key), funcEntry[K, V]{key: key, value: value})
+	return fe.value, loaded
+}
+
+// Delete is a method which deletes a value for a key.
+func (mf *MapFunc[K, C, V]) Delete(key K) {
+	mf.om.Delete(mf.canon(key))
+}
+
+// Range is a method which calls the specified function: fn sequentially for
+// each key and value in this map.
+// If fn returns false, this method stops the iteration.
+func (mf *MapFunc[K, C, V]) Range(fn func(key K, value V) bool) {
+	mf.om.Range(func(_ C, fe funcEntry[K, V]) bool {
+		return fn(fe.key, fe.value)
+	})
+}
+
+// String is a method which returns a string of the content of this map.
+func (mf MapFunc[K, C, V]) String() string {
+	var buf strings.Builder
+	buf.WriteString("MapFunc[")
+	sep := ""
+	mf.Range(func(key K, value V) bool {
+		buf.WriteString(fmt.Sprintf("%s%v:%v", sep, key, value))
+		sep = " "
+		return true
+	})
+	buf.WriteString("]")
+	return buf.String()
+}
+
+// MarshalJSON returns a byte array of JSON string which expresses the content
+// of this map. Keys are written in the same way as Map.MarshalJSON.
+func (mf MapFunc[K, C, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	var err error
+	sep := ""
+	mf.Range(func(key K, value V) bool {
+		buf.WriteString(sep)
+		sep = ","
+		err = addJsonKey(&buf, key)
+		if err != nil {
+			return false
+		}
+		buf.WriteString(":")
+		err = addJsonValue(&buf, value)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON sets the content of this map from a JSON data.
+// Keys are read in the same way as Map.UnmarshalJSON.
+func (mf *MapFunc[K, C, V]) UnmarshalJSON(data []byte) error {
+	var om Map[K, V]
+	err := json.Unmarshal(data, &om)
+	if err != nil {
+		return err
+	}
+	om.Range(func(key K, value V) bool {
+		mf.Store(key, value)
+		return true
+	})
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package maptest provides a fake ordered map with scriptable failures and
+// latencies, for testing code which depends on orderedmap.Fallible, on a
+// orderedmap.Backend, or on a loader and a writer.
+//
+// # Usage
+//
+//	fake := maptest.New[string, int]()
+//	fake.Fail(maptest.OpLoad, io.ErrUnexpectedEOF, 1)
+//	fake.Delay(maptest.OpStore, 10*time.Millisecond)
+//
+//	bm := orderedmap.NewBacked[string, int](fake)
+//	om := orderedmap.New[string, int](
+//		orderedmap.WithLoaderCtx(fake.Loader()),
+//		orderedmap.WithWriterCtx(fake.Writer()),
+//	)
+package maptest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+// Op is a type which identifies a kind of operation of a Fake.
+type Op int
+
+const (
+	// OpLoad is the operation of Load, Get and the loader.
+	OpLoad Op = iota
+
+	// OpStore is the operation of Store, Put and the writer.
+	OpStore
+
+	// OpDelete is the operation of Delete.
+	OpDelete
+
+	// OpRange is the operation of Range and Iterate.
+	OpRange
+
+	// OpLen is the operation of Len.
+	OpLen
+
+	numOps
+)
+
+type fault struct {
+	err     error
+	times   int
+	latency time.Duration
+}
+
+// Fake is a struct which is an in-memory ordered map implementing
+// orderedmap.Fallible and orderedmap.Backend, whose operations can be made to
+// fail or to be delayed.
+// This is safe for concurrent use.
+type Fake[K comparable, V any] struct {
+	mu     sync.Mutex
+	om     orderedmap.Map[K, V]
+	faults [numOps]fault
+	calls  [numOps]int
+}
+
+var (
+	_ orderedmap.Fallible[string, any] = (*Fake[string, any])(nil)
+	_ orderedmap.Backend[string, any]  = (*Fake[string, any])(nil)
+)
+
+// New is a function which creates a new empty Fake.
+func New[K comparable, V any]() *Fake[K, V] {
+	return &Fake[K, V]{om: orderedmap.New[K, V]()}
+}
+
+// Fail is a method which makes the next n calls of the specified operation
+// fail with err. If n is zero or negative, all calls fail until Reset is
+// called. If err is nil, the failure set before is cancelled.
+func (f *Fake[K, V]) Fail(op Op, err error, n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults[op].err = err
+	f.faults[op].times = n
+}
+
+// Delay is a method which makes every call of the specified operation wait
+// for the specified duration before it runs.
+func (f *Fake[K, V]) Delay(op Op, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults[op].latency = d
+}
+
+// Reset is a method which cancels all failures and delays, and clears the
+// counts of calls. The entries are kept.
+func (f *Fake[K, V]) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = [numOps]fault{}
+	f.calls = [numOps]int{}
+}
+
+// Calls is a method which returns the number of calls of the specified
+// operation, including failed ones.
+func (f *Fake[K, V]) Calls(op Op) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[op]
+}
+
+// enter is a method which counts a call of an operation, waits for its delay
+// until the context is done, and returns its scripted error.
+func (f *Fake[K, V]) enter(ctx context.Context, op Op) error {
+	f.mu.Lock()
+	f.calls[op]++
+	flt := &f.faults[op]
+	latency := flt.latency
+	err := flt.err
+	if err != nil && flt.times > 0 {
+		flt.times--
+		if flt.times == 0 {
+			flt.err = nil
+		}
+	}
+	f.mu.Unlock()
+
+	if latency > 0 {
+		t := time.NewTimer(latency)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// Len is a method which returns the number of entries.
+func (f *Fake[K, V]) Len() (int, error) {
+	if err := f.enter(context.Background(), OpLen); err != nil {
+		return 0, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.om.Len(), nil
+}
+
+// Load is a method which returns a value for a key.
+func (f *Fake[K, V]) Load(key K) (value V, ok bool, err error) {
+	return f.load(context.Background(), key)
+}
+
+// Get is a method which returns a value for a key, as an orderedmap.Backend.
+func (f *Fake[K, V]) Get(key K) (value V, ok bool, err error) {
+	return f.load(context.Background(), key)
+}
+
+func (f *Fake[K, V]) load(ctx context.Context, key K) (value V, ok bool, err error) {
+	if err = f.enter(ctx, OpLoad); err != nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	value, ok = f.om.Load(key)
+	return
+}
+
+// Store is a method which sets a value for a key.
+func (f *Fake[K, V]) Store(key K, value V) error {
+	return f.store(context.Background(), key, value)
+}
+
+// Put is a method which sets a value for a key, as an orderedmap.Backend.
+func (f *Fake[K, V]) Put(key K, value V) error {
+	return f.store(context.Background(), key, value)
+}
+
+func (f *Fake[K, V]) store(ctx context.Context, key K, value V) error {
+	if err := f.enter(ctx, OpStore); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.om.Store(key, value)
+	return nil
+}
+
+// Delete is a method which removes an entry for a key.
+func (f *Fake[K, V]) Delete(key K) error {
+	if err := f.enter(context.Background(), OpDelete); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.om.Delete(key)
+	return nil
+}
+
+// Range is a method which calls fn with a snapshot of the entries in the order
+// of key insertions, until fn returns false.
+func (f *Fake[K, V]) Range(fn func(key K, value V) bool) error {
+	if err := f.enter(context.Background(), OpRange); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	pairs := make([]orderedmap.Pair[K, V], 0, f.om.Len())
+	f.om.Range(func(key K, value V) bool {
+		pairs = append(pairs, orderedmap.Pair[K, V]{Key: key, Value: value})
+		return true
+	})
+	f.mu.Unlock()
+
+	for _, p := range pairs {
+		if !fn(p.Key, p.Value) {
+			break
+		}
+	}
+	return nil
+}
+
+// Iterate is a method which is the same as Range, as an orderedmap.Backend.
+func (f *Fake[K, V]) Iterate(fn func(key K, value V) bool) error {
+	return f.Range(fn)
+}
+
+// Loader is a method which returns a loader function for
+// orderedmap.WithLoaderCtx, which loads values from this fake and fails with
+// orderedmap.ErrKeyNotFound for absent keys.
+func (f *Fake[K, V]) Loader() func(ctx context.Context, key K) (V, error) {
+	return func(ctx context.Context, key K) (V, error) {
+		value, ok, err := f.load(ctx, key)
+		if err == nil && !ok {
+			err = orderedmap.ErrKeyNotFound
+		}
+		return value, err
+	}
+}
+
+// Writer is a method which returns a writer function for
+// orderedmap.WithWriterCtx, which stores values into this fake.
+func (f *Fake[K, V]) Writer() func(ctx context.Context, key K, value V) error {
+	return f.store
+}
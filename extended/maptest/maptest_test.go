@@ -0,0 +1,78 @@
+package maptest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+	"github.com/sttk/benchmarks_orderedmap/extended/maptest"
+)
+
+func TestFake_Fail(t *testing.T) {
+	fake := maptest.New[string, int]()
+	bm := orderedmap.NewBacked[string, int](fake)
+	if err := bm.Store("a", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Fail(maptest.OpLoad, io.ErrUnexpectedEOF, 2)
+	for i := 0; i < 2; i++ {
+		if _, _, err := bm.Load("a"); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Load #%d: err = %v", i, err)
+		}
+	}
+	if v, ok, err := bm.Load("a"); err != nil || !ok || v != 1 {
+		t.Errorf("Load = %v, %v, %v", v, ok, err)
+	}
+	if n := fake.Calls(maptest.OpLoad); n != 3 {
+		t.Errorf("Calls(OpLoad) = %d", n)
+	}
+
+	fake.Fail(maptest.OpStore, io.ErrClosedPipe, 0)
+	for i := 0; i < 3; i++ {
+		if err := fake.Store("b", 2); err != io.ErrClosedPipe {
+			t.Errorf("Store #%d: err = %v", i, err)
+		}
+	}
+	fake.Reset()
+	if err := fake.Store("b", 2); err != nil {
+		t.Error(err)
+	}
+	if n, _ := fake.Len(); n != 2 {
+		t.Errorf("Len = %d", n)
+	}
+}
+
+func TestFake_Loader(t *testing.T) {
+	fake := maptest.New[string, int]()
+	fake.Store("a", 1)
+	fake.Delay(maptest.OpLoad, time.Second)
+
+	om := orderedmap.New[string, int](
+		orderedmap.WithLoaderCtx(fake.Loader()),
+		orderedmap.WithWriterCtx(fake.Writer()),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, ok, err := om.LoadCtx(ctx, "a"); ok || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("LoadCtx = %v, %v", ok, err)
+	}
+
+	fake.Reset()
+	if v, ok, err := om.LoadCtx(context.Background(), "a"); err != nil || !ok || v != 1 {
+		t.Errorf("LoadCtx = %v, %v, %v", v, ok, err)
+	}
+	if _, _, err := om.LoadCtx(context.Background(), "x"); !errors.Is(err, orderedmap.ErrKeyNotFound) {
+		t.Errorf("LoadCtx of an absent key: err = %v", err)
+	}
+
+	if err := om.StoreCtx(context.Background(), "c", 3); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, _ := fake.Load("c"); !ok || v != 3 {
+		t.Errorf("fake.Load = %v, %v", v, ok)
+	}
+}
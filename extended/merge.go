@@ -0,0 +1,74 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// Merge is a method which stores the entries of the specified map into this
+// map. The keys which are not in this map are appended in the order of the
+// specified map, and the keys in both maps keep their positions in this map.
+// The value for a key in both maps is the result of resolve with the values
+// of this map and of the specified map. If resolve is nil, the value of the
+// specified map is taken.
+// The keys are looked up in both maps without side effects, so loaders are
+// not called and entries are not moved in access order.
+func (om *Map[K, V]) Merge(other *Map[K, V], resolve func(key K, left, right V) V) {
+	if om.limited() {
+		return
+	}
+	other.Range(func(key K, value V) bool {
+		if resolve != nil {
+			if left, ok := om.peek(key); ok {
+				value = resolve(key, left, value)
+			}
+		}
+		om.put(key, value)
+		return true
+	})
+}
+
+// Union is a method which returns a new map having the keys in this map
+// or in the specified map. The keys of this map come first in its order, and
+// the keys only in the specified map follow in their order.
+// The value for a key in both maps is resolved like Merge.
+func (om *Map[K, V]) Union(other *Map[K, V], resolve func(key K, left, right V) V) Map[K, V] {
+	u := New[K, V]()
+	om.Range(func(key K, value V) bool {
+		u.Store(key, value)
+		return true
+	})
+	u.Merge(other, resolve)
+	return u
+}
+
+// Intersect is a method which returns a new map having the keys in both this
+// map and the specified map, in the order of this map.
+// The values are resolved like Merge.
+func (om *Map[K, V]) Intersect(other *Map[K, V], resolve func(key K, left, right V) V) Map[K, V] {
+	in := New[K, V]()
+	om.Range(func(key K, value V) bool {
+		if right, ok := other.peek(key); ok {
+			if resolve != nil {
+				value = resolve(key, value, right)
+			} else {
+				value = right
+			}
+			in.Store(key, value)
+		}
+		return true
+	})
+	return in
+}
+
+// Difference is a method which returns a new map having the entries of this
+// map whose keys are not in the specified map, in the order of this map.
+func (om *Map[K, V]) Difference(other *Map[K, V]) Map[K, V] {
+	diff := New[K, V]()
+	om.Range(func(key K, value V) bool {
+		if _, ok := other.peek(key); !ok {
+			diff.Store(key, value)
+		}
+		return true
+	})
+	return diff
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// MoveToFront is a method which moves the entry for a key to the front of
+// this map, and reports whether the key is present.
+//
+// Moving methods change only the order of entries: the values, timestamps,
+// expiration times and other states of the entries are kept. After moving,
+// the order of entries is the order of key insertions for all other methods,
+// e.g. the front entry is the first to be evicted by WithMaxLen, and tokens
+// of Page returned before moving may be no longer valid.
+// MoveToBack takes a constant time, and the other moving methods take a time
+// proportional to the number of entries after the new position.
+func (om *Map[K, V]) MoveToFront(key K) bool {
+	ent := om.liveEntry(key)
+	if ent == nil || om.limited() {
+		return false
+	}
+	om.moveBetween(ent, nil, om.head)
+	return true
+}
+
+// MoveToBack is a method which moves the entry for a key to the back of this
+// map, and reports whether the key is present.
+func (om *Map[K, V]) MoveToBack(key K) bool {
+	ent := om.liveEntry(key)
+	if ent == nil || om.limited() {
+		return false
+	}
+	om.moveToBack(ent)
+	return true
+}
+
+// MoveBefore is a method which moves the entry for a key to just before the
+// entry for the mark key, and reports whether both keys are present.
+func (om *Map[K, V]) MoveBefore(key, mark K) bool {
+	ent, at := om.liveEntry(key), om.liveEntry(mark)
+	if ent == nil || at == nil || om.limited() {
+		return false
+	}
+	if ent != at {
+		om.moveBetween(ent, at.prev, at)
+	}
+	return true
+}
+
+// MoveAfter is a method which moves the entry for a key to just after the
+// entry for the mark key, and reports whether both keys are present.
+func (om *Map[K, V]) MoveAfter(key, mark K) bool {
+	ent, at := om.liveEntry(key), om.liveEntry(mark)
+	if ent == nil || at == nil || om.limited() {
+		return false
+	}
+	if ent != at {
+		om.moveBetween(ent, at, at.next)
+	}
+	return true
+}
+
+// liveEntry is a method which returns the entry for a key in the entry list,
+// or nil if the key is not present.
+func (om *Map[K, V]) liveEntry(key K) *Entry[K, V] {
+	if om == nil {
+		return nil
+	}
+	ent, exists := om.m[key]
+	if !exists || ent.deleted {
+		return nil
+	}
+	return ent
+}
+
+// moveBetween is a method which moves an entry in the entry list to between
+// the specified entries, where prev is nil for the front and next is nil for
+// the back, and renumbers the sequence numbers of the entries from the moved
+// entry to the back so that they stay in ascending order.
+func (om *Map[K, V]) moveBetween(ent, prev, next *Entry[K, V]) {
+	if ent == prev || ent == next {
+		return
+	}
+	if next == nil {
+		om.moveToBack(ent)
+		return
+	}
+
+	if ent.prev != nil {
+		ent.prev.next = ent.next
+	} else {
+		om.head = ent.next
+	}
+	if ent.next != nil {
+		ent.next.prev = ent.prev
+	} else {
+		om.last = ent.prev
+	}
+
+	ent.prev = prev
+	ent.next = next
+	if prev != nil {
+		prev.next = ent
+	} else {
+		om.head = ent
+	}
+	next.prev = ent
+
+	for e := ent; e != nil; e = e.next {
+		om.seq++
+		e.seq = om.seq
+	}
+	if om.ext != nil {
+		om.movedExt(ent)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+)
+
+// EncodeMsgpack is a method which encodes this map as a MessagePack map, for
+// github.com/vmihailenco/msgpack/v5.
+// The entries are written in the order of key insertions.
+// Keys of string, bool and numeric types are encoded as MessagePack values of
+// those types, and keys of other types are encoded as strings in the same
+// forms as MarshalJSON.
+func (om Map[K, V]) EncodeMsgpack(enc *msgpack.Encoder) error {
+	if err := enc.EncodeMapLen(om.Len()); err != nil {
+		return err
+	}
+	for ent := om.Front(); ent != nil; ent = ent.Next() {
+		if err := msgpackEncodeKey(enc, ent.Key()); err != nil {
+			return err
+		}
+		if err := enc.Encode(ent.Value()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeMsgpack is a method which sets the content of this map from a
+// MessagePack map in the order of the encoded entries, for
+// github.com/vmihailenco/msgpack/v5.
+// If the map is created with WithNestedOrder and its value type is any,
+// nested maps are decoded into *Map[string, any].
+func (om *Map[K, V]) DecodeMsgpack(dec *msgpack.Decoder) error {
+	n, err := dec.DecodeMapLen()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		key, err := msgpackDecodeKey[K](dec)
+		if err != nil {
+			return err
+		}
+		var val V
+		if p, ok := any(&val).(*any); ok && om.ext != nil && om.ext.nestedOrder {
+			*p, err = decodeOrderedMsgpack(dec)
+		} else {
+			err = dec.Decode(&val)
+		}
+		if err != nil {
+			return err
+		}
+		om.put(key, val)
+	}
+	return nil
+}
+
+func msgpackEncodeKey(enc *msgpack.Encoder, key any) error {
+	switch key.(type) {
+	case string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		return enc.Encode(key)
+	}
+	var buf bytes.Buffer
+	if err := addKeyText(&buf, key); err != nil {
+		return err
+	}
+	return enc.EncodeString(buf.String())
+}
+
+func msgpackDecodeKey[K comparable](dec *msgpack.Decoder) (key K, err error) {
+	switch any(key).(type) {
+	case string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		err = dec.Decode(&key)
+		return
+	}
+	s, err := dec.DecodeString()
+	if err != nil {
+		return
+	}
+	return parseKey[K](s, 0)
+}
+
+// decodeOrderedMsgpack is a function which decodes the next MessagePack value
+// into an any, except that maps are decoded into *Map[string, any] at every
+// depth.
+func decodeOrderedMsgpack(dec *msgpack.Decoder) (any, error) {
+	c, err := dec.PeekCode()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case msgpcode.IsFixedMap(c) || c == msgpcode.Map16 || c == msgpcode.Map32:
+		om := New[string, any](WithNestedOrder())
+		if err := om.DecodeMsgpack(dec); err != nil {
+			return nil, err
+		}
+		return &om, nil
+	case msgpcode.IsFixedArray(c) || c == msgpcode.Array16 || c == msgpcode.Array32:
+		n, err := dec.DecodeArrayLen()
+		if err != nil {
+			return nil, err
+		}
+		arr := make([]any, 0, n)
+		for i := 0; i < n; i++ {
+			v, err := decodeOrderedMsgpack(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	default:
+		return dec.DecodeInterface()
+	}
+}
@@ -0,0 +1,78 @@
+package extended_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+func TestMap_EncodeMsgpack(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("z", 1)
+	om.Store("a", 2)
+
+	bs, err := msgpack.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// {"z": 1, "a": 2} as a fixmap of two entries.
+	want := []byte{0x82, 0xa1, 'z', 0x01, 0xa1, 'a', 0x02}
+	if !bytes.Equal(bs, want) {
+		t.Errorf("msgpack.Marshal = % x", bs)
+	}
+
+	om2 := orderedmap.New[string, int]()
+	if err := msgpack.Unmarshal(bs, &om2); err != nil {
+		t.Fatal(err)
+	}
+	if om2.String() != "Map[z:1 a:2]" {
+		t.Errorf("om2 = %v", om2)
+	}
+
+	big := orderedmap.New[int, bool]()
+	for i := 70000; i > 0; i-- {
+		big.Store(i, i%2 == 0)
+	}
+	if bs, err = msgpack.Marshal(big); err != nil {
+		t.Fatal(err)
+	}
+	big2 := orderedmap.New[int, bool]()
+	if err := msgpack.Unmarshal(bs, &big2); err != nil {
+		t.Fatal(err)
+	}
+	if big.String() != big2.String() {
+		t.Errorf("round trip of %d entries failed", big.Len())
+	}
+}
+
+func TestMap_DecodeMsgpack(t *testing.T) {
+	src := orderedmap.New[string, any]()
+	nested := orderedmap.New[string, any]()
+	nested.Store("y", 1)
+	nested.Store("x", []any{2, "s"})
+	src.Store("b", nested)
+	src.Store("a", nil)
+
+	bs, err := msgpack.Marshal(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	om := orderedmap.New[string, any](orderedmap.WithNestedOrder())
+	if err := msgpack.Unmarshal(bs, &om); err != nil {
+		t.Fatal(err)
+	}
+	if om.String() != "Map[b:Map[y:1 x:[2 s]] a:<nil>]" {
+		t.Errorf("om = %v", om)
+	}
+	b, _ := om.Load("b")
+	if _, ok := b.(*orderedmap.Map[string, any]); !ok {
+		t.Errorf("nested map = %T", b)
+	}
+
+	if err := msgpack.Unmarshal([]byte{0x92, 0x01, 0x02}, &om); err == nil {
+		t.Error("expected an error for an array")
+	}
+}
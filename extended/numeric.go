@@ -0,0 +1,53 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// Number is a constraint for value types which Add and Inc accept.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~complex64 | ~complex128
+}
+
+// Add is a function which adds delta to the value for a key in an ordered map
+// of numbers, and returns the result. If the key is absent or has expired,
+// delta is stored as the value.
+// This looks up the key only once, while Load and Store does it twice.
+// If the addition is rejected by the rate limit set by WithRateLimit, this
+// function returns the zero value.
+func Add[K comparable, V Number](om *Map[K, V], key K, delta V) V {
+	if om.limited() {
+		return 0
+	}
+	ent, exists := om.m[key]
+	if exists && !ent.deleted && om.expireIfDue(ent) {
+		exists = false
+	}
+	if exists {
+		if !ent.deleted {
+			old := ent.value
+			ent.value += delta
+			om.updated(ent, old)
+			om.written(key, ent.value)
+			return ent.value
+		}
+		ent.value = delta
+		ent.deleted = false
+	} else {
+		ent = om.newEntry(key, delta)
+	}
+
+	om.index(key, ent)
+	om.linkLast(ent)
+	om.written(key, delta)
+	return delta
+}
+
+// Inc is a function which increments the value for a key in an ordered map of
+// numbers, and returns the result. If the key is absent, 1 is stored as the
+// value.
+func Inc[K comparable, V Number](om *Map[K, V], key K) V {
+	return Add(om, key, 1)
+}
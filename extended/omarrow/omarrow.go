@@ -0,0 +1,210 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package omarrow provides conversions between ordered maps of columns and
+// Apache Arrow record batches, which preserve the order of columns.
+//
+// # Usage
+//
+//	cols := orderedmap.New[string, []int64]()
+//	cols.Store("id", []int64{1, 2, 3})
+//	cols.Store("count", []int64{10, 20, 30})
+//
+//	rec, err := omarrow.ToRecord(memory.DefaultAllocator, &cols)
+//	...
+//	defer rec.Release()
+//
+//	cols2, err := omarrow.FromRecord[int64](rec)
+//
+// The key of each entry is the name of a column, and the value is the column
+// values. Columns of bool, signed and unsigned integers, floats and strings
+// are supported.
+package omarrow
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+// Value is a type constraint of the column value types which can be converted
+// to and from Arrow arrays.
+type Value interface {
+	bool | int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64 |
+		float32 | float64 | string
+}
+
+// ErrColumnLength is an error which is returned by ToRecord when the columns
+// have different lengths.
+var ErrColumnLength = errors.New("omarrow: columns have different lengths")
+
+// ColumnTypeError is an error type which is returned by FromRecord when the
+// type of a column of a record does not match the requested value type.
+type ColumnTypeError struct {
+	Column string
+	Type   arrow.DataType
+}
+
+func (err ColumnTypeError) Error() string {
+	return fmt.Sprintf("omarrow: column %q has an unsupported type %s", err.Column, err.Type)
+}
+
+// ToRecord is a function which converts an ordered map of columns into an
+// Arrow record batch whose fields are in the order of the map's keys.
+// The returned record must be released by the caller.
+func ToRecord[T Value](mem memory.Allocator, cols *orderedmap.Map[string, []T]) (arrow.Record, error) {
+	typ := dataType[T]()
+	fields := make([]arrow.Field, 0, cols.Len())
+	rows := -1
+	var err error
+	cols.Range(func(name string, col []T) bool {
+		if rows >= 0 && len(col) != rows {
+			err = fmt.Errorf("%w: %q has %d rows, not %d", ErrColumnLength, name, len(col), rows)
+			return false
+		}
+		rows = len(col)
+		fields = append(fields, arrow.Field{Name: name, Type: typ})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rb := array.NewRecordBuilder(mem, arrow.NewSchema(fields, nil))
+	defer rb.Release()
+
+	i := 0
+	cols.Range(func(name string, col []T) bool {
+		appendValues(rb.Field(i), col)
+		i++
+		return true
+	})
+	return rb.NewRecord(), nil
+}
+
+// FromRecord is a function which converts an Arrow record batch into an
+// ordered map of columns whose keys are in the order of the record's fields.
+// Every column must have the Arrow type which corresponds to T, and null
+// values are converted to zero values.
+func FromRecord[T Value](rec arrow.Record) (orderedmap.Map[string, []T], error) {
+	cols := orderedmap.New[string, []T]()
+	typ := dataType[T]()
+	for i, f := range rec.Schema().Fields() {
+		if !arrow.TypeEqual(f.Type, typ) {
+			return cols, ColumnTypeError{Column: f.Name, Type: f.Type}
+		}
+		cols.Store(f.Name, columnValues[T](rec.Column(i)))
+	}
+	return cols, nil
+}
+
+func dataType[T Value]() arrow.DataType {
+	var zero T
+	switch any(zero).(type) {
+	case bool:
+		return arrow.FixedWidthTypes.Boolean
+	case int8:
+		return arrow.PrimitiveTypes.Int8
+	case int16:
+		return arrow.PrimitiveTypes.Int16
+	case int32:
+		return arrow.PrimitiveTypes.Int32
+	case int64:
+		return arrow.PrimitiveTypes.Int64
+	case uint8:
+		return arrow.PrimitiveTypes.Uint8
+	case uint16:
+		return arrow.PrimitiveTypes.Uint16
+	case uint32:
+		return arrow.PrimitiveTypes.Uint32
+	case uint64:
+		return arrow.PrimitiveTypes.Uint64
+	case float32:
+		return arrow.PrimitiveTypes.Float32
+	case float64:
+		return arrow.PrimitiveTypes.Float64
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+func appendValues[T Value](b array.Builder, col []T) {
+	switch b := b.(type) {
+	case *array.BooleanBuilder:
+		b.AppendValues(any(col).([]bool), nil)
+	case *array.Int8Builder:
+		b.AppendValues(any(col).([]int8), nil)
+	case *array.Int16Builder:
+		b.AppendValues(any(col).([]int16), nil)
+	case *array.Int32Builder:
+		b.AppendValues(any(col).([]int32), nil)
+	case *array.Int64Builder:
+		b.AppendValues(any(col).([]int64), nil)
+	case *array.Uint8Builder:
+		b.AppendValues(any(col).([]uint8), nil)
+	case *array.Uint16Builder:
+		b.AppendValues(any(col).([]uint16), nil)
+	case *array.Uint32Builder:
+		b.AppendValues(any(col).([]uint32), nil)
+	case *array.Uint64Builder:
+		b.AppendValues(any(col).([]uint64), nil)
+	case *array.Float32Builder:
+		b.AppendValues(any(col).([]float32), nil)
+	case *array.Float64Builder:
+		b.AppendValues(any(col).([]float64), nil)
+	case *array.StringBuilder:
+		b.AppendValues(any(col).([]string), nil)
+	}
+}
+
+type valueArray[T any] interface {
+	arrow.Array
+	Value(i int) T
+}
+
+func columnValues[T Value](arr arrow.Array) []T {
+	var col any
+	switch a := arr.(type) {
+	case *array.Boolean:
+		col = values[bool](a)
+	case *array.Int8:
+		col = values[int8](a)
+	case *array.Int16:
+		col = values[int16](a)
+	case *array.Int32:
+		col = values[int32](a)
+	case *array.Int64:
+		col = values[int64](a)
+	case *array.Uint8:
+		col = values[uint8](a)
+	case *array.Uint16:
+		col = values[uint16](a)
+	case *array.Uint32:
+		col = values[uint32](a)
+	case *array.Uint64:
+		col = values[uint64](a)
+	case *array.Float32:
+		col = values[float32](a)
+	case *array.Float64:
+		col = values[float64](a)
+	case *array.String:
+		col = values[string](a)
+	}
+	return col.([]T)
+}
+
+func values[T any](a valueArray[T]) []T {
+	col := make([]T, a.Len())
+	for i := range col {
+		if a.IsValid(i) {
+			col[i] = a.Value(i)
+		}
+	}
+	return col
+}
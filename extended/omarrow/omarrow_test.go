@@ -0,0 +1,73 @@
+package omarrow_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/apache/arrow/go/v14/arrow/memory"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+	"github.com/sttk/benchmarks_orderedmap/extended/omarrow"
+)
+
+func TestToRecord(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	cols := orderedmap.New[string, []int64]()
+	cols.Store("id", []int64{1, 2, 3})
+	cols.Store("count", []int64{10, 20, 30})
+	cols.Store("a", []int64{7, 8, 9})
+
+	rec, err := omarrow.ToRecord(mem, &cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Release()
+
+	if rec.NumRows() != 3 || rec.NumCols() != 3 {
+		t.Fatalf("record has %d rows and %d columns", rec.NumRows(), rec.NumCols())
+	}
+	for i, name := range []string{"id", "count", "a"} {
+		if rec.ColumnName(i) != name {
+			t.Errorf("column %d = %s", i, rec.ColumnName(i))
+		}
+	}
+
+	got, err := omarrow.FromRecord[int64](rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != cols.String() {
+		t.Errorf("FromRecord = %v", got)
+	}
+
+	if _, err := omarrow.FromRecord[string](rec); !errors.As(err, &omarrow.ColumnTypeError{}) {
+		t.Errorf("FromRecord[string]: err = %v", err)
+	}
+}
+
+func TestToRecord_string(t *testing.T) {
+	cols := orderedmap.New[string, []string]()
+	cols.Store("name", []string{"x", "y"})
+	cols.Store("city", []string{"p", "q"})
+
+	rec, err := omarrow.ToRecord(memory.DefaultAllocator, &cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Release()
+
+	got, err := omarrow.FromRecord[string](rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != "Map[name:[x y] city:[p q]]" {
+		t.Errorf("FromRecord = %v", got)
+	}
+
+	cols.Store("extra", []string{"z"})
+	if _, err := omarrow.ToRecord(memory.DefaultAllocator, &cols); !errors.Is(err, omarrow.ErrColumnLength) {
+		t.Errorf("ToRecord: err = %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package omhttp provides helpers to write ordered maps as JSON responses and
+// to read them from JSON requests.
+//
+// # Usage
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		om := orderedmap.New[string, int]()
+//		err := omhttp.ReadJSON(r, &om, omhttp.Limits{MaxBytes: 1 << 20})
+//		...
+//		err = omhttp.WriteJSON(w, http.StatusOK, &om, omhttp.WithGzip(r))
+//	}
+package omhttp
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+var (
+	// ErrTooLarge is an error which is returned by ReadJSON when the request
+	// body exceeds Limits.MaxBytes. It wraps orderedmap.ErrLimitExceeded.
+	ErrTooLarge = fmt.Errorf("omhttp: request body too large: %w", orderedmap.ErrLimitExceeded)
+
+	// ErrUnsupportedMediaType is an error which is returned by ReadJSON when
+	// the request has a Content-Type other than application/json, or has an
+	// unsupported Content-Encoding.
+	ErrUnsupportedMediaType = errors.New("omhttp: unsupported media type")
+)
+
+// Encoder is an interface which writes JSON to a writer in chunks, which is
+// implemented by *orderedmap.Map.
+type Encoder interface {
+	EncodeJSON(w io.Writer) error
+}
+
+// Decoder is an interface which reads a JSON object from a json.Decoder token
+// by token, which is implemented by *orderedmap.Map.
+type Decoder interface {
+	DecodeJSONFrom(dec *json.Decoder) error
+}
+
+// WriteOption is a function type which sets an option of WriteJSON.
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	gzip bool
+}
+
+// WithGzip is a function which returns an option to compress the response
+// with gzip if the specified request accepts it.
+func WithGzip(r *http.Request) WriteOption {
+	return func(o *writeOptions) {
+		o.gzip = acceptsGzip(r)
+	}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(enc), ";")
+		if strings.TrimSpace(name) == "gzip" && strings.TrimSpace(params) != "q=0" {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteJSON is a function which writes an ordered map as a JSON response with
+// the specified status code.
+//
+// The JSON is streamed to the response while entries are encoded, so the
+// whole JSON is not held in memory, and the response has no Content-Length.
+// The response has the Content-Type "application/json; charset=utf-8". If it
+// is compressed with WithGzip, it has the Content-Encoding "gzip".
+// Because the status code is written first, an error in encoding is returned
+// after a part of the response has been written.
+func WriteJSON(
+	w http.ResponseWriter,
+	status int,
+	om Encoder,
+	opts ...WriteOption,
+) error {
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	h.Add("Vary", "Accept-Encoding")
+	h.Del("Content-Length")
+
+	if !o.gzip {
+		w.WriteHeader(status)
+		return om.EncodeJSON(w)
+	}
+
+	h.Set("Content-Encoding", "gzip")
+	w.WriteHeader(status)
+	gz := gzip.NewWriter(w)
+	if err := om.EncodeJSON(gz); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+// Limits is a struct which holds limits of ReadJSON.
+//
+// MaxBytes is the maximum size of a request body after decompression. If it
+// is zero or less, the size is not limited.
+type Limits struct {
+	MaxBytes int64
+}
+
+// ReadJSON is a function which reads a JSON request body into an ordered map.
+// The body is decoded while it is read, so the whole body is not held in
+// memory. A body compressed with gzip is decompressed according to the
+// Content-Encoding header. A request without Content-Type is accepted as JSON.
+// An empty body is accepted as no entry, and data after the JSON object is
+// an error.
+func ReadJSON(r *http.Request, om Decoder, limits Limits) error {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mt, _, err := mime.ParseMediaType(ct)
+		if err != nil || mt != "application/json" {
+			return ErrUnsupportedMediaType
+		}
+	}
+
+	body := io.Reader(r.Body)
+	switch strings.ToLower(r.Header.Get("Content-Encoding")) {
+	case "", "identity":
+	case "gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		body = gz
+	default:
+		return ErrUnsupportedMediaType
+	}
+
+	if limits.MaxBytes > 0 {
+		body = &limitedReader{r: body, n: limits.MaxBytes}
+	}
+	dec := json.NewDecoder(body)
+	err := om.DecodeJSONFrom(dec)
+	if err == nil {
+		if _, err = dec.Token(); err == nil {
+			err = errTrailingData
+		}
+	}
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+var errTrailingData = errors.New("omhttp: data after the JSON object")
+
+// limitedReader is a struct which reads at most n bytes from a reader, and
+// returns ErrTooLarge if the reader has more.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > lr.n+1 {
+		p = p[:lr.n+1]
+	}
+	n, err := lr.r.Read(p)
+	if int64(n) > lr.n {
+		lr.n = 0
+		return 0, ErrTooLarge
+	}
+	lr.n -= int64(n)
+	return n, err
+}
@@ -0,0 +1,125 @@
+package omhttp_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+	"github.com/sttk/benchmarks_orderedmap/extended/omhttp"
+)
+
+func newMap() orderedmap.Map[string, int] {
+	om := orderedmap.New[string, int]()
+	om.Store("c", 1)
+	om.Store("a", 2)
+	om.Store("b", 3)
+	return om
+}
+
+func newMapPtr() *orderedmap.Map[string, int] {
+	om := newMap()
+	return &om
+}
+
+func TestWriteJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := omhttp.WriteJSON(rec, http.StatusCreated, newMapPtr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusCreated {
+		t.Errorf("Code = %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %s", ct)
+	}
+	if cl := rec.Header().Get("Content-Length"); cl != "" {
+		t.Errorf("Content-Length = %s", cl)
+	}
+	if rec.Body.String() != `{"c":1,"a":2,"b":3}` {
+		t.Errorf("Body = %s", rec.Body.String())
+	}
+}
+
+func TestWriteJSON_gzip(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip;q=0.8")
+	rec := httptest.NewRecorder()
+	err := omhttp.WriteJSON(rec, http.StatusOK, newMapPtr(), omhttp.WithGzip(req))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ce := rec.Header().Get("Content-Encoding"); ce != "gzip" {
+		t.Fatalf("Content-Encoding = %s", ce)
+	}
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs, _ := io.ReadAll(gz)
+	if string(bs) != `{"c":1,"a":2,"b":3}` {
+		t.Errorf("Body = %s", bs)
+	}
+
+	req.Header.Set("Accept-Encoding", "br")
+	rec = httptest.NewRecorder()
+	omhttp.WriteJSON(rec, http.StatusOK, newMapPtr(), omhttp.WithGzip(req))
+	if ce := rec.Header().Get("Content-Encoding"); ce != "" {
+		t.Errorf("Content-Encoding = %s", ce)
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"z":1,"y":2}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	om := orderedmap.New[string, int]()
+	if err := omhttp.ReadJSON(req, &om, omhttp.Limits{}); err != nil {
+		t.Fatal(err)
+	}
+	if om.String() != "Map[z:1 y:2]" {
+		t.Errorf("String = %s", om.String())
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(`{"x":3}`))
+	gz.Close()
+	req = httptest.NewRequest("POST", "/", &buf)
+	req.Header.Set("Content-Encoding", "gzip")
+	om = orderedmap.New[string, int]()
+	if err := omhttp.ReadJSON(req, &om, omhttp.Limits{MaxBytes: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if om.String() != "Map[x:3]" {
+		t.Errorf("String = %s", om.String())
+	}
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader(`{"x":3}`))
+	if err := omhttp.ReadJSON(req, &om, omhttp.Limits{MaxBytes: 6}); err != omhttp.ErrTooLarge {
+		t.Errorf("err = %v", err)
+	}
+	req = httptest.NewRequest("POST", "/", strings.NewReader(`{"x":3} {}`))
+	if err := omhttp.ReadJSON(req, &om, omhttp.Limits{}); err == nil {
+		t.Error("ReadJSON of trailing data succeeded")
+	}
+	req = httptest.NewRequest("POST", "/", strings.NewReader(``))
+	om = orderedmap.New[string, int]()
+	if err := omhttp.ReadJSON(req, &om, omhttp.Limits{}); err != nil || om.Len() != 0 {
+		t.Errorf("ReadJSON of an empty body = %v, %v", err, om)
+	}
+	big := `{"k":"` + strings.Repeat("x", 1<<16) + `"}`
+	req = httptest.NewRequest("POST", "/", strings.NewReader(big))
+	if err := omhttp.ReadJSON(req, &om, omhttp.Limits{MaxBytes: 1 << 15}); err != omhttp.ErrTooLarge {
+		t.Errorf("err = %v", err)
+	}
+	req = httptest.NewRequest("POST", "/", strings.NewReader(`x=3`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := omhttp.ReadJSON(req, &om, omhttp.Limits{}); err != omhttp.ErrUnsupportedMediaType {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package omotel provides a wrapper of an ordered map which records
+// OpenTelemetry spans and metrics of its operations.
+//
+// # Usage
+//
+//	om := orderedmap.New[string, int]()
+//	m, err := omotel.Wrap(&om, omotel.Options{SampleRate: 0.1})
+//	...
+//	err = m.Store(ctx, "foo", 1)
+//	v, ok, err := m.Load(ctx, "foo")
+//	bs, err := m.Marshal(ctx)
+//
+// Every sampled operation is recorded as a span named "orderedmap.<op>" and
+// its latency is recorded in the histogram "orderedmap.operation.duration"
+// with the attribute "orderedmap.op". The number of entries is set to spans
+// as "orderedmap.len", and the size of JSON outputs is recorded in the
+// histogram "orderedmap.marshal.size".
+package omotel
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+const instrumentationName = "github.com/sttk/benchmarks_orderedmap/extended/omotel"
+
+// Options is a struct which holds options of Wrap.
+//
+// TracerProvider and MeterProvider are the providers of spans and metrics.
+// If they are nil, the global providers of the otel package are used.
+//
+// SampleRate is the ratio of operations which are recorded, from 0 to 1.
+// Operations which are not sampled are passed to the map without any
+// overhead of recording. If SampleRate is zero or less, or 1 or more, all
+// operations are recorded.
+type Options struct {
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+	SampleRate     float64
+}
+
+// Map is a struct which wraps an ordered map and records its operations.
+// A Map is not safe for concurrent use, in the same way as the wrapped map.
+type Map[K comparable, V any] struct {
+	om       *orderedmap.Map[K, V]
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	size     metric.Int64Histogram
+	rate     float64
+}
+
+// Wrap is a function which creates a Map recording operations of the
+// specified ordered map.
+func Wrap[K comparable, V any](om *orderedmap.Map[K, V], opts Options) (*Map[K, V], error) {
+	tp := opts.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	mp := opts.MeterProvider
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	meter := mp.Meter(instrumentationName)
+
+	duration, err := meter.Float64Histogram("orderedmap.operation.duration",
+		metric.WithDescription("Latency of ordered map operations"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	size, err := meter.Int64Histogram("orderedmap.marshal.size",
+		metric.WithDescription("Size of JSON outputs of ordered maps"),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
+	rate := opts.SampleRate
+	if rate <= 0 || rate >= 1 {
+		rate = 1
+	}
+	return &Map[K, V]{
+		om:       om,
+		tracer:   tp.Tracer(instrumentationName),
+		duration: duration,
+		size:     size,
+		rate:     rate,
+	}, nil
+}
+
+// Unwrap is a method which returns the wrapped ordered map.
+func (m *Map[K, V]) Unwrap() *orderedmap.Map[K, V] {
+	return m.om
+}
+
+// Len is a method which returns the number of entries of the wrapped map.
+// This is not recorded.
+func (m *Map[K, V]) Len() int {
+	return m.om.Len()
+}
+
+// Load is a method which returns a value for a key with LoadCtx of the
+// wrapped map, recording the operation "load".
+func (m *Map[K, V]) Load(ctx context.Context, key K) (value V, ok bool, err error) {
+	if !m.sampled() {
+		return m.om.LoadCtx(ctx, key)
+	}
+	ctx, span, start := m.start(ctx, "load")
+	value, ok, err = m.om.LoadCtx(ctx, key)
+	span.SetAttributes(attribute.Bool("orderedmap.found", ok))
+	m.end(ctx, span, start, "load", err)
+	return
+}
+
+// Store is a method which sets a value for a key with StoreCtx of the
+// wrapped map, recording the operation "store".
+func (m *Map[K, V]) Store(ctx context.Context, key K, value V) error {
+	if !m.sampled() {
+		return m.om.StoreCtx(ctx, key, value)
+	}
+	ctx, span, start := m.start(ctx, "store")
+	err := m.om.StoreCtx(ctx, key, value)
+	m.end(ctx, span, start, "store", err)
+	return err
+}
+
+// Marshal is a method which returns the JSON of the wrapped map by MarshalJSON,
+// recording the operation "marshal" and the size of the output.
+func (m *Map[K, V]) Marshal(ctx context.Context) ([]byte, error) {
+	if !m.sampled() {
+		return m.om.MarshalJSON()
+	}
+	ctx, span, start := m.start(ctx, "marshal")
+	bs, err := m.om.MarshalJSON()
+	span.SetAttributes(attribute.Int("orderedmap.marshal.size", len(bs)))
+	m.size.Record(ctx, int64(len(bs)))
+	m.end(ctx, span, start, "marshal", err)
+	return bs, err
+}
+
+func (m *Map[K, V]) sampled() bool {
+	return m.rate >= 1 || rand.Float64() < m.rate
+}
+
+func (m *Map[K, V]) start(ctx context.Context, op string) (context.Context, trace.Span, time.Time) {
+	ctx, span := m.tracer.Start(ctx, "orderedmap."+op,
+		trace.WithAttributes(attribute.Int("orderedmap.len", m.om.Len())))
+	return ctx, span, time.Now()
+}
+
+func (m *Map[K, V]) end(ctx context.Context, span trace.Span, start time.Time, op string, err error) {
+	m.duration.Record(ctx, time.Since(start).Seconds(),
+		metric.WithAttributes(attribute.String("orderedmap.op", op)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,97 @@
+package omotel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+	"github.com/sttk/benchmarks_orderedmap/extended/omotel"
+)
+
+func TestWrap(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	om := orderedmap.New[string, int](orderedmap.WithWriter(func(k string, v int) error {
+		if k == "bad" {
+			return errors.New("bad key")
+		}
+		return nil
+	}))
+	m, err := omotel.Wrap(&om, omotel.Options{TracerProvider: tp, MeterProvider: mp})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	m.Store(ctx, "a", 1)
+	m.Store(ctx, "bad", 2)
+	if v, ok, err := m.Load(ctx, "a"); v != 1 || !ok || err != nil {
+		t.Errorf("Load = (%d, %t, %v)", v, ok, err)
+	}
+	bs, err := m.Marshal(ctx)
+	if string(bs) != `{"a":1,"bad":2}` || err != nil {
+		t.Errorf("Marshal = (%s, %v)", bs, err)
+	}
+
+	ended := spans.Ended()
+	names := []string{}
+	for _, s := range ended {
+		names = append(names, s.Name())
+	}
+	if len(names) != 4 || names[0] != "orderedmap.store" || names[2] != "orderedmap.load" ||
+		names[3] != "orderedmap.marshal" {
+		t.Errorf("spans = %v", names)
+	}
+	if ended[1].Status().Description != "orderedmap: store bad: bad key" {
+		t.Errorf("status = %v", ended[1].Status())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]uint64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, mt := range sm.Metrics {
+			switch data := mt.Data.(type) {
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					counts[mt.Name] += dp.Count
+				}
+			case metricdata.Histogram[int64]:
+				for _, dp := range data.DataPoints {
+					counts[mt.Name] += dp.Count
+				}
+			}
+		}
+	}
+	if counts["orderedmap.operation.duration"] != 4 || counts["orderedmap.marshal.size"] != 1 {
+		t.Errorf("metrics = %v", counts)
+	}
+}
+
+func TestWrap_sampling(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+
+	om := orderedmap.New[int, int]()
+	m, _ := omotel.Wrap(&om, omotel.Options{TracerProvider: tp, SampleRate: 0.1})
+	for i := 0; i < 1000; i++ {
+		m.Store(context.Background(), i, i)
+	}
+	if n := len(spans.Ended()); n == 0 || n > 300 {
+		t.Errorf("sampled spans = %d", n)
+	}
+	if m.Len() != 1000 {
+		t.Errorf("Len = %d", m.Len())
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package omsql provides builders of parameterized SQL statements from ordered
+// maps, whose columns are in the order of the maps' keys, and scanners of
+// query results into ordered maps, whose keys are in the order of the columns.
+//
+// # Usage
+//
+//	row := orderedmap.New[string, any]()
+//	row.Store("id", 1)
+//	row.Store("name", "foo")
+//
+//	query, args, err := omsql.Postgres.Insert("users", &row)
+//	// query: INSERT INTO "users" ("id", "name") VALUES ($1, $2)
+//	// args:  [1 foo]
+//	_, err = db.ExecContext(ctx, query, args...)
+//
+//	rows, err := db.QueryContext(ctx, "SELECT id, name FROM users")
+//	...
+//	users, err := omsql.ScanAll(rows)
+package omsql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+// Dialect is a type which specifies the placeholders and the quotation of
+// identifiers of generated statements.
+type Dialect int
+
+const (
+	// SQLite uses ? placeholders and double-quoted identifiers.
+	SQLite Dialect = iota
+
+	// MySQL uses ? placeholders and back-quoted identifiers.
+	MySQL
+
+	// Postgres uses $1, $2, ... placeholders and double-quoted identifiers.
+	Postgres
+)
+
+var (
+	// ErrNoColumns is an error which is returned when a row has no entries or
+	// there are no rows.
+	ErrNoColumns = errors.New("omsql: no columns")
+)
+
+// ColumnMismatchError is an error type which is returned by InsertBulk when a
+// row does not have the same columns as the first row.
+type ColumnMismatchError struct {
+	Row    int
+	Column string
+}
+
+func (err ColumnMismatchError) Error() string {
+	return fmt.Sprintf("omsql: row %d does not match the columns of row 0 at %q", err.Row, err.Column)
+}
+
+// Insert is a method which builds an INSERT statement of a row into the
+// specified table, and returns it with its arguments.
+// The columns are the keys of the row in the order of key insertions.
+func (d Dialect) Insert(table string, row *orderedmap.Map[string, any]) (string, []any, error) {
+	return d.InsertBulk(table, []*orderedmap.Map[string, any]{row})
+}
+
+// InsertBulk is a method which builds an INSERT statement of multiple rows
+// into the specified table, and returns it with its arguments.
+// The columns are the keys of the first row in the order of key insertions,
+// and every other row must have the same keys, in any order.
+func (d Dialect) InsertBulk(table string, rows []*orderedmap.Map[string, any]) (string, []any, error) {
+	if len(rows) == 0 || rows[0].Len() == 0 {
+		return "", nil, ErrNoColumns
+	}
+	cols := make([]string, 0, rows[0].Len())
+	rows[0].Range(func(col string, _ any) bool {
+		cols = append(cols, col)
+		return true
+	})
+
+	var b strings.Builder
+	b.WriteString("INSERT INTO ")
+	b.WriteString(d.quoteTable(table))
+	b.WriteString(" (")
+	for i, col := range cols {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(d.quote(col))
+	}
+	b.WriteString(") VALUES ")
+
+	args := make([]any, 0, len(cols)*len(rows))
+	for r, row := range rows {
+		if row.Len() != len(cols) {
+			return "", nil, ColumnMismatchError{Row: r, Column: extraColumn(row, cols)}
+		}
+		if r > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for i, col := range cols {
+			v, ok := row.Load(col)
+			if !ok {
+				return "", nil, ColumnMismatchError{Row: r, Column: col}
+			}
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			args = append(args, v)
+			b.WriteString(d.placeholder(len(args)))
+		}
+		b.WriteByte(')')
+	}
+	return b.String(), args, nil
+}
+
+func (d Dialect) placeholder(n int) string {
+	if d == Postgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+func (d Dialect) quote(ident string) string {
+	if d == MySQL {
+		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+// quoteTable is a method which quotes each part of a table name qualified
+// with a schema name, e.g. schema.table.
+func (d Dialect) quoteTable(table string) string {
+	parts := strings.Split(table, ".")
+	for i, p := range parts {
+		parts[i] = d.quote(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// extraColumn is a function which returns the first key of a row which is not
+// in the columns, or the first column which is not in the row.
+func extraColumn(row *orderedmap.Map[string, any], cols []string) string {
+	set := make(map[string]bool, len(cols))
+	for _, col := range cols {
+		set[col] = true
+	}
+	extra := ""
+	row.Range(func(col string, _ any) bool {
+		if !set[col] {
+			extra = col
+			return false
+		}
+		return true
+	})
+	if extra != "" {
+		return extra
+	}
+	for _, col := range cols {
+		if _, ok := row.Load(col); !ok {
+			return col
+		}
+	}
+	return ""
+}
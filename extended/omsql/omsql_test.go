@@ -0,0 +1,65 @@
+package omsql_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+	"github.com/sttk/benchmarks_orderedmap/extended/omsql"
+)
+
+func newRow(kvs ...any) *orderedmap.Map[string, any] {
+	om := orderedmap.New[string, any]()
+	for i := 0; i < len(kvs); i += 2 {
+		om.Store(kvs[i].(string), kvs[i+1])
+	}
+	return &om
+}
+
+func TestDialect_Insert(t *testing.T) {
+	row := newRow("id", 1, "name", "foo", "note", nil)
+
+	q, args, err := omsql.Postgres.Insert("public.users", row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != `INSERT INTO "public"."users" ("id", "name", "note") VALUES ($1, $2, $3)` {
+		t.Errorf("query = %s", q)
+	}
+	if fmt.Sprint(args) != "[1 foo <nil>]" {
+		t.Errorf("args = %v", args)
+	}
+
+	q, _, _ = omsql.MySQL.Insert("users", row)
+	if q != "INSERT INTO `users` (`id`, `name`, `note`) VALUES (?, ?, ?)" {
+		t.Errorf("query = %s", q)
+	}
+
+	if _, _, err := omsql.SQLite.Insert("users", newRow()); !errors.Is(err, omsql.ErrNoColumns) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestDialect_InsertBulk(t *testing.T) {
+	rows := []*orderedmap.Map[string, any]{
+		newRow("id", 1, "name", "a"),
+		newRow("name", "b", "id", 2),
+	}
+	q, args, err := omsql.Postgres.InsertBulk("t", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != `INSERT INTO "t" ("id", "name") VALUES ($1, $2), ($3, $4)` {
+		t.Errorf("query = %s", q)
+	}
+	if fmt.Sprint(args) != "[1 a 2 b]" {
+		t.Errorf("args = %v", args)
+	}
+
+	rows = append(rows, newRow("id", 3, "nick", "c"))
+	var mismatch omsql.ColumnMismatchError
+	if _, _, err := omsql.SQLite.InsertBulk("t", rows); !errors.As(err, &mismatch) || mismatch.Row != 2 || mismatch.Column != "name" {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package omsql
+
+import (
+	"database/sql"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+// ScanRow is a function which scans the current row of the specified rows
+// into a new ordered map, whose keys are the column names in the order of the
+// columns. Values are copied as database/sql does for *any destinations.
+// rows.Next must be called before this function as for rows.Scan.
+func ScanRow(rows *sql.Rows) (*orderedmap.Map[string, any], error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	return scanRow(rows, cols, make([]any, len(cols)), make([]any, len(cols)))
+}
+
+// ScanAll is a function which scans all remaining rows of the specified rows
+// into new ordered maps like ScanRow, reading the column names once, and
+// closes the rows.
+func ScanAll(rows *sql.Rows) ([]*orderedmap.Map[string, any], error) {
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+
+	var all []*orderedmap.Map[string, any]
+	for rows.Next() {
+		om, err := scanRow(rows, cols, values, ptrs)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, om)
+	}
+	return all, rows.Err()
+}
+
+func scanRow(rows *sql.Rows, cols []string, values, ptrs []any) (*orderedmap.Map[string, any], error) {
+	for i := range values {
+		values[i] = nil
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	om := orderedmap.New[string, any]()
+	for i, col := range cols {
+		om.Store(col, values[i])
+	}
+	return &om, nil
+}
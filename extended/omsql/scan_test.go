@@ -0,0 +1,89 @@
+package omsql_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/sttk/benchmarks_orderedmap/extended/omsql"
+)
+
+// fakeDriver is a database/sql driver whose every query returns the rows of
+// fakeRows.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error                               { return nil }
+func (fakeStmt) NumInput() int                              { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (fakeStmt) Query([]driver.Value) (driver.Rows, error)  { return &fakeRows{}, nil }
+
+type fakeRows struct{ i int }
+
+var fakeData = [][]driver.Value{
+	{int64(1), []byte("foo"), nil},
+	{int64(2), []byte("bar"), 2.5},
+}
+
+func (*fakeRows) Columns() []string { return []string{"zid", "name", "score"} }
+func (*fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(fakeData) {
+		return io.EOF
+	}
+	copy(dest, fakeData[r.i])
+	r.i++
+	return nil
+}
+
+func init() {
+	sql.Register("omsql_fake", fakeDriver{})
+}
+
+func TestScanAll(t *testing.T) {
+	db, err := sql.Open("omsql_fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT zid, name, score FROM t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := omsql.ScanAll(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("%d rows", len(all))
+	}
+	if s := fmt.Sprintf("%v %s", all[0], all[1].String()); s != "Map[zid:1 name:[102 111 111] score:<nil>] Map[zid:2 name:[98 97 114] score:2.5]" {
+		t.Errorf("rows = %s", s)
+	}
+
+	rows, err = db.Query("SELECT zid, name, score FROM t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	rows.Next()
+	om, err := omsql.ScanRow(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := om.Load("zid"); v != int64(1) {
+		t.Errorf("zid = %v", v)
+	}
+}
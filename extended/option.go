@@ -0,0 +1,369 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"context"
+	"time"
+)
+
+// Option is a function type which sets an option of a map created by New.
+type Option func(*options)
+
+type options struct {
+	internStrings bool
+	loader        any
+	writer        any
+	maxLen        int
+	maxWeight     int64
+	weigh         any
+	onEvict       any
+	timestamps    bool
+	traceLen      int
+	traceFn       any
+	historyLen    int
+	ratePerSecond float64
+	rateBurst     int
+	rateMode      RateLimitMode
+	nestedOrder   bool
+	quotas        map[string]int
+	accessOrder   bool
+	orderCmp      any
+	onExpire      any
+}
+
+// WithStringInterning is a function which returns an option to dedupe
+// identical string values through an interning table of the map.
+// A value is interned when it is stored, including when it is decoded by
+// UnmarshalJSON, if the value is a string or an interface holding a string.
+// This saves memory when many entries have a few distinct string values, at
+// the cost of a lookup per store.
+// The interning table is never shrunk until the map is discarded.
+func WithStringInterning() Option {
+	return func(o *options) {
+		o.internStrings = true
+	}
+}
+
+// WithLoader is a function which returns an option to fetch a value from an
+// external source when Load does not find a key in the map. A fetched value
+// is stored in the map. If fn returns an error, Load reports the key as not
+// found, and TryLoad returns the error wrapped in a KeyError.
+// The type parameters must be same with the map's, otherwise New panics.
+func WithLoader[K comparable, V any](fn func(key K) (V, error)) Option {
+	return func(o *options) {
+		o.loader = fn
+	}
+}
+
+// WithWriter is a function which returns an option to write an entry to an
+// external destination after it is stored in the map by Store or Swap.
+// Store ignores an error of fn, and TryStore returns it wrapped in a KeyError.
+// In either case, the entry stays stored in the map.
+// The type parameters must be same with the map's, otherwise New panics.
+func WithWriter[K comparable, V any](fn func(key K, value V) error) Option {
+	return func(o *options) {
+		o.writer = fn
+	}
+}
+
+// WithMaxLen is a function which returns an option to cap the number of
+// entries of the map. When an insertion makes the map exceed n entries, the
+// oldest entries are evicted with the reason EvictMaxLen.
+// If n is zero or less, the number of entries is not capped.
+func WithMaxLen(n int) Option {
+	return func(o *options) {
+		o.maxLen = n
+	}
+}
+
+// WithMaxWeight is a function which returns an option to cap the total
+// weight of entries of the map, where weigh returns the weight of an entry,
+// e.g. the size of its value in bytes. When an insertion or an update makes
+// the total weight exceed the budget, the oldest entries are evicted with the
+// reason EvictMaxWeight until the total weight is under the budget.
+// weigh must return the same weight for the same key and value.
+// The type parameters must be same with the map's, otherwise New panics.
+func WithMaxWeight[K comparable, V any](total int64, weigh func(key K, value V) int64) Option {
+	return func(o *options) {
+		o.maxWeight = total
+		o.weigh = weigh
+	}
+}
+
+// WithOnEvict is a function which returns an option to call fn with each
+// entry removed from the map by an eviction, so that resources held by values
+// can be released.
+// The type parameters must be same with the map's, otherwise New panics.
+func WithOnEvict[K comparable, V any](fn func(key K, value V, reason EvictReason)) Option {
+	return func(o *options) {
+		o.onEvict = fn
+	}
+}
+
+// WithTimestamps is a function which returns an option to record the time
+// when each entry is inserted and when its value is last updated, which are
+// returned by Entry#InsertedAt and Entry#UpdatedAt.
+// This costs a clock read per insertion and update, and a small allocation
+// per entry.
+func WithTimestamps() Option {
+	return func(o *options) {
+		o.timestamps = true
+	}
+}
+
+// WithNestedOrder is a function which returns an option to decode JSON
+// objects in values into *Map[string, any] instead of map[string]any, so that
+// the order of keys is preserved at every depth. The nested maps are created
+// with this option, too.
+// This takes effect only when the value type of the map is any.
+func WithNestedOrder() Option {
+	return func(o *options) {
+		o.nestedOrder = true
+	}
+}
+
+func (om *Map[K, V]) applyOptions(opts []Option) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	ext := &extension[K, V]{}
+	if o.internStrings {
+		ext.intern = make(map[string]string)
+	}
+	if o.loader != nil {
+		switch fn := o.loader.(type) {
+		case func(K) (V, error):
+			ext.loader = func(_ context.Context, key K) (V, error) {
+				return fn(key)
+			}
+		case func(context.Context, K) (V, error):
+			ext.loader = fn
+		default:
+			panic("orderedmap: the type of the loader does not match the map")
+		}
+	}
+	if o.writer != nil {
+		switch fn := o.writer.(type) {
+		case func(K, V) error:
+			ext.writer = func(_ context.Context, key K, value V) error {
+				return fn(key, value)
+			}
+		case func(context.Context, K, V) error:
+			ext.writer = fn
+		default:
+			panic("orderedmap: the type of the writer does not match the map")
+		}
+	}
+	if o.maxLen > 0 {
+		ext.maxLen = o.maxLen
+	}
+	if o.weigh != nil {
+		fn, ok := o.weigh.(func(K, V) int64)
+		if !ok {
+			panic("orderedmap: the type of the weigh function does not match the map")
+		}
+		ext.weigh = fn
+		ext.maxWeight = o.maxWeight
+	}
+	if o.onEvict != nil {
+		switch fn := o.onEvict.(type) {
+		case func(K, V, EvictReason):
+			ext.onEvict = func(_ context.Context, key K, value V, reason EvictReason) {
+				fn(key, value, reason)
+			}
+		case func(context.Context, K, V, EvictReason):
+			ext.onEvict = fn
+		default:
+			panic("orderedmap: the type of the eviction callback does not match the map")
+		}
+	}
+	if o.onExpire != nil {
+		fn, ok := o.onExpire.(func(K, V))
+		if !ok {
+			panic("orderedmap: the type of the expiration callback does not match the map")
+		}
+		ext.onExpire = fn
+	}
+	if o.quotas != nil {
+		if _, ok := any(*new(K)).(string); !ok {
+			panic("orderedmap: WithPrefixQuota needs a map with string keys")
+		}
+		ext.quota = newPrefixQuota[K, V](o.quotas)
+	}
+	ext.timestamps = o.timestamps
+	ext.nestedOrder = o.nestedOrder
+	ext.accessOrder = o.accessOrder
+	if o.orderCmp != nil {
+		fn, ok := o.orderCmp.(func(K, K) int)
+		if !ok {
+			panic("orderedmap: the type of the order function does not match the map")
+		}
+		ext.cmp = fn
+	}
+	if o.ratePerSecond > 0 {
+		ext.limiter = newTokenBucket(o.ratePerSecond, o.rateBurst, o.rateMode)
+	}
+	if o.historyLen > 0 {
+		ext.history = make(map[K][]Versioned[V])
+		ext.historyLen = o.historyLen
+	}
+	if o.traceLen > 0 || o.traceFn != nil {
+		t := &tracer[K]{}
+		if o.traceLen > 0 {
+			t.buf = make([]TraceRecord[K], o.traceLen)
+		}
+		if o.traceFn != nil {
+			fn, ok := o.traceFn.(func(TraceRecord[K]))
+			if !ok {
+				panic("orderedmap: the type of the trace function does not match the map")
+			}
+			t.fn = fn
+		}
+		ext.trace = t
+	}
+	om.ext = ext
+}
+
+// intern is a method which returns the interned value for a string value if
+// string interning is enabled, otherwise returns the value as it is.
+func (om *Map[K, V]) intern(value V) V {
+	if om.ext == nil || om.ext.intern == nil {
+		return value
+	}
+	s, ok := any(value).(string)
+	if !ok {
+		return value
+	}
+	if interned, exists := om.ext.intern[s]; exists {
+		return any(interned).(V)
+	}
+	om.ext.intern[s] = s
+	return value
+}
+
+// linkedExt is a method which updates optional states after an entry is
+// appended to the entry list, and evicts the oldest entries if the map
+// exceeds its caps.
+func (om *Map[K, V]) linkedExt(ent *Entry[K, V]) {
+	om.touched(ent.key)
+	if om.ext.timestamps {
+		now := time.Now()
+		ent.times = &entryTimes{inserted: now, updated: now}
+	}
+	om.trace(TraceInsert, ent.key)
+	om.recordPatch("add", ent)
+	if om.ext.cmp != nil {
+		om.placeSorted(ent)
+	}
+	if om.ext.weigh != nil {
+		om.ext.weight += om.ext.weigh(ent.key, ent.value)
+	}
+	if om.ext.quota != nil {
+		om.quotaLinked(ent)
+	}
+	if om.ext.maxLen > 0 {
+		for om.len > om.ext.maxLen {
+			om.evictFront(EvictMaxLen)
+		}
+	}
+	om.evictOverweight()
+}
+
+// movedExt is a method which updates optional states after an entry is moved
+// in the entry list.
+func (om *Map[K, V]) movedExt(ent *Entry[K, V]) {
+	om.trace(TraceMove, ent.key)
+	om.recordPatch("move", ent)
+	if om.ext.quota != nil {
+		om.quotaMoved(ent)
+	}
+}
+
+// unlinkedExt is a method which updates optional states after an entry is
+// removed from the entry list.
+func (om *Map[K, V]) unlinkedExt(ent *Entry[K, V]) {
+	om.touched(ent.key)
+	om.traceUnlinked(ent)
+	om.recordPatch("remove", ent)
+	if om.ext.history != nil {
+		delete(om.ext.history, ent.key)
+	}
+	if om.ext.quota != nil {
+		om.quotaUnlinked(ent)
+	}
+	if om.ext.weigh != nil {
+		om.ext.weight -= om.ext.weigh(ent.key, ent.value)
+	}
+}
+
+// updatedExt is a method which updates optional states after the value of an
+// entry is replaced, and evicts the oldest entries if the map exceeds its
+// weight budget.
+func (om *Map[K, V]) updatedExt(ent *Entry[K, V], old V) {
+	om.touched(ent.key)
+	om.accessed(ent)
+	if om.ext.timestamps {
+		ent.times.updated = time.Now()
+	}
+	om.trace(TraceUpdate, ent.key)
+	om.recordPatch("replace", ent)
+	if om.ext.history != nil {
+		om.keepHistory(ent.key, old)
+	}
+	if om.ext.weigh != nil {
+		om.ext.weight += om.ext.weigh(ent.key, ent.value) - om.ext.weigh(ent.key, old)
+		om.evictOverweight()
+	}
+}
+
+// TryLoad is a method which returns a value stored in this map for a key like
+// Load, and returns an error of the loader set by WithLoader wrapped in a
+// KeyError.
+func (om *Map[K, V]) TryLoad(key K) (value V, ok bool, err error) {
+	if om == nil {
+		return
+	}
+	ent, exists := om.m[key]
+	if exists && !ent.deleted && !om.expireIfDue(ent) {
+		om.accessed(ent)
+		return ent.value, true, nil
+	}
+	if om.ext != nil && om.ext.loader != nil {
+		return om.load(context.Background(), key)
+	}
+	return
+}
+
+// TryStore is a method which sets a value for a key like Store, and returns
+// an error of the writer set by WithWriter wrapped in a KeyError.
+// If a rate limit is set by WithRateLimit, this method is limited like
+// StoreCtx with context.Background.
+func (om *Map[K, V]) TryStore(key K, value V) error {
+	return om.StoreCtx(context.Background(), key, value)
+}
+
+// load is a method which fetches a value for a key with the loader and stores
+// it.
+func (om *Map[K, V]) load(ctx context.Context, key K) (value V, ok bool, err error) {
+	value, err = om.ext.loader(ctx, key)
+	if err != nil {
+		err = &KeyError{Op: "load", Key: key, Err: err}
+		return
+	}
+	value = om.intern(value)
+	om.storeCtx(ctx, key, value)
+	ok = true
+	return
+}
+
+// written is a method which calls the writer set by WithWriter, ignoring its
+// error.
+func (om *Map[K, V]) written(key K, value V) {
+	if om.ext != nil && om.ext.writer != nil {
+		om.ext.writer(context.Background(), key, value)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// Order is a struct which represents a policy of the order of entries of a
+// map, which is set by WithOrder. Use OrderInsertion, OrderAccess or
+// OrderFunc.
+type Order struct {
+	access bool
+	cmp    any
+}
+
+var (
+	// OrderInsertion keeps entries in the order of key insertions, which is
+	// the default.
+	OrderInsertion = Order{}
+
+	// OrderAccess keeps entries in the order of accesses, same with
+	// WithAccessOrder.
+	OrderAccess = Order{access: true}
+)
+
+// OrderFunc is a function which returns an Order to keep entries sorted by
+// their keys with the specified comparison function, which returns a
+// negative number, zero or a positive number if a is less than, equal to or
+// greater than b, like cmp.Compare. Keys which are equal by cmp are in the
+// order of their insertions.
+// The type parameter must be same with the map's key type, otherwise New
+// panics.
+func OrderFunc[K comparable](cmp func(a, b K) int) Order {
+	return Order{cmp: cmp}
+}
+
+// WithOrder is a function which returns an option to set the policy of the
+// order of entries of the map. The last of WithOrder and WithAccessOrder
+// takes effect.
+//
+// With OrderFunc, an inserted entry is placed at its sorted position instead
+// of the back, so every method sees the entries sorted, e.g. Range and
+// MarshalJSON iterate them in ascending order and WithMaxLen evicts the
+// smallest key. The position is searched from the back, so an insertion
+// takes a time proportional to the number of greater keys, and inserting
+// keys in ascending order takes a constant time. Updates of values do not
+// move entries.
+// Moving methods and SortFunc reorder entries regardless of the policy, and
+// later insertions are placed by comparing with entries from the back.
+func WithOrder(order Order) Option {
+	return func(o *options) {
+		o.accessOrder = order.access
+		o.orderCmp = order.cmp
+	}
+}
+
+// placeSorted is a method which moves an entry linked at the back to its
+// position in the order of the comparison function set by OrderFunc.
+// This must be called only when om.ext.cmp is not nil.
+func (om *Map[K, V]) placeSorted(ent *Entry[K, V]) {
+	prev := ent.prev
+	for prev != nil && om.ext.cmp(prev.key, ent.key) > 0 {
+		prev = prev.prev
+	}
+	if prev == ent.prev {
+		return
+	}
+	next := om.head
+	if prev != nil {
+		next = prev.next
+	}
+	om.moveBetween(ent, prev, next)
+}
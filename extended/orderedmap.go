@@ -0,0 +1,868 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package extended provides Map type which is a map preserving the
+// order of key insertions.
+// This package started as a copy of orderedmap v1.0.0, which is kept as it is
+// in v1_0_0, and has got the optional features of this repository, e.g.
+// eviction, TTL, rate limits and codecs.
+//
+// # Usage
+//
+// To create an ordered map is as follows:
+//
+//	om := orderedmap.New[string, string]()
+//
+// To add a map entry is as follows:
+//
+//	om.Store("foo", "hoge")
+//	prev, swapped := om.Swap("bar", "fuga")
+//	actual, loaded := om.LoadOrStore("baz", "fuga")
+//	actual, loaded, err := om.LoadOrStore("baz", func() (string, error) {
+//		return "fuga", nil
+//	})
+//
+// To get a value for a key is as follows:
+//
+//	om.Load("foo")
+//
+// To delete a map entry is as follows:
+//
+//	om.Delete("bar")
+//	v, deleted := om.LoadAndDelete("baz")
+//
+// To delete a map entry logically is as follows:
+//
+//	om.Ldelete("bar")
+//	v, deleted := om.LoadAndLdelete("baz")
+//
+// To iterate map entries is as follows. The order is same with key insertions:
+//
+//	om.Range(func(k, v) bool {
+//	    ...
+//	})
+//	for ent := om.Front(); ent != nil; ent = ent.Next() {
+//	    k := ent.Key(); v : = ent.Value(); ...
+//	}
+//	for ent := om.Back(); ent != nil; ent = ent.Prev() {
+//	    k := ent.Key(); v : = ent.Value(); ...
+//	}
+//	for ent := om.Front(); ent != nil; ent = ent.Next() {
+//	    k, v := ent.KV(); ...
+//	}
+//
+// To serialize the public contents of this map into a JSON string is as follows:
+//
+//	byteSeq, e := om.MarshalJSON()
+//
+// To deserialize a JSON string into an ordered map is as follows:
+//
+//	e := om.UnmarshalJSON(byteSeq)
+package extended
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Map is a struct which represents a map similar with Go standard map,
+// or sync.Map, but preserves the order in which keys were inserted.
+//
+// This map has same methods with sync.Map except CompareAndDelete and
+// CompareAndSwap. (But not support concurrent use.)
+// Its Range method processes a key and a value of each map entry, and the
+// processing order is same with the order of key insertions.
+// And this map also has methods: Front and Back, which iterate this map
+// entries in the order of key insertions and in that reverse order.
+//
+// The zero value of Map is an empty map ready to use. A nil *Map behaves
+// like an empty map for reading methods (Len, Load, Range, Front, Back, ...),
+// but writing methods panic on it as writing to a nil Go map does.
+type Map[K comparable, V any] struct {
+	m    map[K](*Entry[K, V])
+	head *Entry[K, V]
+	last *Entry[K, V]
+	len  int
+	seq  uint64
+	peak int // the largest number of keys the hash index has held
+	ext  *extension[K, V]
+}
+
+// extension is a struct which holds optional states of a Map.
+// This is separated from Map to keep Map small when no option is used.
+type extension[K comparable, V any] struct {
+	scope  *Scope[K, V]
+	dirty  *dirtyKeys[K]
+	intern map[string]string
+	loader func(context.Context, K) (V, error)
+	writer func(context.Context, K, V) error
+	ctx    context.Context
+
+	maxLen    int
+	maxWeight int64
+	weigh     func(K, V) int64
+	weight    int64
+	onEvict   func(context.Context, K, V, EvictReason)
+
+	timestamps bool
+	trace      *tracer[K]
+	history    map[K][]Versioned[V]
+	historyLen int
+	limiter    *tokenBucket
+
+	nestedOrder bool
+	quota       *prefixQuota[K, V]
+	accessOrder bool
+	cmp         func(K, K) int
+	onExpire    func(K, V)
+
+	fieldOptions map[K]FieldOptions
+	patch        *patchRecorder
+}
+
+// Entry is a struct which is a map element and holds a pair of key and value.
+// This struct also has methods: Next and Prev which moves next or previous entties
+// sequencially.
+type Entry[K comparable, V any] struct {
+	key     K
+	value   V
+	prev    *Entry[K, V]
+	next    *Entry[K, V]
+	deleted bool
+	seq     uint64
+	times   *entryTimes
+}
+
+// New is a function which creates a new ordered map, which is ampty.
+// Options can be specified with functions like WithStringInterning.
+func New[K comparable, V any](opts ...Option) Map[K, V] {
+	om := Map[K, V]{m: make(map[K](*Entry[K, V]))}
+	if len(opts) > 0 {
+		om.applyOptions(opts)
+	}
+	return om
+}
+
+// Len is a method which returns the number of entries in this map.
+func (om *Map[K, V]) Len() int {
+	if om == nil {
+		return 0
+	}
+	return om.len
+}
+
+// Store is a method which sets a value for a key
+func (om *Map[K, V]) Store(key K, value V) {
+	if om.limited() {
+		return
+	}
+	om.put(key, value)
+}
+
+// put is a method which sets a value for a key like Store without the rate
+// limit, for decoding methods filling this map.
+func (om *Map[K, V]) put(key K, value V) {
+	om.store(key, value)
+	om.written(key, value)
+}
+
+func (om *Map[K, V]) store(key K, value V) {
+	value = om.intern(value)
+	ent, exists := om.m[key]
+	if exists {
+		if !ent.deleted {
+			old := ent.value
+			ent.value = value
+			om.updated(ent, old)
+			return
+		}
+		ent.value = value
+		ent.deleted = false
+	} else {
+		ent = om.newEntry(key, value)
+	}
+
+	om.index(key, ent)
+	om.linkLast(ent)
+	return
+}
+
+// Swap is a method which sets a value for a key. If the key was present, this
+// map returns the previous value and the loaded flag which is set to true.
+func (om *Map[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	if om.limited() {
+		return
+	}
+	value = om.intern(value)
+	ent, exists := om.m[key]
+	if exists {
+		if !ent.deleted {
+			loaded = true
+			previous = ent.value
+			ent.value = value
+			om.updated(ent, previous)
+			om.written(key, value)
+			return
+		}
+		ent.deleted = false
+		ent.value = value
+	} else {
+		ent = om.newEntry(key, value)
+	}
+
+	om.index(key, ent)
+	om.linkLast(ent)
+	om.written(key, value)
+	return
+}
+
+// Load is a method which returns a value stored in this map for a key.
+// If no value was found for a key, the ok result is false.
+// If a loader is set by WithLoader, a value for a key which was not found is
+// fetched with it.
+func (om *Map[K, V]) Load(key K) (value V, ok bool) {
+	if om == nil {
+		return
+	}
+	if value, ok = om.loadStored(key); ok {
+		return
+	}
+	if om.ext != nil && om.ext.loader != nil {
+		value, ok, _ = om.load(context.Background(), key)
+	}
+	return
+}
+
+// loadStored is a method which returns a value stored in this map for a key
+// like Load without the loader.
+func (om *Map[K, V]) loadStored(key K) (value V, ok bool) {
+	ent, exists := om.m[key]
+	if exists && !ent.deleted && !om.expireIfDue(ent) {
+		om.accessed(ent)
+		return ent.value, true
+	}
+	return
+}
+
+// LoadOr is a method which returns the value stored in this map for a key
+// like Load, or the specified default value if the key is not found.
+func (om *Map[K, V]) LoadOr(key K, def V) V {
+	if value, ok := om.Load(key); ok {
+		return value
+	}
+	return def
+}
+
+// MustLoad is a method which returns the value stored in this map for a key
+// like Load, and panics with an error wrapping ErrKeyNotFound, whose message
+// has the key, if the key is not found.
+// This is for values which must be present, e.g. in test fixtures.
+func (om *Map[K, V]) MustLoad(key K) V {
+	value, ok := om.Load(key)
+	if !ok {
+		panic(fmt.Errorf("%w: %v", ErrKeyNotFound, key))
+	}
+	return value
+}
+
+// LoadMany is a method which returns values stored in this map for keys.
+// The values are in the same order as the keys, and the value for a key which
+// was not found is the zero value. The missing result has the keys which were
+// not found, in the order of the keys.
+func (om *Map[K, V]) LoadMany(keys []K) (values []V, missing []K) {
+	values = make([]V, len(keys))
+	for i, key := range keys {
+		v, ok := om.Load(key)
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+		values[i] = v
+	}
+	return
+}
+
+// LoadOrStore is a method which returns a value for a key if presents,
+// otherwise stores and returns a given value.
+// The loaded flag is true if the value was loaded, false if stored.
+func (om *Map[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	ent, exists := om.m[key]
+	if exists && !ent.deleted && om.expireIfDue(ent) {
+		exists = false
+	}
+	if exists && !ent.deleted {
+		om.accessed(ent)
+		actual = ent.value
+		loaded = true
+		return
+	}
+	if om.limited() {
+		return
+	}
+	if exists {
+		value = om.intern(value)
+		ent.deleted = false
+		ent.value = value
+	} else {
+		value = om.intern(value)
+		ent = om.newEntry(key, value)
+	}
+
+	actual = value
+
+	om.index(key, ent)
+	om.linkLast(ent)
+
+	return
+}
+
+// LoadOrStoreFunc is a method which returns a value for a key if presents,
+// otherwise executes a give function, then stores and returns the result
+// value.
+// The loaded flag is true if the value was loaded, false if stored.
+func (om *Map[K, V]) LoadOrStoreFunc(
+	key K,
+	fn func() (V, error),
+) (actual V, loaded bool, err error) {
+	ent, exists := om.m[key]
+	if exists && !ent.deleted && om.expireIfDue(ent) {
+		exists = false
+	}
+	if exists && !ent.deleted {
+		om.accessed(ent)
+		actual = ent.value
+		loaded = true
+		return
+	}
+	if om.limited() {
+		return
+	}
+	if exists {
+		ent.deleted = false
+		v, e := fn()
+		if e != nil {
+			err = e
+			return
+		}
+		actual = om.intern(v)
+		ent.value = actual
+	} else {
+		v, e := fn()
+		if e != nil {
+			err = e
+			return
+		}
+		actual = om.intern(v)
+		ent = om.newEntry(key, actual)
+	}
+
+	om.index(key, ent)
+	om.linkLast(ent)
+
+	return
+}
+
+// Delete is a method which deletes a value for a key.
+func (om *Map[K, V]) Delete(key K) {
+	if om.limited() {
+		return
+	}
+	om.remove(key)
+}
+
+// remove is a method which deletes a value for a key like Delete without the
+// rate limit.
+func (om *Map[K, V]) remove(key K) {
+	ent, exists := om.m[key]
+	if !exists {
+		return
+	}
+
+	delete(om.m, key)
+
+	if ent.deleted {
+		return
+	}
+	om.unlink(ent)
+}
+
+// DeleteAll is a method which deletes values for the specified keys.
+func (om *Map[K, V]) DeleteAll(keys ...K) {
+	if om.limited() {
+		return
+	}
+	for _, key := range keys {
+		om.remove(key)
+	}
+}
+
+// DeleteFunc is a method which deletes all entries for which pred returns
+// true in one pass in the order of key insertions, and returns the number of
+// deleted entries.
+// Unlike deleting entries during a walk with Front and Next, the iteration is
+// not broken by deletions. pred must not modify this map.
+func (om *Map[K, V]) DeleteFunc(pred func(key K, value V) bool) int {
+	if om == nil || om.limited() {
+		return 0
+	}
+	n := 0
+	for ent := om.head; ent != nil; {
+		next := ent.next
+		if pred(ent.key, ent.value) {
+			om.remove(ent.key)
+			n++
+		}
+		ent = next
+	}
+	return n
+}
+
+// Ldelete is a method which logically deletes a value for a key.
+func (om *Map[K, V]) Ldelete(key K) {
+	if om.limited() {
+		return
+	}
+	ent, exists := om.m[key]
+	if !exists {
+		return
+	}
+
+	if ent.deleted {
+		return
+	}
+	ent.deleted = true
+	om.unlink(ent)
+}
+
+// LoadAndDelete is a method which deletes a value for a key, and returns the
+// previous value if any.
+// The loaded flag is true if the key was present.
+func (om *Map[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	if om.limited() {
+		return
+	}
+	return om.loadAndDelete(key)
+}
+
+func (om *Map[K, V]) loadAndDelete(key K) (value V, loaded bool) {
+	ent, exists := om.m[key]
+	if !exists {
+		return
+	}
+
+	delete(om.m, key)
+
+	if ent.deleted {
+		return
+	}
+	om.unlink(ent)
+
+	value = ent.value
+	loaded = true
+	return
+}
+
+// LoadAndLdelete is a method which logically deletes a value for a key, and
+// returns the previous value if any.
+// The loaded flag is true if the key was present.
+func (om *Map[K, V]) LoadAndLdelete(key K) (value V, loaded bool) {
+	if om.limited() {
+		return
+	}
+	ent, exists := om.m[key]
+	if !exists {
+		return
+	}
+
+	if ent.deleted {
+		return
+	}
+	ent.deleted = true
+	om.unlink(ent)
+
+	value = ent.value
+	loaded = true
+	return
+}
+
+// FrontAndDelete is a method which deletes the first entry and returns it.
+// If this map has no entry, this method returns nil
+func (om *Map[K, V]) FrontAndDelete() *Entry[K, V] {
+	if om.limited() {
+		return nil
+	}
+	return om.frontAndDelete()
+}
+
+func (om *Map[K, V]) frontAndDelete() *Entry[K, V] {
+	ent := om.head
+	if ent == nil {
+		return nil
+	}
+
+	delete(om.m, ent.Key())
+	om.unlink(ent)
+
+	return ent
+}
+
+// FrontAndLdelete is a method which logically deletes the first entry and
+// returns it.
+// If this map has no entry, this method returns nil
+func (om *Map[K, V]) FrontAndLdelete() *Entry[K, V] {
+	if om.limited() {
+		return nil
+	}
+	ent := om.head
+	if ent == nil {
+		return nil
+	}
+
+	ent.deleted = true
+	om.unlink(ent)
+
+	return ent
+}
+
+// BackAndDelete is a method which deletes the last entry and returns it.
+// If this map has no entry, this method returns nil
+func (om *Map[K, V]) BackAndDelete() *Entry[K, V] {
+	if om.limited() {
+		return nil
+	}
+	return om.backAndDelete()
+}
+
+func (om *Map[K, V]) backAndDelete() *Entry[K, V] {
+	ent := om.last
+	if ent == nil {
+		return nil
+	}
+
+	delete(om.m, ent.Key())
+	om.unlink(ent)
+
+	return ent
+}
+
+// BackAndLdelete is a method which logically deletes the last entry and
+// returns it.
+// If this map has no entry, this method returns nil
+func (om *Map[K, V]) BackAndLdelete() *Entry[K, V] {
+	if om.limited() {
+		return nil
+	}
+	ent := om.last
+	if ent == nil {
+		return nil
+	}
+
+	ent.deleted = true
+	om.unlink(ent)
+
+	return ent
+}
+
+// PopFront is a method which deletes the first entry and returns its key and
+// value, like a queue. The ok flag is false if this map has no entry.
+// Expired entries set by StoreWithTTL are removed and skipped.
+func (om *Map[K, V]) PopFront() (key K, value V, ok bool) {
+	if om.limited() {
+		return
+	}
+	for om.head != nil && om.expireIfDue(om.head) {
+	}
+	ent := om.frontAndDelete()
+	if ent == nil {
+		return
+	}
+	return ent.key, ent.value, true
+}
+
+// PopBack is a method which deletes the last entry and returns its key and
+// value, like a stack. The ok flag is false if this map has no entry.
+// Expired entries set by StoreWithTTL are removed and skipped.
+func (om *Map[K, V]) PopBack() (key K, value V, ok bool) {
+	if om.limited() {
+		return
+	}
+	for om.last != nil && om.expireIfDue(om.last) {
+	}
+	ent := om.backAndDelete()
+	if ent == nil {
+		return
+	}
+	return ent.key, ent.value, true
+}
+
+// Pop is a method which deletes the entry for a key and returns its key and
+// value. The ok flag is false if the key is not present or has expired.
+func (om *Map[K, V]) Pop(key K) (k K, value V, ok bool) {
+	if om.limited() {
+		return
+	}
+	if ent, exists := om.m[key]; exists && !ent.deleted && om.expireIfDue(ent) {
+		return
+	}
+	value, ok = om.loadAndDelete(key)
+	if !ok {
+		return
+	}
+	return key, value, true
+}
+
+// Clear is a method which deletes all entries in this map.
+// The capacity of the hash index is kept for reuse.
+// If an eviction callback is set by WithOnEvict, it is called for each entry
+// with the reason EvictClear.
+func (om *Map[K, V]) Clear() {
+	if om.limited() {
+		return
+	}
+	if om.ext != nil && om.ext.dirty != nil {
+		for ent := om.head; ent != nil; ent = ent.next {
+			om.ext.dirty.add(ent.key)
+		}
+	}
+	if om.ext != nil && om.ext.trace != nil {
+		for ent := om.head; ent != nil; ent = ent.next {
+			om.trace(TraceClear, ent.key)
+		}
+	}
+	if om.ext != nil && om.ext.onEvict != nil {
+		for ent := om.head; ent != nil; ent = ent.next {
+			om.ext.onEvict(context.Background(), ent.key, ent.value, EvictClear)
+		}
+	}
+	if om.ext != nil && om.ext.patch != nil {
+		for ent := om.head; ent != nil; ent = ent.next {
+			om.recordPatch("remove", ent)
+		}
+	}
+	for key := range om.m {
+		delete(om.m, key)
+	}
+	om.head = nil
+	om.last = nil
+	om.len = 0
+	if om.ext != nil {
+		om.ext.weight = 0
+		if om.ext.quota != nil {
+			om.ext.quota.reset(nil)
+		}
+		if om.ext.history != nil {
+			om.ext.history = make(map[K][]Versioned[V])
+		}
+	}
+}
+
+// newEntry is a method which allocates a new entry, from the scope if this
+// map was created by a Scope.
+func (om *Map[K, V]) newEntry(key K, value V) *Entry[K, V] {
+	if om.ext != nil && om.ext.scope != nil {
+		return om.ext.scope.newEntry(key, value)
+	}
+	return &Entry[K, V]{key: key, value: value}
+}
+
+// index is a method which registers an entry for a key in the hash index.
+// The hash index is created lazily so that a zero-value Map is usable.
+func (om *Map[K, V]) index(key K, ent *Entry[K, V]) {
+	if om.m == nil {
+		om.m = make(map[K](*Entry[K, V]))
+	}
+	om.m[key] = ent
+	if n := len(om.m); n > om.peak {
+		om.peak = n
+	}
+}
+
+// linkLast is a method which appends an entry to the end of the entry list.
+func (om *Map[K, V]) linkLast(ent *Entry[K, V]) {
+	if om.last == nil {
+		om.head = ent
+	} else {
+		ent.prev = om.last
+		om.last.next = ent
+	}
+	om.last = ent
+	om.len++
+	om.seq++
+	ent.seq = om.seq
+	if ent.times != nil {
+		ent.times.expires = time.Time{}
+	}
+	if om.ext != nil {
+		om.linkedExt(ent)
+	}
+}
+
+// moveToBack is a method which moves an entry in the entry list to the back,
+// and renumbers its sequence number as if it were linked last.
+// This does not update optional states because the entry is not inserted.
+func (om *Map[K, V]) moveToBack(ent *Entry[K, V]) {
+	if ent == om.last {
+		return
+	}
+	if ent.prev != nil {
+		ent.prev.next = ent.next
+	} else {
+		om.head = ent.next
+	}
+	ent.next.prev = ent.prev
+
+	ent.prev = om.last
+	ent.next = nil
+	om.last.next = ent
+	om.last = ent
+	om.seq++
+	ent.seq = om.seq
+	if om.ext != nil {
+		om.movedExt(ent)
+	}
+}
+
+// unlink is a method which removes an entry from the entry list.
+func (om *Map[K, V]) unlink(ent *Entry[K, V]) {
+	if ent.prev != nil {
+		ent.prev.next = ent.next
+	} else {
+		om.head = ent.next
+	}
+
+	if ent.next != nil {
+		ent.next.prev = ent.prev
+	} else {
+		om.last = ent.prev
+	}
+
+	ent.next = nil
+	ent.prev = nil
+	om.len--
+	if om.ext != nil {
+		om.unlinkedExt(ent)
+	}
+}
+
+// updated is a method which is called after the value of an entry in the
+// entry list is replaced in place.
+func (om *Map[K, V]) updated(ent *Entry[K, V], old V) {
+	if om.ext != nil {
+		om.updatedExt(ent, old)
+	}
+}
+
+// Range is a method which calls the specified function: fn sequentially for
+// each key and value in this map.
+// If fn returns false, this method stops the iteration.
+func (om *Map[K, V]) Range(fn func(key K, value V) bool) {
+	if om == nil {
+		return
+	}
+	om.debugValidate()
+	for entry := om.head; entry != nil; entry = entry.next {
+		if !fn(entry.key, entry.value) {
+			break
+		}
+	}
+}
+
+// EachN is a method which calls the specified function: fn with keys and
+// values of up to n entries at a time, in the order of key insertions.
+// The slices passed to fn are reused between calls, so fn must not retain
+// them after it returns. If n is less than 1, it is treated as 1.
+func (om *Map[K, V]) EachN(n int, fn func(keys []K, values []V)) {
+	if om == nil {
+		return
+	}
+	if n < 1 {
+		n = 1
+	}
+	if om.len < n {
+		n = om.len
+	}
+	if n == 0 {
+		return
+	}
+
+	om.debugValidate()
+	keys := make([]K, 0, n)
+	values := make([]V, 0, n)
+	for ent := om.head; ent != nil; ent = ent.next {
+		keys = append(keys, ent.key)
+		values = append(values, ent.value)
+		if len(keys) == n {
+			fn(keys, values)
+			keys = keys[:0]
+			values = values[:0]
+		}
+	}
+	if len(keys) > 0 {
+		fn(keys, values)
+	}
+}
+
+// Front is a method which returns the head entry of this map.
+func (om *Map[K, V]) Front() *Entry[K, V] {
+	if om == nil {
+		return nil
+	}
+	om.debugValidate()
+	return om.head
+}
+
+// Back is a method which returns the last entry of this map.
+func (om *Map[K, V]) Back() *Entry[K, V] {
+	if om == nil {
+		return nil
+	}
+	om.debugValidate()
+	return om.last
+}
+
+// String is a method which returns a string of the content of this map.
+func (om Map[K, V]) String() string {
+	var buf strings.Builder
+	buf.WriteString("Map[")
+	ent := om.Front()
+	if ent != nil {
+		buf.WriteString(fmt.Sprintf("%v:%v", ent.Key(), ent.Value()))
+		for ent = ent.Next(); ent != nil; ent = ent.Next() {
+			buf.WriteString(fmt.Sprintf(" %v:%v", ent.Key(), ent.Value()))
+		}
+	}
+	buf.WriteString("]")
+	return buf.String()
+}
+
+// Prev is a method which returns the previous entry of this entry.
+// If this entry is a head entry of an ordered map, the returned value is nil.
+func (ent *Entry[K, V]) Prev() *Entry[K, V] {
+	return ent.prev
+}
+
+// Next is a method which returns the next entry of this entry.
+// If this entry is a last entry of an ordered map, the returned value is nil.
+func (ent *Entry[K, V]) Next() *Entry[K, V] {
+	return ent.next
+}
+
+// Key is a method which returns the key of this entry.
+func (ent *Entry[K, V]) Key() K {
+	return ent.key
+}
+
+// Value is a method which returns the value of this entry.
+func (ent *Entry[K, V]) Value() V {
+	return ent.value
+}
+
+// KV is a method which returns both the key and the value of this entry.
+// This is cheaper than calling Key and Value separately in iteration-heavy
+// loops.
+func (ent *Entry[K, V]) KV() (K, V) {
+	return ent.key, ent.value
+}
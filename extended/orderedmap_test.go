@@ -0,0 +1,2481 @@
+package extended_test
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"text/template"
+	"time"
+	"unsafe"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+func TestMap_zeroValue(t *testing.T) {
+	var om orderedmap.Map[string, int]
+
+	if om.Len() != 0 {
+		t.Errorf("Len() = %d", om.Len())
+	}
+	if v, ok := om.Load("a"); v != 0 || ok {
+		t.Errorf("Load(a) = (%d, %t)", v, ok)
+	}
+	om.Delete("a")
+	om.Ldelete("a")
+	if ent := om.FrontAndDelete(); ent != nil {
+		t.Errorf("FrontAndDelete() = %v", ent)
+	}
+
+	om.Store("a", 1)
+	if prev, loaded := om.Swap("b", 2); prev != 0 || loaded {
+		t.Errorf("Swap(b) = (%d, %t)", prev, loaded)
+	}
+	if actual, loaded := om.LoadOrStore("c", 3); actual != 3 || loaded {
+		t.Errorf("LoadOrStore(c) = (%d, %t)", actual, loaded)
+	}
+	if om.String() != "Map[a:1 b:2 c:3]" {
+		t.Errorf("String() = %s", om.String())
+	}
+
+	var om2 orderedmap.Map[string, int]
+	_, _, err := om2.LoadOrStoreFunc("a", func() (int, error) { return 1, nil })
+	if err != nil || om2.Len() != 1 {
+		t.Errorf("LoadOrStoreFunc(a) = %v, Len() = %d", err, om2.Len())
+	}
+
+	var om3 orderedmap.Map[string, int]
+	if err := om3.UnmarshalJSON([]byte(`{"x":1,"y":2}`)); err != nil {
+		t.Fatal(err)
+	}
+	if om3.String() != "Map[x:1 y:2]" {
+		t.Errorf("String() = %s", om3.String())
+	}
+}
+
+func TestMap_nilPointer(t *testing.T) {
+	var om *orderedmap.Map[string, int]
+
+	if om.Len() != 0 {
+		t.Errorf("Len() = %d", om.Len())
+	}
+	if v, ok := om.Load("a"); v != 0 || ok {
+		t.Errorf("Load(a) = (%d, %t)", v, ok)
+	}
+	if om.Front() != nil || om.Back() != nil {
+		t.Errorf("Front() or Back() is not nil")
+	}
+	om.Range(func(k string, v int) bool {
+		t.Errorf("Range called fn with %s", k)
+		return true
+	})
+	om.EachN(2, func(keys []string, vals []int) {
+		t.Errorf("EachN called fn with %v", keys)
+	})
+}
+
+func TestPool(t *testing.T) {
+	pool := orderedmap.NewPool[string, int](orderedmap.PoolOptions{MaxLen: 2})
+
+	om := pool.Get()
+	om.Store("a", 1)
+	om.Store("b", 2)
+	pool.Put(om)
+
+	om = pool.Get()
+	if om.Len() != 0 || om.Front() != nil || om.Back() != nil {
+		t.Errorf("Get() returned a non-empty map: %s", om.String())
+	}
+	if _, ok := om.Load("a"); ok {
+		t.Errorf("Load(a) found a cleared entry")
+	}
+	om.Store("c", 3)
+	if om.String() != "Map[c:3]" {
+		t.Errorf("String() = %s", om.String())
+	}
+
+	large := pool.Get()
+	large.Store("x", 1)
+	large.Store("y", 2)
+	large.Store("z", 3)
+	large.Delete("y")
+	large.Delete("z")
+	pool.Put(large)
+	for i := 0; i < 10; i++ {
+		if pool.Get() == large {
+			t.Fatal("Get() returned a map which had more entries than MaxLen")
+		}
+	}
+}
+
+func TestScope(t *testing.T) {
+	sc := orderedmap.NewScope[string, int](2)
+
+	om1 := sc.New()
+	om2 := sc.New()
+	om1.Store("a", 1)
+	om2.Store("b", 2)
+	om1.Store("c", 3)
+	om1.Delete("a")
+	om1.Store("a", 4)
+	if om1.String() != "Map[c:3 a:4]" || om2.String() != "Map[b:2]" {
+		t.Errorf("om1 = %s, om2 = %s", om1.String(), om2.String())
+	}
+
+	sc.Free()
+	if om1.Len() != 0 || om2.Len() != 0 {
+		t.Errorf("Len() after Free = %d, %d", om1.Len(), om2.Len())
+	}
+	om1.Store("x", 5)
+	if om1.String() != "Map[x:5]" {
+		t.Errorf("om1 = %s", om1.String())
+	}
+}
+
+func TestMap_DirtyKeys(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("a", 1)
+	om.Store("b", 2)
+	om.Store("c", 3)
+	if keys := om.DirtyKeys(); keys != nil {
+		t.Errorf("DirtyKeys() before Checkpoint = %v", keys)
+	}
+
+	om.Checkpoint()
+	if keys := om.DirtyKeys(); len(keys) != 0 {
+		t.Errorf("DirtyKeys() = %v", keys)
+	}
+
+	om.Store("c", 30)
+	om.Load("a")
+	om.Delete("b")
+	om.Store("d", 4)
+	om.Swap("c", 300)
+	if keys := om.DirtyKeys(); fmt.Sprint(keys) != "[c b d]" {
+		t.Errorf("DirtyKeys() = %v", keys)
+	}
+
+	om.Checkpoint()
+	om.Clear()
+	if keys := om.DirtyKeys(); fmt.Sprint(keys) != "[a c d]" {
+		t.Errorf("DirtyKeys() after Clear = %v", keys)
+	}
+}
+
+func TestMap_View(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("a", 1)
+	om.Store("b", 2)
+	om.Store("c", 3)
+
+	even := om.View(func(k string, v int) bool { return v%2 == 0 })
+	if even.String() != "View[b:2]" || even.Len() != 1 {
+		t.Errorf("View = %s", even.String())
+	}
+	if _, ok := even.Load("a"); ok {
+		t.Errorf("Load(a) found an unmatched entry")
+	}
+
+	om.Store("a", 10)
+	om.Delete("b")
+	om.Store("d", 4)
+	if even.String() != "View[a:10 d:4]" || even.Len() != 2 {
+		t.Errorf("View = %s", even.String())
+	}
+	if v, ok := even.Load("d"); v != 4 || !ok {
+		t.Errorf("Load(d) = (%d, %t)", v, ok)
+	}
+
+	loads := 0
+	lru := orderedmap.NewLRU[string, int](0, orderedmap.WithLoader(func(k string) (int, error) {
+		loads++
+		return 2, nil
+	}))
+	lru.Store("a", 2)
+	lru.Store("b", 4)
+	even = lru.View(func(k string, v int) bool { return v%2 == 0 })
+	if v, ok := even.Load("a"); v != 2 || !ok {
+		t.Errorf("Load(a) = (%d, %t)", v, ok)
+	}
+	if _, ok := even.Load("x"); ok {
+		t.Errorf("Load(x) found an absent entry")
+	}
+	if loads != 0 || lru.String() != "Map[a:2 b:4]" {
+		t.Errorf("the underlying map is changed: %d loads, %v", loads, lru)
+	}
+}
+
+func TestChain(t *testing.T) {
+	locals := orderedmap.New[string, int]()
+	locals.Store("x", 1)
+	locals.Store("y", 2)
+	globals := orderedmap.New[string, int]()
+	globals.Store("z", 30)
+	globals.Store("x", 10)
+	globals.Store("w", 40)
+
+	cv := orderedmap.Chain(&locals, &globals)
+	if cv.String() != "ChainView[x:1 y:2 z:30 w:40]" || cv.Len() != 4 {
+		t.Errorf("Chain = %s", cv.String())
+	}
+	if v, ok := cv.Load("x"); v != 1 || !ok {
+		t.Errorf("Load(x) = (%d, %t)", v, ok)
+	}
+	if v, ok := cv.Load("w"); v != 40 || !ok {
+		t.Errorf("Load(w) = (%d, %t)", v, ok)
+	}
+
+	locals.Delete("x")
+	if cv.String() != "ChainView[y:2 z:30 x:10 w:40]" {
+		t.Errorf("Chain = %s", cv.String())
+	}
+
+	loads := 0
+	cached := orderedmap.NewLRU[string, int](0, orderedmap.WithLoader(func(k string) (int, error) {
+		loads++
+		return 0, errors.New("not found")
+	}))
+	cached.Store("a", 1)
+	cached.Store("b", 2)
+	cv = orderedmap.Chain(&cached, &globals)
+	if cv.String() != "ChainView[a:1 b:2 z:30 x:10 w:40]" {
+		t.Errorf("Chain = %s", cv.String())
+	}
+	if v, ok := cv.Load("a"); v != 1 || !ok {
+		t.Errorf("Load(a) = (%d, %t)", v, ok)
+	}
+	if loads != 0 || cached.String() != "Map[a:1 b:2]" {
+		t.Errorf("the chained map is changed: %d loads, %v", loads, cached)
+	}
+
+	first := orderedmap.New[string, int]()
+	first.StoreWithTTL("k", 1, time.Millisecond)
+	second := orderedmap.New[string, int]()
+	second.Store("k", 2)
+	time.Sleep(2 * time.Millisecond)
+	cv = orderedmap.Chain(&first, &second)
+	if cv.String() != "ChainView[k:2]" || cv.Len() != 1 {
+		t.Errorf("Chain with an expired entry = %s, Len = %d", cv.String(), cv.Len())
+	}
+}
+
+func TestScoped(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("x", 0)
+
+	db := orderedmap.Scoped(&om, "db.")
+	http := orderedmap.Scoped(&om, "http.")
+	db.Store("port", 5432)
+	http.Store("port", 80)
+	db.Scoped("pool.").Store("size", 10)
+
+	if om.String() != "Map[x:0 db.port:5432 http.port:80 db.pool.size:10]" {
+		t.Errorf("om = %s", om.String())
+	}
+	if v, ok := http.Load("port"); v != 80 || !ok {
+		t.Errorf("Load(port) = (%d, %t)", v, ok)
+	}
+
+	keys := []string{}
+	db.Range(func(k string, v int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	if fmt.Sprint(keys) != "[port pool.size]" || db.Len() != 2 {
+		t.Errorf("keys = %v", keys)
+	}
+
+	db.Delete("port")
+	if _, ok := om.Load("db.port"); ok {
+		t.Errorf("db.port is not deleted")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	om := orderedmap.New[string, any]()
+	om.Store("host", "localhost")
+	om.Store("port", 8080)
+
+	s := "http://${host}:${port}/${path}"
+	if x := orderedmap.Expand(&om, s); x != "http://localhost:8080/${path}" {
+		t.Errorf("Expand = %s", x)
+	}
+	if x, err := orderedmap.ExpandWith(&om, s, orderedmap.MissingKeyEmpty); x != "http://localhost:8080/" || err != nil {
+		t.Errorf("ExpandWith(empty) = %s, %v", x, err)
+	}
+	_, err := orderedmap.ExpandWith(&om, s, orderedmap.MissingKeyFail)
+	if e, ok := err.(orderedmap.MissingKeyError); !ok || e.Key != "path" {
+		t.Errorf("ExpandWith(error) = %v", err)
+	}
+	if x := orderedmap.Expand(&om, "${host"); x != "${host" {
+		t.Errorf("Expand = %s", x)
+	}
+	if x := os.Expand("$host:${port}", orderedmap.Mapping(&om)); x != "localhost:8080" {
+		t.Errorf("os.Expand = %s", x)
+	}
+
+	tmpl := template.Must(template.New("").Funcs(orderedmap.FuncMap(&om)).Parse(
+		`{{expand "${host}:${port}/${path}"}} {{lookup "port"}}[{{lookup "path"}}]`))
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, nil); err != nil || buf.String() != "localhost:8080/${path} 8080[]" {
+		t.Errorf("template = %s, %v", buf.String(), err)
+	}
+	tmpl = template.Must(template.New("").Funcs(orderedmap.FuncMap(&om)).Parse(
+		`{{expandStrict "${host}/${path}"}}`))
+	if err := tmpl.Execute(io.Discard, nil); !errors.Is(err, orderedmap.ErrKeyNotFound) {
+		t.Errorf("template with a missing key = %v", err)
+	}
+}
+
+func TestMap_WithStringInterning(t *testing.T) {
+	om := orderedmap.New[int, string](orderedmap.WithStringInterning())
+	err := om.UnmarshalJSON([]byte(`{"1":"active","2":"active","3":"closed"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	om.Store(4, strings.Repeat("act", 1)+"ive")
+
+	v1, _ := om.Load(1)
+	v2, _ := om.Load(2)
+	v4, _ := om.Load(4)
+	if unsafe.StringData(v1) != unsafe.StringData(v2) ||
+		unsafe.StringData(v1) != unsafe.StringData(v4) {
+		t.Errorf("values are not interned")
+	}
+	if om.String() != "Map[1:active 2:active 3:closed 4:active]" {
+		t.Errorf("String = %s", om.String())
+	}
+
+	oa := orderedmap.New[int, any](orderedmap.WithStringInterning())
+	oa.Store(1, strings.Repeat("x", 2))
+	oa.Store(2, strings.Repeat("x", 2))
+	oa.Store(3, 1)
+	a1, _ := oa.Load(1)
+	a2, _ := oa.Load(2)
+	if unsafe.StringData(a1.(string)) != unsafe.StringData(a2.(string)) {
+		t.Errorf("values in interfaces are not interned")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	om := orderedmap.New[string, []int]()
+	orderedmap.Append(&om, "a", 1)
+	orderedmap.Append(&om, "b", 2, 3)
+	orderedmap.Append(&om, "a", 4, 5)
+	orderedmap.Append(&om, "c")
+	if om.String() != "Map[a:[1 4 5] b:[2 3] c:[]]" {
+		t.Errorf("String = %s", om.String())
+	}
+
+	om.Ldelete("a")
+	orderedmap.Append(&om, "a", 6)
+	if om.String() != "Map[b:[2 3] c:[] a:[6]]" {
+		t.Errorf("String = %s", om.String())
+	}
+
+	orderedmap.Append(&om, "d", 1, 2)
+	retained, _ := om.LoadAndLdelete("d")
+	orderedmap.Append(&om, "d", 9)
+	if fmt.Sprint(retained) != "[1 2]" {
+		t.Errorf("retained = %v", retained)
+	}
+
+	words := []string{"apple", "bear", "avocado", "cat", "banana"}
+	og := orderedmap.New[byte, []string]()
+	orderedmap.GroupAppend(&og, words, func(w string) byte { return w[0] })
+	if og.Len() != 3 {
+		t.Errorf("Len = %d", og.Len())
+	}
+	if v, _ := og.Load('a'); fmt.Sprint(v) != "[apple avocado]" {
+		t.Errorf("Load(a) = %v", v)
+	}
+	if v, _ := og.Load('b'); fmt.Sprint(v) != "[bear banana]" {
+		t.Errorf("Load(b) = %v", v)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	if v := orderedmap.Add(&om, "a", 3); v != 3 {
+		t.Errorf("Add = %d", v)
+	}
+	if v := orderedmap.Inc(&om, "b"); v != 1 {
+		t.Errorf("Inc = %d", v)
+	}
+	if v := orderedmap.Add(&om, "a", -5); v != -2 {
+		t.Errorf("Add = %d", v)
+	}
+	om.Ldelete("a")
+	if v := orderedmap.Inc(&om, "a"); v != 1 {
+		t.Errorf("Inc = %d", v)
+	}
+	if om.String() != "Map[b:1 a:1]" {
+		t.Errorf("String = %s", om.String())
+	}
+
+	om.StoreWithTTL("e", 100, time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+	if v := orderedmap.Inc(&om, "e"); v != 1 {
+		t.Errorf("Inc of an expired key = %d", v)
+	}
+	if ent := om.Back(); ent.Key() != "e" || !ent.ExpiresAt().IsZero() {
+		t.Errorf("back = %v, expires at %v", ent.Key(), ent.ExpiresAt())
+	}
+
+	oa := orderedmap.New[string, []int]()
+	oa.StoreWithTTL("e", []int{1}, time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+	orderedmap.Append(&oa, "e", 2)
+	if oa.String() != "Map[e:[2]]" {
+		t.Errorf("Append to an expired key = %v", oa)
+	}
+
+	of := orderedmap.New[string, float64]()
+	orderedmap.Add(&of, "x", 0.5)
+	if v := orderedmap.Add(&of, "x", 0.25); v != 0.75 {
+		t.Errorf("Add = %g", v)
+	}
+}
+
+func TestMap_TopN(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	for i, v := range []int{5, 1, 9, 3, 9, 7, 5, 2} {
+		om.Store(fmt.Sprintf("k%d", i), v)
+	}
+	less := func(a, b *orderedmap.Entry[string, int]) bool {
+		return a.Value() < b.Value()
+	}
+
+	keys := func(ents []*orderedmap.Entry[string, int]) string {
+		s := ""
+		for _, ent := range ents {
+			s += fmt.Sprintf("%s:%d ", ent.Key(), ent.Value())
+		}
+		return s
+	}
+	if s := keys(om.TopN(4, less)); s != "k2:9 k4:9 k5:7 k0:5 " {
+		t.Errorf("TopN(4) = %s", s)
+	}
+	if s := keys(om.TopN(100, less)); s != "k2:9 k4:9 k5:7 k0:5 k6:5 k3:3 k7:2 k1:1 " {
+		t.Errorf("TopN(100) = %s", s)
+	}
+	if ents := om.TopN(0, less); len(ents) != 0 {
+		t.Errorf("TopN(0) = %v", ents)
+	}
+}
+
+func TestTieredMap(t *testing.T) {
+	tm := orderedmap.NewTiered[string, int](nil, orderedmap.TieredOptions{
+		HotLen:      2,
+		PromotedLen: 1,
+	})
+	for i, k := range []string{"a", "b", "c", "d"} {
+		if err := tm.Store(k, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tm.Store("a", 10)
+	tm.Store("d", 13)
+
+	keys := ""
+	tm.Range(func(k string, v int) bool {
+		keys += fmt.Sprintf("%s:%d ", k, v)
+		return true
+	})
+	if keys != "a:10 b:1 c:2 d:13 " {
+		t.Errorf("Range = %s", keys)
+	}
+
+	tm.Load("d")
+	tm.Load("a")
+	tm.Load("a")
+	tm.Load("b")
+	tm.Load("x")
+	st := tm.Stats()
+	if st.Demotions != 2 || st.Promotions != 2 || st.HotHits != 2 ||
+		st.ColdHits != 2 || st.Misses != 1 {
+		t.Errorf("Stats = %+v", st)
+	}
+
+	tm.Delete("b")
+	tm.Delete("c")
+	if v, ok, _ := tm.Load("b"); ok {
+		t.Errorf("Load(b) = %d", v)
+	}
+	if n, _ := tm.Len(); n != 2 {
+		t.Errorf("Len = %d", n)
+	}
+}
+
+func TestMap_LoadMany(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("a", 1)
+	om.Store("b", 2)
+
+	values, missing := om.LoadMany([]string{"b", "x", "a", "y"})
+	if fmt.Sprint(values) != "[2 0 1 0]" || fmt.Sprint(missing) != "[x y]" {
+		t.Errorf("LoadMany = (%v, %v)", values, missing)
+	}
+
+	tm := orderedmap.NewTiered[string, int](nil, orderedmap.TieredOptions{HotLen: 1})
+	tm.Store("a", 1)
+	tm.Store("b", 2)
+	values, missing, err := tm.LoadMany([]string{"b", "x", "a"})
+	if err != nil || fmt.Sprint(values) != "[2 0 1]" || fmt.Sprint(missing) != "[x]" {
+		t.Errorf("LoadMany = (%v, %v, %v)", values, missing, err)
+	}
+	if st := tm.Stats(); st.HotHits != 1 || st.ColdHits != 1 || st.Misses != 1 {
+		t.Errorf("Stats = %+v", st)
+	}
+}
+
+func TestMap_WithLoaderAndWriter(t *testing.T) {
+	source := map[string]int{"a": 1, "b": 2}
+	written := []string{}
+	om := orderedmap.New[string, int](
+		orderedmap.WithLoader(func(k string) (int, error) {
+			v, ok := source[k]
+			if !ok {
+				return 0, fmt.Errorf("no %s", k)
+			}
+			return v, nil
+		}),
+		orderedmap.WithWriter(func(k string, v int) error {
+			written = append(written, fmt.Sprintf("%s:%d", k, v))
+			if k == "bad" {
+				return fmt.Errorf("cannot write %s", k)
+			}
+			return nil
+		}),
+	)
+
+	if v, ok := om.Load("b"); v != 2 || !ok {
+		t.Errorf("Load(b) = (%d, %t)", v, ok)
+	}
+	if v, ok := om.Load("x"); v != 0 || ok {
+		t.Errorf("Load(x) = (%d, %t)", v, ok)
+	}
+	if _, _, err := om.TryLoad("x"); err == nil || err.Error() != "orderedmap: load x: no x" {
+		t.Errorf("TryLoad(x) = %v", err)
+	}
+	source["b"] = 20
+	if v, ok := om.Load("b"); v != 2 || !ok {
+		t.Errorf("Load(b) = (%d, %t)", v, ok)
+	}
+
+	om.Store("c", 3)
+	om.Swap("c", 4)
+	if err := om.TryStore("bad", 5); err == nil {
+		t.Errorf("TryStore did not return an error")
+	}
+	orderedmap.Inc(&om, "c")
+	orderedmap.Add(&om, "d", 6)
+	if fmt.Sprint(written) != "[c:3 c:4 bad:5 c:5 d:6]" {
+		t.Errorf("written = %v", written)
+	}
+	if om.String() != "Map[b:2 c:5 bad:5 d:6]" {
+		t.Errorf("String = %s", om.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("New with a mismatched loader did not panic")
+		}
+	}()
+	orderedmap.New[string, string](orderedmap.WithLoader(func(k string) (int, error) {
+		return 0, nil
+	}))
+}
+
+func TestMap_contextCallbacks(t *testing.T) {
+	type ctxKey struct{}
+	got := []string{}
+	record := func(ctx context.Context, s string) {
+		id, _ := ctx.Value(ctxKey{}).(string)
+		got = append(got, s+"@"+id)
+	}
+	om := orderedmap.New[string, int](
+		orderedmap.WithMaxLen(1),
+		orderedmap.WithLoaderCtx(func(ctx context.Context, k string) (int, error) {
+			record(ctx, "load:"+k)
+			return len(k), ctx.Err()
+		}),
+		orderedmap.WithWriterCtx(func(ctx context.Context, k string, v int) error {
+			record(ctx, "write:"+k)
+			return nil
+		}),
+		orderedmap.WithOnEvictCtx(func(ctx context.Context, k string, v int, r orderedmap.EvictReason) {
+			record(ctx, "evict:"+k)
+		}),
+	)
+
+	ctx1 := context.WithValue(context.Background(), ctxKey{}, "1")
+	ctx2 := context.WithValue(context.Background(), ctxKey{}, "2")
+	if err := om.StoreCtx(ctx1, "a", 1); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := om.LoadCtx(ctx2, "bb"); v != 2 || !ok || err != nil {
+		t.Errorf("LoadCtx = (%d, %t, %v)", v, ok, err)
+	}
+	om.Store("c", 3)
+
+	canceled, cancel := context.WithCancel(ctx1)
+	cancel()
+	if _, ok, err := om.LoadCtx(canceled, "d"); ok || !errors.Is(err, context.Canceled) {
+		t.Errorf("LoadCtx with a canceled context = (%t, %v)", ok, err)
+	}
+
+	if fmt.Sprint(got) != "[write:a@1 load:bb@2 evict:a@2 evict:bb@ write:c@ load:d@1]" {
+		t.Errorf("got = %v", got)
+	}
+}
+
+func TestMap_WithRateLimit(t *testing.T) {
+	om := orderedmap.New[string, int](orderedmap.WithRateLimit(1, 2, orderedmap.RateLimitReject))
+	if err := om.TryStore("a", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := om.TryStore("b", 2); err != nil {
+		t.Fatal(err)
+	}
+	err := om.TryStore("c", 3)
+	if !errors.Is(err, orderedmap.ErrRateLimited) || !errors.Is(err, orderedmap.ErrLimitExceeded) {
+		t.Errorf("TryStore over the limit = %v", err)
+	}
+	om.Store("d", 4)
+	om.Delete("a")
+	om.Clear()
+	if v := orderedmap.Inc(&om, "a"); v != 0 {
+		t.Errorf("Inc over the limit = %d", v)
+	}
+	if v, loaded := om.LoadOrStore("e", 5); v != 0 || loaded {
+		t.Errorf("LoadOrStore over the limit = %v, %v", v, loaded)
+	}
+	if _, _, ok := om.Pop("b"); ok {
+		t.Error("Pop over the limit succeeded")
+	}
+	if om.MoveToFront("b") {
+		t.Error("MoveToFront over the limit succeeded")
+	}
+	if v, loaded := om.LoadOrStore("a", 0); v != 1 || !loaded {
+		t.Errorf("LoadOrStore of a present key = %v, %v", v, loaded)
+	}
+	err = om.DeleteCtx(context.Background(), "a")
+	if !errors.Is(err, orderedmap.ErrRateLimited) || err.Error() != "orderedmap: delete a: orderedmap: rate limited: orderedmap: limit exceeded" {
+		t.Errorf("DeleteCtx over the limit = %v", err)
+	}
+	if om.String() != "Map[a:1 b:2]" {
+		t.Errorf("String = %s", om.String())
+	}
+
+	sm := orderedmap.NewSync[string, int](orderedmap.WithRateLimit(1, 1, orderedmap.RateLimitReject))
+	sm.Store("a", 1)
+	if err := sm.DeleteCtx(context.Background(), "a"); !errors.Is(err, orderedmap.ErrRateLimited) {
+		t.Errorf("SyncMap DeleteCtx over the limit = %v", err)
+	}
+	if sm.CompareAndSwap("a", 1, 2) || sm.CompareAndDelete("a", 1) {
+		t.Error("CompareAndSwap or CompareAndDelete over the limit succeeded")
+	}
+	if sm.String() != "SyncMap[a:1]" {
+		t.Errorf("sm = %v", sm)
+	}
+
+	om2 := orderedmap.New[string, int](orderedmap.WithRateLimit(100, 1, orderedmap.RateLimitWait))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := om2.StoreCtx(context.Background(), strconv.Itoa(i), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	om2.Delete("0")
+	if err := om2.DeleteCtx(context.Background(), "1"); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 35*time.Millisecond {
+		t.Errorf("StoreCtx did not wait: %v", d)
+	}
+	if om2.String() != "Map[2:2]" {
+		t.Errorf("om2 = %v", om2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := om2.StoreCtx(ctx, "x", 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("StoreCtx with a canceled context = %v", err)
+	}
+}
+
+func TestMap_WithPrefixQuota(t *testing.T) {
+	evicted := []string{}
+	om := orderedmap.New[string, int](
+		orderedmap.WithPrefixQuota("a/", 2),
+		orderedmap.WithPrefixQuota("a/x/", 1),
+		orderedmap.WithPrefixQuota("b/", 0),
+		orderedmap.WithOnEvict(func(k string, v int, r orderedmap.EvictReason) {
+			evicted = append(evicted, k+":"+r.String())
+		}),
+	)
+	om.Store("a/1", 1)
+	om.Store("b/1", 1)
+	om.Store("a/x/1", 1)
+	om.Store("a/2", 2)
+	om.Store("a/1", 10)
+	om.Store("a/3", 3)
+	om.Store("a/x/2", 2)
+	om.Store("c", 0)
+	om.Store("b/2", 2)
+
+	if om.String() != "Map[b/1:1 a/2:2 a/3:3 a/x/2:2 c:0 b/2:2]" {
+		t.Errorf("String = %s", om.String())
+	}
+	if fmt.Sprint(evicted) != "[a/1:PrefixQuota a/x/1:PrefixQuota]" {
+		t.Errorf("evicted = %v", evicted)
+	}
+	st := om.Stats()
+	if st.Len != 6 || fmt.Sprint(st.PrefixCounts) != "map[a/:2 a/x/:1 b/:2]" {
+		t.Errorf("Stats = %+v", st)
+	}
+
+	om.Delete("b/1")
+	om.Clear()
+	if st := om.Stats(); fmt.Sprint(st.PrefixCounts) != "map[a/:0 a/x/:0 b/:0]" {
+		t.Errorf("Stats after Clear = %+v", st)
+	}
+
+	evicted = evicted[:0]
+	om.Store("a/1", 1)
+	om.Store("c", 0)
+	om.Store("a/2", 2)
+	om.MoveToBack("a/1")
+	om.Store("a/3", 3)
+	om.MoveToFront("a/3")
+	cp := om.Clone()
+	om.Store("a/4", 4)
+	om.SortFunc(func(x, y *orderedmap.Entry[string, int]) bool { return x.Key() > y.Key() })
+	om.Store("a/5", 5)
+	if om.String() != "Map[c:0 a/1:1 a/5:5]" {
+		t.Errorf("String after moves = %s", om.String())
+	}
+	if fmt.Sprint(evicted) != "[a/2:PrefixQuota a/3:PrefixQuota a/4:PrefixQuota]" {
+		t.Errorf("evicted after moves = %v", evicted)
+	}
+	cp.Store("a/6", 6)
+	if cp.String() != "Map[c:0 a/1:1 a/6:6]" {
+		t.Errorf("Clone = %s", cp.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("New with int keys did not panic")
+		}
+	}()
+	orderedmap.New[int, int](orderedmap.WithPrefixQuota("a", 1))
+}
+
+func TestMap_Page(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	for i, k := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		om.Store(k, i)
+	}
+	keys := func(ps []orderedmap.Pair[string, int]) string {
+		s := ""
+		for _, p := range ps {
+			s += p.Key
+		}
+		return s
+	}
+
+	page, next := om.Page("", 3)
+	if keys(page) != "abc" || next == "" {
+		t.Errorf("Page 1 = (%s, %q)", keys(page), next)
+	}
+
+	om.Delete("c")
+	om.Delete("a")
+	om.Store("a", 10)
+	page, next = om.Page(next, 3)
+	if keys(page) != "def" || next == "" {
+		t.Errorf("Page 2 = (%s, %q)", keys(page), next)
+	}
+
+	om.Ldelete("f")
+	om.Store("f", 11)
+	page, next = om.Page(next, 3)
+	if keys(page) != "gaf" || next != "" {
+		t.Errorf("Page 3 = (%s, %q)", keys(page), next)
+	}
+
+	if page, next := om.Page("!", 3); page != nil || next != "" {
+		t.Errorf("Page with an invalid token = (%v, %q)", page, next)
+	}
+	if page, next := om.Page("", 0); page != nil || next != "" {
+		t.Errorf("Page with zero limit = (%v, %q)", page, next)
+	}
+
+	all := ""
+	for tok := ""; ; {
+		var page []orderedmap.Pair[string, int]
+		page, tok = om.Page(tok, 2)
+		all += keys(page)
+		if tok == "" {
+			break
+		}
+	}
+	if all != "bdegaf" {
+		t.Errorf("all pages = %s", all)
+	}
+}
+
+func TestSyncMap(t *testing.T) {
+	sm := orderedmap.NewSync[int, int]()
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				k := g*1000 + i
+				sm.Store(k, i)
+				if v, ok := sm.Load(k); !ok || v != i {
+					t.Errorf("Load(%d) = (%d, %t)", k, v, ok)
+					return
+				}
+				if i%2 == 1 {
+					sm.Delete(k)
+				}
+				sm.Range(func(k, v int) bool {
+					sm.Load(k)
+					return k < 10
+				})
+			}
+		}(g)
+	}
+	wg.Wait()
+	if sm.Len() != 2000 {
+		t.Errorf("Len = %d", sm.Len())
+	}
+
+	prev := -1
+	for _, p := range sm.Snapshot() {
+		if p.Key%1000 <= prev%1000 && p.Key/1000 == prev/1000 {
+			t.Errorf("keys of a goroutine are out of order: %d after %d", p.Key, prev)
+		}
+		prev = p.Key
+	}
+
+	if !sm.CompareAndSwap(0, 0, 100) || sm.CompareAndSwap(0, 0, 200) {
+		t.Errorf("CompareAndSwap failed")
+	}
+	if sm.CompareAndDelete(0, 0) || !sm.CompareAndDelete(0, 100) {
+		t.Errorf("CompareAndDelete failed")
+	}
+	if actual, loaded := sm.LoadOrStore(2, 5); actual != 2 || !loaded {
+		t.Errorf("LoadOrStore = (%d, %t)", actual, loaded)
+	}
+	sm.Clear()
+	sm.Store(1, 1)
+	if sm.String() != "SyncMap[1:1]" {
+		t.Errorf("String = %s", sm.String())
+	}
+}
+
+func TestSyncMap_Load_loader(t *testing.T) {
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	sm := orderedmap.NewSync[string, int](orderedmap.WithLoader(func(k string) (int, error) {
+		if k == "slow" {
+			close(fetching)
+			<-release
+		}
+		if k == "bad" {
+			return 0, errors.New("no bad")
+		}
+		return len(k), nil
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if v, ok := sm.Load("slow"); v != 40 || !ok {
+			t.Errorf("Load(slow) = (%d, %t)", v, ok)
+		}
+	}()
+	<-fetching
+	sm.Store("a", 1)
+	if v, ok := sm.Load("ab"); v != 2 || !ok {
+		t.Errorf("Load(ab) = (%d, %t)", v, ok)
+	}
+	sm.Store("slow", 40)
+	close(release)
+	<-done
+
+	if v, ok := sm.Load("bad"); v != 0 || ok {
+		t.Errorf("Load(bad) = (%d, %t)", v, ok)
+	}
+	if sm.String() != "SyncMap[a:1 ab:2 slow:40]" {
+		t.Errorf("sm = %v", sm)
+	}
+}
+
+func TestSyncMap_Load_dedup(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	sm := orderedmap.NewSync[string, int](orderedmap.WithLoader(func(k string) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return len(k), nil
+	}))
+
+	var wg sync.WaitGroup
+	results := make([]int, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = sm.Load("abc")
+		}(i)
+	}
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("calls = %d", n)
+	}
+	for _, r := range results {
+		if r != 3 {
+			t.Errorf("results = %v", results)
+			break
+		}
+	}
+	if v, ok := sm.Load("abcd"); v != 4 || !ok || atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("Load after the flight = (%d, %t), calls = %d", v, ok, calls)
+	}
+}
+
+func joinKeys(m orderedmap.ReadOnly[string, int]) string {
+	var keys []string
+	m.Range(func(key string, value int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return strings.Join(keys, ",")
+}
+
+func TestMap_ForEachParallel(t *testing.T) {
+	om := orderedmap.New[int, int]()
+	for i := 0; i < 1000; i++ {
+		om.Store(i, i*2)
+	}
+
+	for _, workers := range []int{0, 1, 3, 2000} {
+		var mu sync.Mutex
+		seen := make(map[int]int)
+		om.ForEachParallel(workers, func(k, v int) {
+			mu.Lock()
+			seen[k] = v
+			mu.Unlock()
+		})
+		if len(seen) != 1000 {
+			t.Errorf("workers=%d: %d entries visited", workers, len(seen))
+		}
+		for k, v := range seen {
+			if v != k*2 {
+				t.Errorf("workers=%d: value of %d = %d", workers, k, v)
+			}
+		}
+	}
+
+	empty := orderedmap.New[int, int]()
+	empty.ForEachParallel(4, func(k, v int) { t.Error("called for an empty map") })
+}
+
+func TestMap_SortFunc(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("c", 2)
+	om.Store("a", 3)
+	om.Store("d", 1)
+	om.Store("b", 2)
+
+	om.SortFunc(func(a, b *orderedmap.Entry[string, int]) bool {
+		return a.Value() < b.Value()
+	})
+	if om.String() != "Map[d:1 c:2 b:2 a:3]" {
+		t.Errorf("SortFunc = %v", om)
+	}
+	if ent := om.Back(); ent.Key() != "a" || ent.Prev().Key() != "b" {
+		t.Errorf("back entries = %v, %v", ent.Key(), ent.Prev().Key())
+	}
+
+	orderedmap.SortKeys(&om)
+	if om.String() != "Map[a:3 b:2 c:2 d:1]" {
+		t.Errorf("SortKeys = %v", om)
+	}
+
+	om.Store("0", 0)
+	om.Delete("b")
+	if om.String() != "Map[a:3 c:2 d:1 0:0]" {
+		t.Errorf("after Store and Delete = %v", om)
+	}
+}
+
+func TestNewLRU(t *testing.T) {
+	var evicted []string
+	om := orderedmap.NewLRU[string, int](3,
+		orderedmap.WithOnEvict(func(k string, v int, r orderedmap.EvictReason) {
+			evicted = append(evicted, fmt.Sprintf("%s:%d:%v", k, v, r))
+		}))
+	om.Store("a", 1)
+	om.Store("b", 2)
+	om.Store("c", 3)
+
+	om.Load("a")
+	om.Store("d", 4)
+	if om.String() != "Map[c:3 a:1 d:4]" {
+		t.Errorf("om = %v", om)
+	}
+
+	om.Store("c", 30)
+	om.LoadOrStore("a", 0)
+	om.Store("e", 5)
+	if om.String() != "Map[c:30 a:1 e:5]" {
+		t.Errorf("om = %v", om)
+	}
+	if fmt.Sprint(evicted) != "[b:2:MaxLen d:4:MaxLen]" {
+		t.Errorf("evicted = %v", evicted)
+	}
+
+	om.Range(func(k string, v int) bool { return true })
+	if ent := om.Front(); ent.Key() != "c" {
+		t.Errorf("front = %v", ent.Key())
+	}
+}
+
+func TestWithAccessOrder(t *testing.T) {
+	om := orderedmap.New[string, int](orderedmap.WithAccessOrder())
+	om.Store("a", 1)
+	om.Store("b", 2)
+	om.Store("c", 3)
+
+	om.Load("a")
+	om.Load("x")
+	om.Store("b", 20)
+	if om.String() != "Map[c:3 a:1 b:20]" {
+		t.Errorf("om = %v", om)
+	}
+	v, loaded, err := om.LoadOrStoreFunc("c", func() (int, error) {
+		t.Error("fn is called for a present key")
+		return 0, nil
+	})
+	if v != 3 || !loaded || err != nil {
+		t.Errorf("LoadOrStoreFunc = (%v, %v, %v)", v, loaded, err)
+	}
+	if om.String() != "Map[a:1 b:20 c:3]" {
+		t.Errorf("om = %v", om)
+	}
+
+	sm := orderedmap.NewSync[string, int](orderedmap.WithAccessOrder())
+	sm.Store("a", 1)
+	sm.Store("b", 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				sm.Load("a")
+				sm.LoadOrStore("b", 0)
+			}
+		}()
+	}
+	wg.Wait()
+	if sm.String() != "SyncMap[a:1 b:2]" {
+		t.Errorf("sm = %v", sm)
+	}
+}
+
+func TestMap_WriteTable(t *testing.T) {
+	om := orderedmap.New[string, any]()
+	om.Store("name", "foo")
+	om.Store("id", 12)
+	om.Store("a|b", "x\ny")
+
+	var buf strings.Builder
+	if err := om.WriteTable(&buf, orderedmap.TableOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := "KEY   VALUE\nname  foo\nid    12\na|b   x y\n"
+	if buf.String() != want {
+		t.Errorf("WriteTable =\n%s", buf.String())
+	}
+
+	buf.Reset()
+	om.WriteTable(&buf, orderedmap.TableOptions{Markdown: true, KeyHeader: "k", ValueHeader: "v"})
+	want = "| k    | v |\n| ---- | --- |\n| name | foo |\n| id   | 12 |\n| a\\|b | x y |\n"
+	if buf.String() != want {
+		t.Errorf("WriteTable with Markdown =\n%s", buf.String())
+	}
+
+	om2 := orderedmap.New[string, string]()
+	om2.Store("<b>", "a & *b*")
+	buf.Reset()
+	om2.WriteTable(&buf, orderedmap.TableOptions{Markdown: true, NoHeader: true})
+	if buf.String() != "| \\<b\\> | a & \\*b\\* |\n" {
+		t.Errorf("WriteTable with Markdown =\n%s", buf.String())
+	}
+
+	buf.Reset()
+	om2.WriteHTMLTable(&buf, orderedmap.TableOptions{})
+	want = "<table>\n<thead><tr><th>KEY</th><th>VALUE</th></tr></thead>\n<tbody>\n" +
+		"<tr><td>&lt;b&gt;</td><td>a &amp; *b*</td></tr>\n</tbody>\n</table>\n"
+	if buf.String() != want {
+		t.Errorf("WriteHTMLTable =\n%s", buf.String())
+	}
+
+	buf.Reset()
+	om2.WriteHTMLDefinitionList(&buf, orderedmap.TableOptions{})
+	if buf.String() != "<dl>\n<dt>&lt;b&gt;</dt><dd>a &amp; *b*</dd>\n</dl>\n" {
+		t.Errorf("WriteHTMLDefinitionList =\n%s", buf.String())
+	}
+}
+
+func TestMap_StoreWithTTL(t *testing.T) {
+	var expired, evicted []string
+	om := orderedmap.New[string, int](
+		orderedmap.WithExpireFunc(func(k string, v int) {
+			expired = append(expired, k)
+		}),
+		orderedmap.WithOnEvict(func(k string, v int, r orderedmap.EvictReason) {
+			evicted = append(evicted, k+":"+r.String())
+		}),
+	)
+	om.StoreWithTTL("a", 1, 10*time.Millisecond)
+	om.StoreWithTTL("b", 2, time.Hour)
+	om.StoreWithTTL("c", 3, 10*time.Millisecond)
+	om.Store("d", 4)
+	if ent := om.Front(); ent.ExpiresAt().IsZero() {
+		t.Error("ExpiresAt is zero")
+	}
+	if ent := om.Back(); !ent.ExpiresAt().IsZero() {
+		t.Errorf("ExpiresAt = %v", ent.ExpiresAt())
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if v, ok := om.Load("a"); ok {
+		t.Errorf("Load of an expired key = %v", v)
+	}
+	if om.String() != "Map[b:2 c:3 d:4]" {
+		t.Errorf("om = %v", om)
+	}
+	if n := om.PurgeExpired(); n != 1 {
+		t.Errorf("PurgeExpired = %d", n)
+	}
+	if om.String() != "Map[b:2 d:4]" {
+		t.Errorf("om = %v", om)
+	}
+	if fmt.Sprint(expired, evicted) != "[a c] [a:Expired c:Expired]" {
+		t.Errorf("expired = %v, evicted = %v", expired, evicted)
+	}
+
+	om.StoreWithTTL("a", 10, 10*time.Millisecond)
+	om.Delete("a")
+	om.Store("a", 11)
+	time.Sleep(20 * time.Millisecond)
+	if v, ok := om.Load("a"); !ok || v != 11 {
+		t.Errorf("Load of a key stored again = %v, %v", v, ok)
+	}
+
+	om.StoreWithTTL("e", 5, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	v, loaded, err := om.LoadOrStoreFunc("e", func() (int, error) { return 50, nil })
+	if v != 50 || loaded || err != nil {
+		t.Errorf("LoadOrStoreFunc of an expired key = (%v, %v, %v)", v, loaded, err)
+	}
+	if ent := om.Back(); ent.Key() != "e" || !ent.ExpiresAt().IsZero() {
+		t.Errorf("back = %v, expires at %v", ent.Key(), ent.ExpiresAt())
+	}
+}
+
+func TestSyncMap_StartJanitor(t *testing.T) {
+	sm := orderedmap.NewSync[string, int]()
+	var _ io.Closer = sm
+	sm.StoreWithTTL("a", 1, 10*time.Millisecond)
+	sm.Store("b", 2)
+	sm.StoreWithTTL("c", 3, 10*time.Millisecond)
+	stop, err := sm.StartJanitor(orderedmap.JanitorOptions{
+		Interval:  5 * time.Millisecond,
+		Jitter:    time.Millisecond,
+		BatchSize: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	deadline := time.Now().Add(time.Second)
+	for sm.Len() != 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if sm.String() != "SyncMap[b:2]" {
+		t.Errorf("sm = %v", sm)
+	}
+	if n := sm.Reclaimed(); n != 2 {
+		t.Errorf("Reclaimed = %d", n)
+	}
+	stop()
+	stop()
+
+	sm.StoreWithTTL("d", 4, time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+	if n := sm.PurgeExpired(); n != 1 {
+		t.Errorf("PurgeExpired = %d", n)
+	}
+	if n := sm.Reclaimed(); n != 3 {
+		t.Errorf("Reclaimed = %d", n)
+	}
+}
+
+func TestSyncMap_Close(t *testing.T) {
+	sm := orderedmap.NewSync[string, int]()
+	for i := 0; i < 3; i++ {
+		if _, err := sm.StartJanitor(orderedmap.JanitorOptions{Interval: time.Millisecond}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sm.Close(); err != nil {
+		t.Errorf("Close = %v", err)
+	}
+	if err := sm.Close(); err != nil {
+		t.Errorf("Close after Close = %v", err)
+	}
+	if _, err := sm.StartJanitor(orderedmap.JanitorOptions{Interval: time.Millisecond}); !errors.Is(err, orderedmap.ErrClosed) {
+		t.Errorf("StartJanitor after Close = %v", err)
+	}
+
+	sm.StoreWithTTL("a", 1, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if sm.Len() != 1 || sm.Reclaimed() != 0 {
+		t.Errorf("Len = %d, Reclaimed = %d", sm.Len(), sm.Reclaimed())
+	}
+	if _, ok := sm.Load("a"); ok {
+		t.Error("Load of an expired key succeeded")
+	}
+}
+
+func TestBindEnv(t *testing.T) {
+	t.Setenv("OMTEST_DB_PORT", "5432")
+	t.Setenv("OMTEST_APP_NAME", "foo")
+	t.Setenv("OMTEST_", "ignored")
+
+	env := orderedmap.New[string, string]()
+	if n := orderedmap.BindEnv(&env, "OMTEST_"); n != 2 {
+		t.Errorf("BindEnv = %d", n)
+	}
+	if env.String() != "Map[app_name:foo db_port:5432]" {
+		t.Errorf("env = %v", env)
+	}
+
+	defaults := orderedmap.New[string, string]()
+	defaults.Store("db_host", "localhost")
+	defaults.Store("db_port", "3306")
+	defaults.Store("debug", "false")
+	flags := orderedmap.New[string, string]()
+	flags.Store("debug", "true")
+
+	cfg := orderedmap.Layer(&defaults, nil, &env, &flags)
+	if cfg.String() != "Map[db_host:localhost db_port:5432 debug:true app_name:foo]" {
+		t.Errorf("Layer = %v", cfg)
+	}
+}
+
+func TestMap_At(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	for i, k := range []string{"e", "d", "c", "b", "a"} {
+		om.Store(k, i)
+	}
+	om.Delete("c")
+
+	values := map[string]int{"e": 0, "d": 1, "b": 3, "a": 4}
+	for i, want := range []string{"e", "d", "b", "a"} {
+		k, v, ok := om.At(i)
+		if !ok || k != want || v != values[k] {
+			t.Errorf("At(%d) = %v, %v, %v", i, k, v, ok)
+		}
+		if ent := om.EntryAt(i); ent.Key() != want {
+			t.Errorf("EntryAt(%d) = %v", i, ent.Key())
+		}
+		if j := om.IndexOf(want); j != i {
+			t.Errorf("IndexOf(%s) = %d", want, j)
+		}
+	}
+	if _, _, ok := om.At(4); ok {
+		t.Error("At(4) is ok")
+	}
+	if om.EntryAt(-1) != nil {
+		t.Error("EntryAt(-1) is not nil")
+	}
+	if i := om.IndexOf("c"); i != -1 {
+		t.Errorf("IndexOf of a deleted key = %d", i)
+	}
+}
+
+func TestMap_KeysSlice(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("b", 1)
+	om.Store("a", 2)
+	om.Store("c", 3)
+	om.Delete("a")
+
+	if keys := om.KeysSlice(); fmt.Sprint(keys) != "[b c]" || cap(keys) != 2 {
+		t.Errorf("KeysSlice = %v (cap %d)", keys, cap(keys))
+	}
+	if values := om.ValuesSlice(); fmt.Sprint(values) != "[1 3]" {
+		t.Errorf("ValuesSlice = %v", values)
+	}
+	if pairs := om.Pairs(); fmt.Sprint(pairs) != "[{b 1} {c 3}]" {
+		t.Errorf("Pairs = %v", pairs)
+	}
+
+	var nilMap *orderedmap.Map[string, int]
+	if nilMap.KeysSlice() != nil || nilMap.ValuesSlice() != nil || nilMap.Pairs() != nil {
+		t.Error("slices of a nil map are not nil")
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	om := orderedmap.New[string, any]()
+	om.Store("port", 8080)
+	om.Store("host", "localhost")
+	om.Store("verbose", false)
+
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.String("a", "", "an unrelated flag")
+	if err := orderedmap.RegisterFlags(&om, fs, "srv."); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-srv.port", "9090", "-srv.verbose"}); err != nil {
+		t.Fatal(err)
+	}
+	if om.String() != "Map[port:9090 host:localhost verbose:true]" {
+		t.Errorf("om = %v", om)
+	}
+
+	var buf strings.Builder
+	fs.SetOutput(&buf)
+	fs.Usage()
+	want := "Usage of app:\n" +
+		"  -srv.port int\n    \t (default 8080)\n" +
+		"  -srv.host string\n    \t (default \"localhost\")\n" +
+		"  -srv.verbose\n    \t\n" +
+		"  -a string\n    \tan unrelated flag\n"
+	if buf.String() != want {
+		t.Errorf("Usage =\n%s", buf.String())
+	}
+
+	bad := orderedmap.New[string, any]()
+	bad.Store("ratio", 0.5)
+	if err := orderedmap.RegisterFlags(&bad, flag.NewFlagSet("", flag.ContinueOnError), ""); err == nil {
+		t.Error("expected an error for a float value")
+	}
+}
+
+func TestInferSchema(t *testing.T) {
+	om := orderedmap.New[string, any](orderedmap.WithNestedOrder())
+	err := om.UnmarshalJSON([]byte(`{"name":"app","port":8080,"ratio":0.5,` +
+		`"tags":["a","b"],"db":{"user":"u","pass":null},` +
+		`"nodes":[{"id":1,"host":"x"},{"id":2.5,"zone":"z"}],"mixed":[1,"a",true]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := orderedmap.InferSchema(&om)
+	b, err := schema.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"$schema":"https://json-schema.org/draft/2020-12/schema",` +
+		`"type":"object","properties":{` +
+		`"name":{"type":"string"},` +
+		`"port":{"type":"integer"},` +
+		`"ratio":{"type":"number"},` +
+		`"tags":{"type":"array","items":{"type":"string"}},` +
+		`"db":{"type":"object","properties":{"user":{"type":"string"},"pass":{"type":"null"}},"required":["user","pass"]},` +
+		`"nodes":{"type":"array","items":{"type":"object","properties":{"id":{"type":"number"},"host":{"type":"string"},"zone":{"type":"string"}},"required":["id"]}},` +
+		`"mixed":{"type":"array","items":{"anyOf":[{"type":"integer"},{"type":"string"},{"type":"boolean"}]}}` +
+		`},"required":["name","port","ratio","tags","db","nodes","mixed"]}`
+	if string(b) != want {
+		t.Errorf("got %s\nwant %s", b, want)
+	}
+
+	for i := 0; i < 10; i++ {
+		b2, _ := orderedmap.InferSchema(&om).MarshalJSON()
+		if string(b2) != string(b) {
+			t.Fatalf("schema is not deterministic: %s", b2)
+		}
+	}
+}
+
+func TestMap_Clone(t *testing.T) {
+	om := orderedmap.NewLRU[string, int](3)
+	om.Store("a", 1)
+	om.StoreWithTTL("b", 2, time.Hour)
+	om.Store("c", 3)
+	om.Ldelete("c")
+	om.Store("c", 30)
+
+	cp := om.Clone()
+	if cp.String() != "Map[a:1 b:2 c:30]" || cp.Len() != 3 {
+		t.Errorf("cp = %v", cp)
+	}
+	if cp.Back().Prev().ExpiresAt() != om.Back().Prev().ExpiresAt() {
+		t.Errorf("expiration time is not copied")
+	}
+
+	cp.Load("a")
+	cp.Store("d", 4)
+	if cp.String() != "Map[c:30 a:1 d:4]" {
+		t.Errorf("cp = %v", cp)
+	}
+	if om.String() != "Map[a:1 b:2 c:30]" {
+		t.Errorf("om = %v", om)
+	}
+
+	var nilMap *orderedmap.Map[string, int]
+	if cp := nilMap.Clone(); cp.Len() != 0 {
+		t.Errorf("clone of a nil map = %v", cp)
+	}
+}
+
+func TestMap_DeepClone(t *testing.T) {
+	om := orderedmap.New[string, []int](orderedmap.WithHistory(2))
+	om.Store("x", []int{1})
+	om.Store("y", []int{2, 3})
+	om.Store("x", []int{4})
+
+	cp := om.DeepClone(func(v []int) []int {
+		return append([]int(nil), v...)
+	})
+	v, _ := cp.Load("y")
+	v[0] = 20
+	cp.History("x")[0].Value[0] = 10
+
+	if om.String() != "Map[x:[4] y:[2 3]]" {
+		t.Errorf("om = %v", om)
+	}
+	if cp.String() != "Map[x:[4] y:[20 3]]" {
+		t.Errorf("cp = %v", cp)
+	}
+	if h := om.History("x"); len(h) != 1 || h[0].Value[0] != 1 {
+		t.Errorf("history = %v", h)
+	}
+}
+
+func TestMap_ValidateSchema(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"required": ["name", "port", "owner"],
+		"additionalProperties": false,
+		"properties": {
+			"port": {"$ref": "#/$defs/port"},
+			"name": {"type": "string", "minLength": 1},
+			"owner": {"type": "string"},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+			"db": {
+				"type": "object",
+				"properties": {
+					"host": {"type": "string"},
+					"mode": {"enum": ["ro", "rw"]}
+				}
+			}
+		},
+		"$defs": {"port": {"type": "integer", "minimum": 1, "maximum": 65535}}
+	}`)
+
+	om := orderedmap.New[string, any](orderedmap.WithNestedOrder())
+	err := om.UnmarshalJSON([]byte(`{"name":"","db":{"mode":"x","host":1},` +
+		`"port":70000.5,"tags":["a",2,"a"],"extra":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := om.ValidateSchema(schema)
+	if errs == nil {
+		t.Fatal("ValidateSchema returned nil")
+	}
+	var ptrs []string
+	for ent := errs.Front(); ent != nil; ent = ent.Next() {
+		ptrs = append(ptrs, ent.Key())
+	}
+	want := []string{"", "/name", "/db/mode", "/db/host", "/port", "/tags", "/tags/1", "/extra"}
+	if fmt.Sprint(ptrs) != fmt.Sprint(want) {
+		t.Errorf("pointers = %q", ptrs)
+	}
+	if e, _ := errs.Load(""); e.Error() != `required: the property "owner" is missing` {
+		t.Errorf("error of root = %v", e)
+	}
+	if e, _ := errs.Load("/port"); e.Error() !=
+		"type: expected integer, but got number\nmaximum: 70000.5 is greater than 65535" {
+		t.Errorf("error of /port = %v", e)
+	}
+	var se orderedmap.SchemaError
+	if !errors.As(errs, &se) || se.Keyword != "required" {
+		t.Errorf("errors.As = %v", se)
+	}
+	if !strings.HasPrefix(errs.Error(), `orderedmap: "": required: the property "owner" is missing; "/name": minLength:`) {
+		t.Errorf("Error = %v", errs.Error())
+	}
+
+	om2 := orderedmap.New[string, any]()
+	om2.Store("name", "app")
+	om2.Store("port", 8080)
+	om2.Store("owner", "me")
+	if errs := om2.ValidateSchema(schema); errs != nil {
+		t.Errorf("ValidateSchema = %v", errs)
+	}
+
+	if errs := om2.ValidateSchema([]byte(`{`)); errs == nil || errs.Len() != 1 {
+		t.Errorf("ValidateSchema with an invalid schema = %v", errs)
+	}
+}
+
+func TestMap_LoadOr(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("a", 1)
+	om.Store("b", 0)
+
+	if v := om.LoadOr("a", 9); v != 1 {
+		t.Errorf("LoadOr(a) = %d", v)
+	}
+	if v := om.LoadOr("b", 9); v != 0 {
+		t.Errorf("LoadOr(b) = %d", v)
+	}
+	if v := om.LoadOr("c", 9); v != 9 {
+		t.Errorf("LoadOr(c) = %d", v)
+	}
+	var nilMap *orderedmap.Map[string, int]
+	if v := nilMap.LoadOr("a", 9); v != 9 {
+		t.Errorf("LoadOr of a nil map = %d", v)
+	}
+}
+
+func TestMap_MustLoad(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("a", 1)
+
+	if v := om.MustLoad("a"); v != 1 {
+		t.Errorf("MustLoad(a) = %d", v)
+	}
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || !errors.Is(err, orderedmap.ErrKeyNotFound) {
+			t.Fatalf("recovered = %v", r)
+		}
+		if err.Error() != "orderedmap: key not found: missing" {
+			t.Errorf("message = %s", err.Error())
+		}
+	}()
+	om.MustLoad("missing")
+	t.Errorf("MustLoad did not panic")
+}
+
+func TestMap_Merge(t *testing.T) {
+	newMap := func(kvs ...any) *orderedmap.Map[string, int] {
+		om := orderedmap.New[string, int]()
+		for i := 0; i < len(kvs); i += 2 {
+			om.Store(kvs[i].(string), kvs[i+1].(int))
+		}
+		return &om
+	}
+	sum := func(key string, left, right int) int { return left + right }
+
+	left := newMap("b", 1, "a", 2, "c", 3)
+	right := newMap("d", 10, "a", 20, "e", 30, "b", 40)
+
+	if u := left.Union(right, nil); u.String() != "Map[b:40 a:20 c:3 d:10 e:30]" {
+		t.Errorf("Union = %v", u)
+	}
+	if u := left.Union(right, sum); u.String() != "Map[b:41 a:22 c:3 d:10 e:30]" {
+		t.Errorf("Union with resolve = %v", u)
+	}
+	if in := left.Intersect(right, nil); in.String() != "Map[b:40 a:20]" {
+		t.Errorf("Intersect = %v", in)
+	}
+	if in := left.Intersect(right, sum); in.String() != "Map[b:41 a:22]" {
+		t.Errorf("Intersect with resolve = %v", in)
+	}
+	if diff := left.Difference(right); diff.String() != "Map[c:3]" {
+		t.Errorf("Difference = %v", diff)
+	}
+	if diff := right.Difference(left); diff.String() != "Map[d:10 e:30]" {
+		t.Errorf("Difference = %v", diff)
+	}
+	if left.String() != "Map[b:1 a:2 c:3]" || right.Len() != 4 {
+		t.Errorf("operands are changed: %v %v", left, right)
+	}
+
+	var keys []string
+	left.Merge(right, func(key string, l, r int) int {
+		keys = append(keys, key)
+		return l
+	})
+	if left.String() != "Map[b:1 a:2 c:3 d:10 e:30]" || fmt.Sprint(keys) != "[a b]" {
+		t.Errorf("Merge = %v, resolved %v", left, keys)
+	}
+
+	var nilMap *orderedmap.Map[string, int]
+	if u := nilMap.Union(right, nil); u.Len() != 4 {
+		t.Errorf("Union of a nil map = %v", u)
+	}
+	left.Merge(nil, nil)
+	if left.Len() != 5 {
+		t.Errorf("Merge with a nil map = %v", left)
+	}
+
+	loaded := 0
+	lru := orderedmap.NewLRU[string, int](0, orderedmap.WithLoader(func(k string) (int, error) {
+		loaded++
+		return 0, nil
+	}))
+	lru.Store("x", 1)
+	lru.Store("y", 2)
+	other := newMap("x", 1)
+	if in := other.Intersect(&lru, nil); in.String() != "Map[x:1]" {
+		t.Errorf("Intersect with a LRU map = %v", in)
+	}
+	if diff := newMap("x", 1, "w", 0).Difference(&lru); diff.String() != "Map[w:0]" {
+		t.Errorf("Difference from a LRU map = %v", diff)
+	}
+	if lru.String() != "Map[x:1 y:2]" || loaded != 0 {
+		t.Errorf("Intersect or Difference loads or moves entries: %v, loaded %d", lru, loaded)
+	}
+	lru.Merge(newMap("z", 3, "x", 10), sum)
+	if lru.String() != "Map[y:2 z:3 x:11]" || loaded != 0 {
+		t.Errorf("Merge = %v, loaded %d", lru, loaded)
+	}
+}
+
+func TestMap_Filter(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("c", 3)
+	om.Store("a", 1)
+	om.Store("d", 4)
+	om.Store("b", 2)
+
+	even := om.Filter(func(k string, v int) bool { return v%2 == 0 })
+	if even.String() != "Map[d:4 b:2]" {
+		t.Errorf("Filter = %v", even)
+	}
+	even.Store("e", 6)
+	if om.Len() != 4 {
+		t.Errorf("om = %v", om)
+	}
+
+	labels := orderedmap.MapValues(&om, func(k string, v int) string {
+		return k + strings.Repeat("*", v)
+	})
+	if labels.String() != "Map[c:c*** a:a* d:d**** b:b**]" {
+		t.Errorf("MapValues = %v", labels)
+	}
+
+	s := orderedmap.Reduce(&om, "", func(acc string, k string, v int) string {
+		return acc + k
+	})
+	if s != "cadb" {
+		t.Errorf("Reduce = %s", s)
+	}
+	sum := orderedmap.Reduce(even, 0, func(acc int, _ string, v int) int { return acc + v })
+	if sum != 12 {
+		t.Errorf("Reduce = %d", sum)
+	}
+
+	var nilMap *orderedmap.Map[string, int]
+	if m := nilMap.Filter(func(string, int) bool { return true }); m.Len() != 0 {
+		t.Errorf("Filter of a nil map = %v", m)
+	}
+	if m := orderedmap.MapValues(nilMap, func(string, int) int { return 0 }); m.Len() != 0 {
+		t.Errorf("MapValues of a nil map = %v", m)
+	}
+	if n := orderedmap.Reduce(nilMap, 7, func(acc int, _ string, _ int) int { return 0 }); n != 7 {
+		t.Errorf("Reduce of a nil map = %d", n)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a := orderedmap.New[string, int]()
+	a.Store("x", 1)
+	a.Store("y", 2)
+	b := orderedmap.New[string, int]()
+	b.Store("x", 1)
+	b.Store("y", 2)
+
+	if !orderedmap.Equal(&a, &b) {
+		t.Errorf("Equal(%v, %v) = false", a, b)
+	}
+	b.Store("y", 3)
+	if orderedmap.Equal(&a, &b) {
+		t.Errorf("Equal(%v, %v) = true", a, b)
+	}
+	b.Delete("x")
+	b.Store("x", 1)
+	b.Store("y", 2)
+	if orderedmap.Equal(&a, &b) {
+		t.Errorf("Equal(%v, %v) = true", a, b)
+	}
+	b.Delete("x")
+	if orderedmap.Equal(&a, &b) {
+		t.Errorf("Equal(%v, %v) = true", a, b)
+	}
+
+	var nilMap *orderedmap.Map[string, int]
+	empty := orderedmap.New[string, int]()
+	if !orderedmap.Equal(nilMap, &empty) || orderedmap.Equal(nilMap, &a) {
+		t.Errorf("Equal with a nil map is wrong")
+	}
+}
+
+func TestEqualFunc(t *testing.T) {
+	a := orderedmap.New[string, []int]()
+	a.Store("x", []int{1, 2})
+	a.Store("y", nil)
+	b := orderedmap.New[string, string]()
+	b.Store("x", "[1 2]")
+	b.Store("y", "[]")
+
+	eq := func(v []int, s string) bool { return fmt.Sprint(v) == s }
+	if !orderedmap.EqualFunc(&a, &b, eq) {
+		t.Errorf("EqualFunc(%v, %v) = false", a, b)
+	}
+	b.Store("y", "[0]")
+	if orderedmap.EqualFunc(&a, &b, eq) {
+		t.Errorf("EqualFunc(%v, %v) = true", a, b)
+	}
+}
+
+func TestMap_DeleteFunc(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	for i, k := range []string{"a", "b", "c", "d", "e", "f"} {
+		om.Store(k, i)
+	}
+
+	n := om.DeleteFunc(func(k string, v int) bool { return v%2 == 0 })
+	if n != 3 || om.String() != "Map[b:1 d:3 f:5]" || om.Len() != 3 {
+		t.Errorf("DeleteFunc = %d, %v", n, om)
+	}
+	if n := om.DeleteFunc(func(string, int) bool { return false }); n != 0 {
+		t.Errorf("DeleteFunc = %d", n)
+	}
+	if n := om.DeleteFunc(func(string, int) bool { return true }); n != 3 || om.Len() != 0 {
+		t.Errorf("DeleteFunc = %d, %v", n, om)
+	}
+	if om.Front() != nil || om.Back() != nil {
+		t.Errorf("om = %v", om)
+	}
+
+	var nilMap *orderedmap.Map[string, int]
+	if n := nilMap.DeleteFunc(func(string, int) bool { return true }); n != 0 {
+		t.Errorf("DeleteFunc of a nil map = %d", n)
+	}
+}
+
+func TestMap_DeleteAll(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("a", 1)
+	om.Store("b", 2)
+	om.Store("c", 3)
+
+	om.DeleteAll("c", "x", "a")
+	if om.String() != "Map[b:2]" {
+		t.Errorf("om = %v", om)
+	}
+	om.DeleteAll()
+	if om.Len() != 1 {
+		t.Errorf("om = %v", om)
+	}
+}
+
+func TestMap_DeepMerge(t *testing.T) {
+	decode := func(s string) *orderedmap.Map[string, any] {
+		om := orderedmap.New[string, any](orderedmap.WithNestedOrder())
+		if err := om.UnmarshalJSON([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+		return &om
+	}
+	base := `{"kind":"Deployment","spec":{"replicas":1,"template":{"containers":[` +
+		`{"name":"app","image":"app:1","ports":[80]},{"name":"log","image":"log:1"}]}},"tags":["a"]}`
+	overlay := `{"spec":{"template":{"containers":[` +
+		`{"name":"log","image":"log:2"},{"name":"side","image":"side:1"}]},"replicas":3},` +
+		`"tags":["b"],"meta":{"owner":"x"}}`
+
+	cases := []struct {
+		strategy orderedmap.ArrayStrategy
+		want     string
+	}{
+		{orderedmap.ArrayReplace, `{"kind":"Deployment","spec":{"replicas":3,"template":{"containers":[` +
+			`{"name":"log","image":"log:2"},{"name":"side","image":"side:1"}]}},"tags":["b"],"meta":{"owner":"x"}}`},
+		{orderedmap.ArrayConcat, `{"kind":"Deployment","spec":{"replicas":3,"template":{"containers":[` +
+			`{"name":"app","image":"app:1","ports":[80]},{"name":"log","image":"log:1"},` +
+			`{"name":"log","image":"log:2"},{"name":"side","image":"side:1"}]}},"tags":["a","b"],"meta":{"owner":"x"}}`},
+		{orderedmap.ArrayMergeByIndex, `{"kind":"Deployment","spec":{"replicas":3,"template":{"containers":[` +
+			`{"name":"log","image":"log:2","ports":[80]},{"name":"side","image":"side:1"}]}},"tags":["b"],"meta":{"owner":"x"}}`},
+		{orderedmap.ArrayMergeByKey("name"), `{"kind":"Deployment","spec":{"replicas":3,"template":{"containers":[` +
+			`{"name":"app","image":"app:1","ports":[80]},{"name":"log","image":"log:2"},` +
+			`{"name":"side","image":"side:1"}]}},"tags":["a","b"],"meta":{"owner":"x"}}`},
+	}
+	for i, c := range cases {
+		om := decode(base)
+		other := decode(overlay)
+		om.DeepMerge(other, c.strategy)
+		b, err := om.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != c.want {
+			t.Errorf("case %d: got  %s\nwant %s", i, b, c.want)
+		}
+
+		meta, _ := om.Load("meta")
+		meta.(*orderedmap.Map[string, any]).Store("owner", "y")
+		if b, _ := other.MarshalJSON(); string(b) != strings.ReplaceAll(overlay, " ", "") {
+			t.Errorf("case %d: other is changed: %s", i, b)
+		}
+	}
+
+	loaded := 0
+	lru := orderedmap.NewLRU[string, any](0, orderedmap.WithLoader(func(k string) (any, error) {
+		loaded++
+		return nil, nil
+	}))
+	lru.Store("kind", "Service")
+	lru.Store("tags", []any{"c"})
+	lru.DeepMerge(decode(overlay), orderedmap.ArrayConcat)
+	if b, _ := lru.MarshalJSON(); loaded != 0 || string(b) != `{"kind":"Service",`+
+		`"spec":{"template":{"containers":[{"name":"log","image":"log:2"},{"name":"side","image":"side:1"}]},`+
+		`"replicas":3},"tags":["c","b"],"meta":{"owner":"x"}}` {
+		t.Errorf("DeepMerge into a LRU map = %s, loaded %d", b, loaded)
+	}
+}
+
+func TestMap_StartRecording(t *testing.T) {
+	om := orderedmap.New[string, any](orderedmap.WithMaxLen(3))
+	om.Store("a", 1)
+	om.StartRecording()
+
+	om.Store("b/c", "x")
+	om.Store("a", map[string]int{"n": 2})
+	om.Store("d", nil)
+	om.Store("e", true)
+	om.Delete("d")
+	om.SortFunc(func(x, y *orderedmap.Entry[string, any]) bool { return x.Key() > y.Key() })
+	om.Clear()
+
+	b, err := om.StopRecording()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"op":"add","path":"/b~1c","value":"x"},` +
+		`{"op":"replace","path":"/a","value":{"n":2}},` +
+		`{"op":"add","path":"/d","value":null},` +
+		`{"op":"add","path":"/e","value":true},` +
+		`{"op":"remove","path":"/a"},` +
+		`{"op":"remove","path":"/d"},` +
+		`{"op":"move","from":"/e","path":"/e","x-after":""},` +
+		`{"op":"move","from":"/b~1c","path":"/b~1c","x-after":"/e"},` +
+		`{"op":"remove","path":"/e"},` +
+		`{"op":"remove","path":"/b~1c"}]`
+	if string(b) != want {
+		t.Errorf("patch = %s", b)
+	}
+
+	om.Store("f", 1)
+	if b, err := om.StopRecording(); err != nil || string(b) != "[]" {
+		t.Errorf("StopRecording without recording = %s, %v", b, err)
+	}
+
+	lru := orderedmap.NewLRU[int, string](3)
+	lru.Store(1, "a")
+	lru.Store(2, "b")
+	lru.StartRecording()
+	lru.Load(1)
+	lru.Load(1)
+	b, err = lru.StopRecording()
+	if err != nil || string(b) != `[{"op":"move","from":"/1","path":"/1","x-after":"/2"}]` {
+		t.Errorf("patch = %s, %v", b, err)
+	}
+
+	om2 := orderedmap.New[string, int]()
+	om2.StartRecording()
+	om2.Store(`"h/i"`, 1)
+	if b, err := om2.StopRecording(); err != nil || string(b) != `[{"op":"add","path":"/\"h~1i\"","value":1}]` {
+		t.Errorf("patch = %s, %v", b, err)
+	}
+
+	om.StartRecording()
+	om.Store("g", func() {})
+	if _, err := om.StopRecording(); err == nil {
+		t.Errorf("StopRecording with an unsupported value succeeded")
+	}
+}
+
+func TestMap_Pop(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	om.Store("a", 1)
+	om.Store("b", 2)
+	om.Store("c", 3)
+	om.Store("d", 4)
+
+	if k, v, ok := om.PopFront(); k != "a" || v != 1 || !ok {
+		t.Errorf("PopFront = (%s, %d, %t)", k, v, ok)
+	}
+	if k, v, ok := om.PopBack(); k != "d" || v != 4 || !ok {
+		t.Errorf("PopBack = (%s, %d, %t)", k, v, ok)
+	}
+	if k, v, ok := om.Pop("c"); k != "c" || v != 3 || !ok {
+		t.Errorf("Pop(c) = (%s, %d, %t)", k, v, ok)
+	}
+	if k, v, ok := om.Pop("x"); k != "" || v != 0 || ok {
+		t.Errorf("Pop(x) = (%s, %d, %t)", k, v, ok)
+	}
+	if om.String() != "Map[b:2]" {
+		t.Errorf("om = %v", om)
+	}
+	om.PopFront()
+	if _, _, ok := om.PopFront(); ok {
+		t.Errorf("PopFront of an empty map succeeded")
+	}
+	if _, _, ok := om.PopBack(); ok {
+		t.Errorf("PopBack of an empty map succeeded")
+	}
+
+	om.StoreWithTTL("x", 1, time.Nanosecond)
+	om.Store("y", 2)
+	om.StoreWithTTL("z", 3, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if k, _, ok := om.PopFront(); k != "y" || !ok {
+		t.Errorf("PopFront with expired entries = (%s, %t)", k, ok)
+	}
+	om.StoreWithTTL("w", 4, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, _, ok := om.Pop("w"); ok || om.Len() != 1 {
+		t.Errorf("Pop of an expired entry succeeded: %v", om)
+	}
+	if _, _, ok := om.PopBack(); ok || om.Len() != 0 {
+		t.Errorf("PopBack of an expired entry succeeded: %v", om)
+	}
+}
+
+func TestSyncMap_PopFront(t *testing.T) {
+	sm := orderedmap.NewSync[int, int]()
+	for i := 0; i < 1000; i++ {
+		sm.Store(i, i)
+	}
+
+	var mu sync.Mutex
+	seen := make(map[int]bool)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				k, _, ok := sm.PopFront()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				if seen[k] {
+					t.Errorf("key %d is popped twice", k)
+				}
+				seen[k] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 1000 || sm.Len() != 0 {
+		t.Errorf("popped %d keys, %d left", len(seen), sm.Len())
+	}
+
+	sm.Store(1, 10)
+	sm.Store(2, 20)
+	if k, v, ok := sm.PopBack(); k != 2 || v != 20 || !ok {
+		t.Errorf("PopBack = (%d, %d, %t)", k, v, ok)
+	}
+	if k, v, ok := sm.Pop(1); k != 1 || v != 10 || !ok {
+		t.Errorf("Pop = (%d, %d, %t)", k, v, ok)
+	}
+}
+
+func TestMap_MoveToFront(t *testing.T) {
+	om := orderedmap.New[string, int](orderedmap.WithMaxLen(5))
+	for i, k := range []string{"a", "b", "c", "d"} {
+		om.Store(k, i)
+	}
+
+	steps := []struct {
+		move func() bool
+		want string
+	}{
+		{func() bool { return om.MoveToFront("c") }, "Map[c:2 a:0 b:1 d:3]"},
+		{func() bool { return om.MoveToFront("c") }, "Map[c:2 a:0 b:1 d:3]"},
+		{func() bool { return om.MoveToBack("a") }, "Map[c:2 b:1 d:3 a:0]"},
+		{func() bool { return om.MoveBefore("a", "b") }, "Map[c:2 a:0 b:1 d:3]"},
+		{func() bool { return om.MoveBefore("c", "d") }, "Map[a:0 b:1 c:2 d:3]"},
+		{func() bool { return om.MoveBefore("c", "d") }, "Map[a:0 b:1 c:2 d:3]"},
+		{func() bool { return om.MoveAfter("a", "d") }, "Map[b:1 c:2 d:3 a:0]"},
+		{func() bool { return om.MoveAfter("d", "b") }, "Map[b:1 d:3 c:2 a:0]"},
+		{func() bool { return om.MoveAfter("b", "b") }, "Map[b:1 d:3 c:2 a:0]"},
+	}
+	for i, step := range steps {
+		if !step.move() || om.String() != step.want {
+			t.Errorf("step %d: om = %v, want %s", i, om, step.want)
+		}
+		var fwd, bwd []string
+		for ent := om.Front(); ent != nil; ent = ent.Next() {
+			fwd = append(fwd, ent.Key())
+		}
+		for ent := om.Back(); ent != nil; ent = ent.Prev() {
+			bwd = append([]string{ent.Key()}, bwd...)
+		}
+		if fmt.Sprint(fwd) != fmt.Sprint(bwd) || len(fwd) != om.Len() {
+			t.Errorf("step %d: keys = %v, backward keys = %v", i, fwd, bwd)
+		}
+	}
+
+	if om.MoveToFront("x") || om.MoveToBack("x") || om.MoveBefore("x", "a") || om.MoveAfter("a", "x") {
+		t.Errorf("moving an absent key succeeded")
+	}
+	om.Ldelete("c")
+	if om.MoveToFront("c") {
+		t.Errorf("moving a deleted key succeeded")
+	}
+
+	om.Store("e", 4)
+	om.Store("f", 5)
+	om.Store("g", 6)
+	if om.String() != "Map[d:3 a:0 e:4 f:5 g:6]" {
+		t.Errorf("om = %v", om)
+	}
+
+	var nilMap *orderedmap.Map[string, int]
+	if nilMap.MoveToFront("a") {
+		t.Errorf("moving in a nil map succeeded")
+	}
+}
+
+func TestFormatDiff(t *testing.T) {
+	a := orderedmap.New[string, int]()
+	for i, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		a.Store(k, i)
+	}
+	b := a.Clone()
+
+	if d := orderedmap.FormatDiff(&a, &b); d != "" {
+		t.Errorf("FormatDiff of equal maps = %q", d)
+	}
+
+	b.Delete("b")
+	b.Store("c", 20)
+	b.MoveToBack("a")
+	b.Store("k", 10)
+	want := "-a: 0 (moved)\n" +
+		"-b: 1\n" +
+		"-c: 2\n" +
+		"+c: 20\n" +
+		" d: 3\n" +
+		" e: 4\n" +
+		" f: 5\n" +
+		"...\n" +
+		" h: 7\n" +
+		" i: 8\n" +
+		" j: 9\n" +
+		"+a: 0 (moved)\n" +
+		"+k: 10\n"
+	if d := orderedmap.FormatDiff(&a, &b); d != want {
+		t.Errorf("FormatDiff = \n%s", d)
+	}
+
+	c := orderedmap.New[string, int]()
+	c.Store("x", 1)
+	want = "\x1b[31m-x: 1\x1b[0m\n\x1b[32m+x: 2\x1b[0m\n"
+	d := c.Clone()
+	d.Store("x", 2)
+	if s := orderedmap.FormatDiffColor(&c, &d); s != want {
+		t.Errorf("FormatDiffColor = %q", s)
+	}
+
+	var nilMap *orderedmap.Map[string, int]
+	if s := orderedmap.FormatDiff(nilMap, &c); s != "+x: 1\n" {
+		t.Errorf("FormatDiff with a nil map = %q", s)
+	}
+}
+
+func TestWithOrder(t *testing.T) {
+	om := orderedmap.New[string, int](orderedmap.WithOrder(orderedmap.OrderFunc(strings.Compare)))
+	for i, k := range []string{"m", "c", "x", "a", "p", "c"} {
+		om.Store(k, i)
+	}
+	if om.String() != "Map[a:3 c:5 m:0 p:4 x:2]" {
+		t.Errorf("om = %v", om)
+	}
+
+	om.Delete("a")
+	om.LoadOrStore("b", 6)
+	om.Swap("z", 7)
+	om.Ldelete("m")
+	om.Store("m", 8)
+	if om.String() != "Map[b:6 c:5 m:8 p:4 x:2 z:7]" {
+		t.Errorf("om = %v", om)
+	}
+	if b, _ := om.MarshalJSON(); string(b) != `{"b":6,"c":5,"m":8,"p":4,"x":2,"z":7}` {
+		t.Errorf("MarshalJSON = %s", b)
+	}
+
+	bounded := orderedmap.New[int, string](
+		orderedmap.WithMaxLen(3),
+		orderedmap.WithOrder(orderedmap.OrderFunc(func(a, b int) int { return b - a })))
+	if err := bounded.UnmarshalJSON([]byte(`{"1":"a","5":"b","3":"c","4":"d"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if bounded.String() != "Map[4:d 3:c 1:a]" {
+		t.Errorf("bounded = %v", bounded)
+	}
+
+	byLen := orderedmap.New[string, int](orderedmap.WithOrder(orderedmap.OrderFunc(
+		func(a, b string) int { return len(a) - len(b) })))
+	for i, k := range []string{"ccc", "a", "bb", "b", "aa"} {
+		byLen.Store(k, i)
+	}
+	if byLen.String() != "Map[a:1 b:3 bb:2 aa:4 ccc:0]" {
+		t.Errorf("byLen = %v", byLen)
+	}
+
+	access := orderedmap.New[string, int](orderedmap.WithOrder(orderedmap.OrderAccess))
+	access.Store("a", 1)
+	access.Store("b", 2)
+	access.Load("a")
+	if access.String() != "Map[b:2 a:1]" {
+		t.Errorf("access = %v", access)
+	}
+
+	last := orderedmap.New[string, int](
+		orderedmap.WithOrder(orderedmap.OrderAccess),
+		orderedmap.WithOrder(orderedmap.OrderInsertion))
+	last.Store("a", 1)
+	last.Store("b", 2)
+	last.Load("a")
+	if last.String() != "Map[a:1 b:2]" {
+		t.Errorf("last = %v", last)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("New with a mismatched order function did not panic")
+		}
+	}()
+	orderedmap.New[int, int](orderedmap.WithOrder(orderedmap.OrderFunc(strings.Compare)))
+}
+
+func TestInterface(t *testing.T) {
+	om := orderedmap.New[string, int]()
+	for _, m := range []orderedmap.Interface[string, int]{&om, orderedmap.NewSync[string, int]()} {
+		m.Store("b", 1)
+		m.Store("a", 2)
+		m.Store("c", 3)
+		m.Delete("a")
+		if v, ok := m.Load("c"); !ok || v != 3 {
+			t.Errorf("%T: Load = %v, %v", m, v, ok)
+		}
+		if m.Len() != 2 || joinKeys(m) != "b,c" {
+			t.Errorf("%T: keys = %s", m, joinKeys(m))
+		}
+	}
+}
+
+func TestMap_WithOnEvict(t *testing.T) {
+	evicted := []string{}
+	om := orderedmap.New[string, int](
+		orderedmap.WithMaxLen(2),
+		orderedmap.WithOnEvict(func(k string, v int, r orderedmap.EvictReason) {
+			evicted = append(evicted, fmt.Sprintf("%s:%d:%s", k, v, r))
+		}),
+	)
+	om.Store("a", 1)
+	om.Store("b", 2)
+	om.Store("a", 10)
+	om.Store("c", 3)
+	om.Ldelete("b")
+	om.Store("d", 4)
+	om.Store("b", 5)
+	if om.String() != "Map[d:4 b:5]" {
+		t.Errorf("String = %s", om.String())
+	}
+	om.Clear()
+	if fmt.Sprint(evicted) != "[a:10:MaxLen c:3:MaxLen d:4:Clear b:5:Clear]" {
+		t.Errorf("evicted = %v", evicted)
+	}
+	if _, ok := om.Load("a"); ok {
+		t.Errorf("an evicted entry is loaded")
+	}
+}
+
+func TestMap_WithMaxWeight(t *testing.T) {
+	evicted := []string{}
+	om := orderedmap.New[string, string](
+		orderedmap.WithMaxWeight(10, func(k, v string) int64 {
+			return int64(len(v))
+		}),
+		orderedmap.WithOnEvict(func(k, v string, r orderedmap.EvictReason) {
+			evicted = append(evicted, k+":"+r.String())
+		}),
+	)
+	om.Store("a", "xxxx")
+	om.Store("b", "xxxx")
+	om.Store("c", "xx")
+	om.Store("d", "x")
+	if om.String() != "Map[b:xxxx c:xx d:x]" {
+		t.Errorf("String = %s", om.String())
+	}
+	om.Store("d", "xxxxxx")
+	if om.String() != "Map[c:xx d:xxxxxx]" {
+		t.Errorf("String = %s", om.String())
+	}
+	om.Delete("d")
+	om.Store("e", "xxxxxxxxx")
+	if om.String() != "Map[e:xxxxxxxxx]" {
+		t.Errorf("String = %s", om.String())
+	}
+	om.Store("f", "xxxxxxxxxxx")
+	if om.Len() != 0 {
+		t.Errorf("String = %s", om.String())
+	}
+	if fmt.Sprint(evicted) != "[a:MaxWeight b:MaxWeight c:MaxWeight e:MaxWeight f:MaxWeight]" {
+		t.Errorf("evicted = %v", evicted)
+	}
+}
+
+func TestMap_WithTimestamps(t *testing.T) {
+	om := orderedmap.New[string, int](orderedmap.WithTimestamps())
+	t0 := time.Now()
+	om.Store("a", 1)
+	om.Store("b", 2)
+	time.Sleep(time.Millisecond)
+	t1 := time.Now()
+	om.Store("c", 3)
+	om.Store("a", 10)
+
+	a := om.Front()
+	if a.InsertedAt().Before(t0) || !a.InsertedAt().Before(t1) {
+		t.Errorf("InsertedAt = %v", a.InsertedAt())
+	}
+	if a.UpdatedAt().Before(t1) {
+		t.Errorf("UpdatedAt = %v", a.UpdatedAt())
+	}
+	if b := a.Next(); !b.UpdatedAt().Equal(b.InsertedAt()) {
+		t.Errorf("UpdatedAt = %v, InsertedAt = %v", b.UpdatedAt(), b.InsertedAt())
+	}
+
+	om.MoveToFront("c")
+	if n := om.DeleteInsertedBefore(t1); n != 2 {
+		t.Errorf("DeleteInsertedBefore = %d", n)
+	}
+	if om.String() != "Map[c:3]" {
+		t.Errorf("String = %s", om.String())
+	}
+
+	om2 := orderedmap.New[string, int]()
+	om2.Store("a", 1)
+	if !om2.Front().InsertedAt().IsZero() || om2.DeleteInsertedBefore(time.Now()) != 0 {
+		t.Errorf("timestamps are recorded without WithTimestamps")
+	}
+}
+
+func TestMap_WithTrace(t *testing.T) {
+	logged := []string{}
+	om := orderedmap.New[string, int](
+		orderedmap.WithMaxLen(3),
+		orderedmap.WithTrace(4, func(r orderedmap.TraceRecord[string]) {
+			logged = append(logged, fmt.Sprintf("%d:%s:%s", r.Seq, r.Op, r.Key))
+		}),
+	)
+	om.Store("a", 1)
+	om.Store("b", 2)
+	om.Store("a", 10)
+	om.Ldelete("b")
+	om.Store("c", 3)
+	om.Store("d", 4)
+	om.Store("e", 5)
+	om.Delete("c")
+	om.MoveToBack("d")
+	om.SortFunc(func(a, b *orderedmap.Entry[string, int]) bool { return a.Key() < b.Key() })
+	om.Clear()
+
+	if fmt.Sprint(logged) != "[1:Insert:a 2:Insert:b 3:Update:a 4:Ldelete:b "+
+		"5:Insert:c 6:Insert:d 7:Insert:e 8:Evict:a 9:Delete:c 10:Move:d "+
+		"11:Move:d 12:Move:e 13:Clear:d 14:Clear:e]" {
+		t.Errorf("logged = %v", logged)
+	}
+
+	hist := om.DebugHistory()
+	seqs := []uint64{}
+	for _, r := range hist {
+		seqs = append(seqs, r.Seq)
+	}
+	if fmt.Sprint(seqs) != "[11 12 13 14]" {
+		t.Errorf("DebugHistory = %v", hist)
+	}
+	if !strings.Contains(hist[1].Caller, ".TestMap_WithTrace (orderedmap_test.go:") {
+		t.Errorf("Caller = %s", hist[1].Caller)
+	}
+
+	om2 := orderedmap.New[string, int]()
+	if om2.DebugHistory() != nil {
+		t.Errorf("DebugHistory without WithTrace = %v", om2.DebugHistory())
+	}
+}
+
+func TestMap_WithHistory(t *testing.T) {
+	om := orderedmap.New[string, int](orderedmap.WithHistory(2))
+	t0 := time.Now()
+	om.Store("a", 1)
+	om.Store("a", 2)
+	om.Swap("a", 3)
+	orderedmap.Add(&om, "a", 4)
+	om.Store("b", 1)
+
+	hist := om.History("a")
+	if len(hist) != 2 || hist[0].Value != 2 || hist[1].Value != 3 {
+		t.Errorf("History = %v", hist)
+	}
+	if hist[0].ReplacedAt.Before(t0) || hist[1].ReplacedAt.Before(hist[0].ReplacedAt) {
+		t.Errorf("History = %v", hist)
+	}
+	if v, _ := om.Load("a"); v != 7 {
+		t.Errorf("Load = %d", v)
+	}
+	if om.History("b") != nil {
+		t.Errorf("History(b) = %v", om.History("b"))
+	}
+
+	om.Delete("a")
+	om.Store("a", 1)
+	if om.History("a") != nil {
+		t.Errorf("History after Delete = %v", om.History("a"))
+	}
+
+	om.Store("b", 2)
+	om.Clear()
+	if om.History("b") != nil {
+		t.Errorf("History after Clear = %v", om.History("b"))
+	}
+
+	om2 := orderedmap.New[string, int]()
+	om2.Store("a", 1)
+	om2.Store("a", 2)
+	if om2.History("a") != nil {
+		t.Errorf("History without WithHistory = %v", om2.History("a"))
+	}
+}
+
+func TestSpillMap(t *testing.T) {
+	sm, err := orderedmap.NewSpill[string, []byte](orderedmap.SpillOptions{
+		Threshold: 4,
+		Dir:       t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sm.Close()
+
+	sm.Store("a", []byte("abc"))
+	sm.Store("b", []byte("abcdefgh"))
+	sm.Store("c", []byte("xyz"))
+	sm.Store("c", []byte("xyzxyzxyz"))
+	if sm.Spilled() != 2 {
+		t.Errorf("Spilled = %d", sm.Spilled())
+	}
+
+	v, ok, err := sm.Load("b")
+	if string(v) != "abcdefgh" || !ok || err != nil {
+		t.Errorf("Load = (%s, %t, %v)", v, ok, err)
+	}
+
+	sm.Store("b", []byte("ab"))
+	sm.Delete("c")
+	if sm.Spilled() != 0 {
+		t.Errorf("Spilled = %d", sm.Spilled())
+	}
+
+	keys := ""
+	sm.Range(func(k string, v []byte) bool {
+		keys += k + ":" + string(v) + " "
+		return true
+	})
+	if keys != "a:abc b:ab " {
+		t.Errorf("Range = %s", keys)
+	}
+
+	sm2, _ := orderedmap.NewSpill[int, Foo](orderedmap.SpillOptions{Dir: t.TempDir()})
+	sm2.Store(1, Foo{Bar: "bar", Baz: 1})
+	if f, _, err := sm2.Load(1); f.Bar != "bar" || f.Baz != 1 || err != nil {
+		t.Errorf("Load = (%v, %v)", f, err)
+	}
+	if err := sm2.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm2.Store(2, Foo{}); !errors.Is(err, orderedmap.ErrClosed) {
+		t.Errorf("Store after Close = %v", err)
+	}
+}
+
+func TestLoadConcurrent(t *testing.T) {
+	keys := []int{5, 3, 8, 1, 3, 9, 2}
+	var running, maxRunning int32
+	fetch := func(ctx context.Context, k int) (string, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(10-k) * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return strconv.Itoa(k * 10), nil
+	}
+
+	om, err := orderedmap.LoadConcurrent(context.Background(), keys, fetch, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if om.String() != "Map[5:50 3:30 8:80 1:10 9:90 2:20]" {
+		t.Errorf("String = %s", om.String())
+	}
+	if m := atomic.LoadInt32(&maxRunning); m > 3 {
+		t.Errorf("max running fetches = %d", m)
+	}
+
+	_, err = orderedmap.LoadConcurrent(context.Background(), keys,
+		func(ctx context.Context, k int) (string, error) {
+			if k == 8 {
+				return "", fmt.Errorf("fail %d", k)
+			}
+			return "", nil
+		}, 2)
+	var kerr *orderedmap.KeyError
+	if !errors.As(err, &kerr) || kerr.Key != 8 || kerr.Err.Error() != "fail 8" {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+)
+
+// Pair is a struct which holds a key and a value of an entry.
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Page is a method which returns up to limit entries which follow the
+// position of the specified token, and the token of the next page, which is
+// an empty string if there are no more entries. An empty token means the
+// front of this map.
+//
+// A token is an opaque URL-safe string which can be passed to clients, e.g.
+// in a REST listing endpoint:
+//
+//	entries, next := om.Page(r.URL.Query().Get("page_token"), 100)
+//
+// Tokens are stable against changes of this map: entries after the position
+// of a token are returned even if the entry at the position was deleted, and
+// an entry which was deleted and stored again appears at its new position.
+// If the token is invalid, this method returns no entries and an empty token.
+// If limit is zero or less, this method returns no entries and the same
+// token.
+func (om *Map[K, V]) Page(token string, limit int) (entries []Pair[K, V], next string) {
+	if limit <= 0 {
+		return nil, token
+	}
+	ent, ok := om.pageStart(token)
+	if !ok {
+		return nil, ""
+	}
+
+	for ; ent != nil && len(entries) < limit; ent = ent.next {
+		entries = append(entries, Pair[K, V]{Key: ent.key, Value: ent.value})
+		if len(entries) == limit && ent.next != nil {
+			next = pageToken(ent)
+		}
+	}
+	return
+}
+
+// pageStart is a method which returns the first entry after the position of
+// a token. The entry at the position is found through the hash index if it is
+// still there, otherwise the entry list is walked by sequence numbers.
+func (om *Map[K, V]) pageStart(token string) (*Entry[K, V], bool) {
+	if om == nil {
+		return nil, token == ""
+	}
+	if token == "" {
+		return om.Front(), true
+	}
+
+	bs, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(bs) < 8 {
+		return nil, false
+	}
+	seq := binary.BigEndian.Uint64(bs)
+	if seq > om.seq {
+		return nil, false
+	}
+
+	if key, err := parseKey[K](string(bs[8:]), 0); err == nil {
+		if ent, exists := om.m[key]; exists && !ent.deleted && ent.seq == seq {
+			return ent.next, true
+		}
+	}
+
+	ent := om.Front()
+	for ent != nil && ent.seq <= seq {
+		ent = ent.next
+	}
+	return ent, true
+}
+
+// pageToken is a function which makes the token of the position of an entry
+// from its sequence number and the text form of its key. If the key has no
+// text form, the token has only the sequence number.
+func pageToken[K comparable, V any](ent *Entry[K, V]) string {
+	var buf bytes.Buffer
+	var seq [8]byte
+	binary.BigEndian.PutUint64(seq[:], ent.seq)
+	buf.Write(seq[:])
+	if addKeyText(&buf, ent.key) != nil {
+		buf.Truncate(8)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes())
+}
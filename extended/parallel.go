@@ -0,0 +1,49 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ForEachParallel is a method which calls the specified function: fn for each
+// key and value in this map with multiple goroutines.
+// The entry list is partitioned into contiguous chunks in the order of key
+// insertions, one chunk per worker, and each worker calls fn for the entries
+// of its chunk in order. The order of calls across chunks is not guaranteed.
+// If workers is less than 1, runtime.GOMAXPROCS(0) is used.
+// This map must not be modified until this method returns, and fn must be
+// safe for concurrent use.
+func (om *Map[K, V]) ForEachParallel(workers int, fn func(key K, value V)) {
+	if om == nil || om.len == 0 {
+		return
+	}
+	om.debugValidate()
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > om.len {
+		workers = om.len
+	}
+	size := (om.len + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	i := 0
+	for ent := om.head; ent != nil; ent = ent.next {
+		if i%size == 0 {
+			wg.Add(1)
+			go func(ent *Entry[K, V]) {
+				defer wg.Done()
+				for j := 0; j < size && ent != nil; j++ {
+					fn(ent.key, ent.value)
+					ent = ent.next
+				}
+			}(ent)
+		}
+		i++
+	}
+	wg.Wait()
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// patchOp is a struct which is an operation of a JSON Patch document.
+type patchOp struct {
+	Op    string          `json:"op"`
+	From  string          `json:"from,omitempty"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+	After *string         `json:"x-after,omitempty"`
+}
+
+// patchRecorder is a struct which holds the operations recorded since
+// StartRecording, and the first error which occurred while recording.
+type patchRecorder struct {
+	ops []patchOp
+	err error
+}
+
+// StartRecording is a method which starts recording mutations of this map as
+// a JSON Patch document (RFC 6902), or discards the recorded operations if
+// recording has been already started.
+//
+// An insertion is recorded as "add", an update as "replace", and a deletion,
+// an eviction, an expiration and Clear as "remove", with the JSON pointer of
+// the key and the JSON value.
+// A change of the order of entries, e.g. by a load of a map created by
+// NewLRU or by SortFunc, is recorded as a "move" of a key to the same path
+// with the extension member "x-after", which is the path of the key which the
+// moved key follows, or "" if the key is moved to the front.
+func (om *Map[K, V]) StartRecording() {
+	if om.ext == nil {
+		om.ext = &extension[K, V]{}
+	}
+	om.ext.patch = &patchRecorder{}
+}
+
+// StopRecording is a method which stops recording mutations of this map, and
+// returns the recorded operations as a JSON Patch document.
+// If a key or a value could not be encoded into JSON while recording, this
+// method returns the error. If recording has not been started, this method
+// returns an empty document.
+func (om *Map[K, V]) StopRecording() ([]byte, error) {
+	if om.ext == nil || om.ext.patch == nil {
+		return []byte("[]"), nil
+	}
+	rec := om.ext.patch
+	om.ext.patch = nil
+	if rec.err != nil {
+		return nil, rec.err
+	}
+	if rec.ops == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(rec.ops)
+}
+
+// recordPatch is a method which records an operation for an entry if this
+// map is recording mutations.
+func (om *Map[K, V]) recordPatch(op string, ent *Entry[K, V]) {
+	rec := om.ext.patch
+	if rec == nil || rec.err != nil {
+		return
+	}
+	path, err := keyPointer(ent.key)
+	if err != nil {
+		rec.err = err
+		return
+	}
+	p := patchOp{Op: op, Path: path}
+	switch op {
+	case "add", "replace":
+		p.Value, err = json.Marshal(ent.value)
+		if err != nil {
+			rec.err = err
+			return
+		}
+	case "move":
+		p.From = path
+		after := ""
+		if ent.prev != nil {
+			if after, err = keyPointer(ent.prev.key); err != nil {
+				rec.err = err
+				return
+			}
+		}
+		p.After = &after
+	}
+	rec.ops = append(rec.ops, p)
+}
+
+// keyPointer is a function which returns the JSON pointer of a key, whose
+// token is the text form of the key, i.e. the member name in JSON after
+// unescaping. The pointer is escaped when the patch is marshaled.
+func keyPointer(key any) (string, error) {
+	if s, ok := key.(string); ok {
+		return "/" + pointerEscaper.Replace(s), nil
+	}
+	var buf bytes.Buffer
+	if err := addKeyText(&buf, key); err != nil {
+		return "", err
+	}
+	return "/" + pointerEscaper.Replace(buf.String()), nil
+}
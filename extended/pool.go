@@ -0,0 +1,57 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"sync"
+)
+
+// PoolOptions is a struct which holds options of Pool.
+//
+// MaxLen is the maximum number of entries of a map which is reclaimed by Put.
+// A map which has ever had more entries at once is dropped instead, even if
+// entries were deleted before Put, because the hash index of a Go map never
+// shrinks and keeping it would pin the memory. If MaxLen is zero or less, all
+// maps are reclaimed.
+type PoolOptions struct {
+	MaxLen int
+}
+
+// Pool is a struct which hands out cleared ordered maps and reclaims them.
+// This is useful for request-scoped maps, e.g. in HTTP handlers, where
+// allocating a map per request dominates.
+// A Pool is safe for concurrent use, but maps taken from it are not.
+type Pool[K comparable, V any] struct {
+	pool sync.Pool
+	opts PoolOptions
+}
+
+// NewPool is a function which creates a new pool of ordered maps.
+func NewPool[K comparable, V any](opts PoolOptions) *Pool[K, V] {
+	p := &Pool[K, V]{opts: opts}
+	p.pool.New = func() any {
+		om := New[K, V]()
+		return &om
+	}
+	return p
+}
+
+// Get is a method which returns an empty ordered map from this pool.
+func (p *Pool[K, V]) Get() *Map[K, V] {
+	return p.pool.Get().(*Map[K, V])
+}
+
+// Put is a method which clears the specified ordered map and returns it to
+// this pool. The map and its entries must not be used after this call.
+func (p *Pool[K, V]) Put(om *Map[K, V]) {
+	if om == nil {
+		return
+	}
+	if p.opts.MaxLen > 0 && om.peak > p.opts.MaxLen {
+		return
+	}
+	om.Clear()
+	p.pool.Put(om)
+}
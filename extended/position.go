@@ -0,0 +1,60 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// EntryAt is a method which returns the i-th entry of this map in the order
+// of key insertions, where the index of the front entry is 0.
+// If i is out of range, this method returns nil.
+// This walks the entry list from the nearer end, so it costs O(len) time.
+func (om *Map[K, V]) EntryAt(i int) *Entry[K, V] {
+	if om == nil || i < 0 || i >= om.len {
+		return nil
+	}
+	if i <= om.len/2 {
+		ent := om.head
+		for ; i > 0; i-- {
+			ent = ent.next
+		}
+		return ent
+	}
+	ent := om.last
+	for j := om.len - 1; j > i; j-- {
+		ent = ent.prev
+	}
+	return ent
+}
+
+// At is a method which returns the key and the value of the i-th entry of
+// this map in the order of key insertions, like EntryAt.
+// If i is out of range, the ok result is false.
+func (om *Map[K, V]) At(i int) (key K, value V, ok bool) {
+	ent := om.EntryAt(i)
+	if ent == nil {
+		return
+	}
+	return ent.key, ent.value, true
+}
+
+// IndexOf is a method which returns the index of the entry for a key in the
+// order of key insertions, where the index of the front entry is 0.
+// If the key is not present, this method returns -1.
+// This walks the entry list from the front, so it costs O(len) time.
+func (om *Map[K, V]) IndexOf(key K) int {
+	if om == nil {
+		return -1
+	}
+	target, exists := om.m[key]
+	if !exists || target.deleted {
+		return -1
+	}
+	i := 0
+	for ent := om.head; ent != nil; ent = ent.next {
+		if ent == target {
+			return i
+		}
+		i++
+	}
+	return -1
+}
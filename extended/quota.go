@@ -0,0 +1,234 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"sort"
+	"strings"
+)
+
+// WithPrefixQuota is a function which returns an option to cap the number of
+// entries whose keys start with the specified prefix, e.g. "tenantA/". When
+// an insertion makes the entries of a prefix exceed n, the oldest entry of
+// the prefix is evicted with the reason EvictPrefixQuota, so that entries of
+// other prefixes are never evicted for it.
+// This option can be specified for several prefixes. A key is counted for
+// the longest prefix which it starts with.
+// If n is zero or less, the entries of the prefix are only counted.
+// The key type of the map must be string, otherwise New panics.
+func WithPrefixQuota(prefix string, n int) Option {
+	return func(o *options) {
+		if o.quotas == nil {
+			o.quotas = make(map[string]int)
+		}
+		o.quotas[prefix] = n
+	}
+}
+
+// Stats is a struct which holds statistics of a Map.
+//
+// PrefixCounts has the number of entries per prefix set by WithPrefixQuota.
+// It is nil if no quota is set.
+type Stats struct {
+	Len          int
+	PrefixCounts map[string]int
+}
+
+// Stats is a method which returns the statistics of this map.
+func (om *Map[K, V]) Stats() Stats {
+	st := Stats{Len: om.Len()}
+	if om != nil && om.ext != nil && om.ext.quota != nil {
+		st.PrefixCounts = make(map[string]int, len(om.ext.quota.counts))
+		for prefix, n := range om.ext.quota.counts {
+			st.PrefixCounts[prefix] = n
+		}
+	}
+	return st
+}
+
+// prefixQuota is a struct which holds the quotas and the counts of entries
+// per prefix, and the entries of each prefix which has a positive quota in
+// the order of the entry list, so that the oldest entry of a prefix is found
+// without scanning the entries of other prefixes.
+type prefixQuota[K comparable, V any] struct {
+	prefixes []string // sorted from the longest
+	limits   map[string]int
+	counts   map[string]int
+	lists    map[string]*quotaList[K, V]
+	nodes    map[*Entry[K, V]]*quotaNode[K, V]
+}
+
+// quotaList is a struct which is a list of the entries of a prefix in the
+// order of their sequence numbers, i.e. in the order of the entry list.
+type quotaList[K comparable, V any] struct {
+	front *quotaNode[K, V]
+	back  *quotaNode[K, V]
+}
+
+type quotaNode[K comparable, V any] struct {
+	ent  *Entry[K, V]
+	list *quotaList[K, V]
+	prev *quotaNode[K, V]
+	next *quotaNode[K, V]
+}
+
+func newPrefixQuota[K comparable, V any](limits map[string]int) *prefixQuota[K, V] {
+	q := &prefixQuota[K, V]{
+		limits: limits,
+		counts: make(map[string]int, len(limits)),
+		lists:  make(map[string]*quotaList[K, V]),
+		nodes:  make(map[*Entry[K, V]]*quotaNode[K, V]),
+	}
+	for prefix, n := range limits {
+		q.prefixes = append(q.prefixes, prefix)
+		q.counts[prefix] = 0
+		if n > 0 {
+			q.lists[prefix] = &quotaList[K, V]{}
+		}
+	}
+	sort.Slice(q.prefixes, func(i, j int) bool {
+		return len(q.prefixes[i]) > len(q.prefixes[j])
+	})
+	return q
+}
+
+func (q *prefixQuota[K, V]) prefixOf(key K) (string, bool) {
+	s := any(key).(string)
+	for _, prefix := range q.prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// reset is a method which recounts the entries from the specified entry to
+// the back of the entry list, and rebuilds the lists of the prefixes.
+func (q *prefixQuota[K, V]) reset(head *Entry[K, V]) {
+	for prefix := range q.counts {
+		q.counts[prefix] = 0
+	}
+	for _, l := range q.lists {
+		l.front, l.back = nil, nil
+	}
+	q.nodes = make(map[*Entry[K, V]]*quotaNode[K, V])
+	for ent := head; ent != nil; ent = ent.next {
+		prefix, ok := q.prefixOf(ent.key)
+		if !ok {
+			continue
+		}
+		q.counts[prefix]++
+		if l := q.lists[prefix]; l != nil {
+			n := &quotaNode[K, V]{ent: ent, list: l}
+			l.pushBack(n)
+			q.nodes[ent] = n
+		}
+	}
+}
+
+func (l *quotaList[K, V]) pushBack(n *quotaNode[K, V]) {
+	n.prev = l.back
+	n.next = nil
+	if l.back != nil {
+		l.back.next = n
+	} else {
+		l.front = n
+	}
+	l.back = n
+}
+
+func (l *quotaList[K, V]) remove(n *quotaNode[K, V]) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else {
+		l.front = n.next
+	}
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else {
+		l.back = n.prev
+	}
+	n.prev, n.next = nil, nil
+}
+
+// place is a method which puts a node after the last node whose entry has a
+// smaller sequence number. This searches from the back, so placing an entry
+// which is at the back of the entry list takes constant time.
+func (l *quotaList[K, V]) place(n *quotaNode[K, V]) {
+	p := l.back
+	for p != nil && p.ent.seq > n.ent.seq {
+		p = p.prev
+	}
+	if p == nil {
+		n.prev = nil
+		n.next = l.front
+		if l.front != nil {
+			l.front.prev = n
+		} else {
+			l.back = n
+		}
+		l.front = n
+		return
+	}
+	n.prev = p
+	n.next = p.next
+	if p.next != nil {
+		p.next.prev = n
+	} else {
+		l.back = n
+	}
+	p.next = n
+}
+
+// quotaLinked is a method which counts an inserted entry for its prefix, and
+// evicts the oldest entries of the prefix if it exceeds its quota.
+// This must be called only when om.ext.quota is not nil.
+func (om *Map[K, V]) quotaLinked(ent *Entry[K, V]) {
+	q := om.ext.quota
+	prefix, ok := q.prefixOf(ent.key)
+	if !ok {
+		return
+	}
+	q.counts[prefix]++
+	l := q.lists[prefix]
+	if l == nil {
+		return
+	}
+	n := &quotaNode[K, V]{ent: ent, list: l}
+	l.place(n)
+	q.nodes[ent] = n
+	for q.counts[prefix] > q.limits[prefix] {
+		om.evict(l.front.ent, EvictPrefixQuota)
+	}
+}
+
+// quotaMoved is a method which keeps the list of the prefix of a moved entry
+// in the order of the entry list.
+// This must be called only when om.ext.quota is not nil.
+func (om *Map[K, V]) quotaMoved(ent *Entry[K, V]) {
+	n := om.ext.quota.nodes[ent]
+	if n == nil {
+		return
+	}
+	if (n.prev == nil || n.prev.ent.seq < ent.seq) &&
+		(n.next == nil || n.next.ent.seq > ent.seq) {
+		return
+	}
+	n.list.remove(n)
+	n.list.place(n)
+}
+
+// quotaUnlinked is a method which uncounts a removed entry for its prefix.
+// This must be called only when om.ext.quota is not nil.
+func (om *Map[K, V]) quotaUnlinked(ent *Entry[K, V]) {
+	q := om.ext.quota
+	if prefix, ok := q.prefixOf(ent.key); ok {
+		q.counts[prefix]--
+	}
+	if n := q.nodes[ent]; n != nil {
+		n.list.remove(n)
+		delete(q.nodes, ent)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitMode is a type which represents what a rate-limited map does when
+// a mutation exceeds the limit set by WithRateLimit.
+type RateLimitMode int
+
+const (
+	// RateLimitReject makes the mutation fail with ErrRateLimited.
+	RateLimitReject RateLimitMode = iota
+
+	// RateLimitWait makes the mutation wait until it is allowed or its context
+	// is done.
+	RateLimitWait
+)
+
+// WithRateLimit is a function which returns an option to limit the rate of
+// mutations with a token bucket, which allows burst mutations at once and
+// perSecond mutations per second on average.
+// If perSecond is zero or less, mutations are not limited.
+//
+// Each call of a method which modifies entries or their order takes a token,
+// e.g. Store, Swap, Delete, DeleteAll, DeleteFunc, Pop, Clear, MoveToFront,
+// SortFunc, Merge, and the functions Add, Inc, Append and GroupAppend.
+// LoadOrStore and LoadOrStoreFunc take a token only when they store a value.
+// Evictions, expirations and decoding methods, e.g. UnmarshalJSON, are not
+// limited.
+//
+// StoreCtx, TryStore and DeleteCtx return ErrRateLimited in a KeyError, and
+// StoreCtx and DeleteCtx wait with their contexts in the RateLimitWait mode.
+// Other methods wait with context.Background in the RateLimitWait mode, and
+// do nothing in the RateLimitReject mode: they return the results for an
+// absent key, e.g. the zero value and false for Swap and LoadOrStore.
+// Code on request paths should use the methods with an error result to know
+// rejections.
+func WithRateLimit(perSecond float64, burst int, mode RateLimitMode) Option {
+	return func(o *options) {
+		o.ratePerSecond = perSecond
+		o.rateBurst = burst
+		o.rateMode = mode
+	}
+}
+
+// limited is a method which takes a token for a mutation by a method without
+// an error result, and reports whether the mutation is rejected.
+func (om *Map[K, V]) limited() bool {
+	return om.ext != nil && om.ext.limiter != nil &&
+		om.ext.limiter.take(context.Background()) != nil
+}
+
+// tokenBucket is a struct which holds the state of a token bucket.
+type tokenBucket struct {
+	perSecond float64
+	burst     float64
+	mode      RateLimitMode
+	tokens    float64
+	last      time.Time
+}
+
+func newTokenBucket(perSecond float64, burst int, mode RateLimitMode) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		perSecond: perSecond,
+		burst:     float64(burst),
+		mode:      mode,
+		tokens:    float64(burst),
+		last:      time.Now(),
+	}
+}
+
+// take is a method which takes a token from this bucket, waiting for it in
+// the RateLimitWait mode.
+func (tb *tokenBucket) take(ctx context.Context) error {
+	for {
+		now := time.Now()
+		tb.tokens += now.Sub(tb.last).Seconds() * tb.perSecond
+		if tb.tokens > tb.burst {
+			tb.tokens = tb.burst
+		}
+		tb.last = now
+
+		if tb.tokens >= 1 {
+			tb.tokens--
+			return nil
+		}
+		if tb.mode != RateLimitWait {
+			return ErrRateLimited
+		}
+
+		wait := time.Duration((1 - tb.tokens) / tb.perSecond * float64(time.Second))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
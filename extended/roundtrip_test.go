@@ -0,0 +1,101 @@
+package extended_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+// TestRoundTrip checks that every variant of ordered maps is encoded and
+// decoded with JSON and gob in the same form as Map.
+func TestRoundTrip(t *testing.T) {
+	codecs := []struct {
+		name      string
+		marshal   func(v any) ([]byte, error)
+		unmarshal func(data []byte, v any) error
+	}{
+		{"JSON", json.Marshal, json.Unmarshal},
+		{"gob", func(v any) ([]byte, error) {
+			var buf bytes.Buffer
+			err := gob.NewEncoder(&buf).Encode(v)
+			return buf.Bytes(), err
+		}, func(data []byte, v any) error {
+			return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+		}},
+	}
+
+	newMap := func(opts ...orderedmap.Option) *orderedmap.Map[string, int] {
+		om := orderedmap.New[string, int](opts...)
+		om.Store("c", 1)
+		om.Store("a", 2)
+		om.Store("b", 3)
+		return &om
+	}
+	newEmpty := func() any {
+		om := orderedmap.New[string, int]()
+		return &om
+	}
+
+	ttl := newMap()
+	ttl.StoreWithTTL("a", 20, time.Hour)
+	ttl.StoreWithTTL("x", 9, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	sm := orderedmap.NewSync[string, int]()
+	sm.Store("c", 1)
+	sm.Store("a", 2)
+
+	lower := orderedmap.New[string, int]()
+	lower.Store("a", 10)
+	lower.Store("d", 4)
+
+	variants := []struct {
+		name string
+		src  any
+		dst  func() any
+		want string
+	}{
+		{"Map", newMap(), newEmpty, "Map[c:1 a:2 b:3]"},
+		{"TTL", ttl, newEmpty, "Map[c:1 a:20 b:3]"},
+		{"Bounded", newMap(orderedmap.WithMaxLen(3)), func() any {
+			om := orderedmap.NewLRU[string, int](2)
+			return &om
+		}, "Map[a:2 b:3]"},
+		{"Sync", sm, func() any {
+			return orderedmap.NewSync[string, int]()
+		}, "SyncMap[c:1 a:2]"},
+		{"View", newMap().View(func(k string, v int) bool { return v != 2 }), newEmpty,
+			"Map[c:1 b:3]"},
+		{"ChainView", orderedmap.Chain(newMap(), &lower), newEmpty, "Map[c:1 a:2 b:3 d:4]"},
+	}
+
+	for _, c := range codecs {
+		for _, v := range variants {
+			t.Run(c.name+"/"+v.name, func(t *testing.T) {
+				data, err := c.marshal(v.src)
+				if err != nil {
+					t.Fatal(err)
+				}
+				dst := v.dst()
+				if err := c.unmarshal(data, dst); err != nil {
+					t.Fatal(err)
+				}
+				if s := fmt.Sprint(dst); s != v.want {
+					t.Errorf("decoded = %s, want %s", s, v.want)
+				}
+			})
+		}
+	}
+
+	om := newEmpty().(*orderedmap.Map[string, int])
+	data, _ := json.Marshal(ttl)
+	json.Unmarshal(data, om)
+	if ent := om.Front().Next(); !ent.ExpiresAt().IsZero() {
+		t.Errorf("expiration time is decoded: %v", ent.ExpiresAt())
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// RowFillPolicy is a type which specifies how EachRow and Rows handle a key
+// of a schema which is not in a map.
+type RowFillPolicy int
+
+const (
+	// RowFillZero fills the value for a missing key with the zero value.
+	RowFillZero RowFillPolicy = iota
+
+	// RowSkipIncomplete skips a map which lacks any key of the schema.
+	RowSkipIncomplete
+)
+
+// EachRow is a function which calls the specified function: fn for each of
+// the specified maps with a row, which is the values of the map for the
+// schema keys in the order of the schema keys.
+// Keys of a map which are not in the schema are ignored, and keys of the
+// schema which are not in a map are handled according to the policy. A nil
+// map is treated as an empty map.
+// The row slice passed to fn is reused between calls, so fn must not retain
+// it after it returns. If fn returns false, this function stops the
+// iteration.
+func EachRow[K comparable, V any](
+	maps []*Map[K, V],
+	schemaKeys []K,
+	policy RowFillPolicy,
+	fn func(row []V) bool,
+) {
+	row := make([]V, len(schemaKeys))
+	var zero V
+outer:
+	for _, om := range maps {
+		var m map[K]*Entry[K, V]
+		if om != nil {
+			m = om.m
+		}
+		for i, key := range schemaKeys {
+			ent, exists := m[key]
+			if !exists || ent.deleted {
+				if policy == RowSkipIncomplete {
+					continue outer
+				}
+				row[i] = zero
+				continue
+			}
+			row[i] = ent.value
+		}
+		if !fn(row) {
+			return
+		}
+	}
+}
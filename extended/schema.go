@@ -0,0 +1,671 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SchemaDialect is the JSON Schema dialect which InferSchema declares with
+// the "$schema" keyword.
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// InferSchema is a function which returns a JSON Schema describing the
+// specified map as a sample document. The schema is also an ordered map, so
+// the "properties" of every object keep the order of keys in the document and
+// the output of MarshalJSON is same on every run.
+//
+// Nested objects are described from *Map[string, any] (e.g. decoded with
+// WithNestedOrder), Map[string, any] and map[string]any, where keys of
+// map[string]any are in sorted order. Every key of an object is listed in
+// "required". The "items" of an array merge the schemas of its elements:
+// objects are merged into one schema whose properties are in the order of
+// their first appearances and which requires only the keys in all elements,
+// integers and numbers are merged into "number", and other differing schemas
+// are listed in "anyOf".
+func InferSchema(om *Map[string, any]) *Map[string, any] {
+	schema := New[string, any]()
+	schema.Store("$schema", SchemaDialect)
+	fillObjectSchema(&schema, om.Range)
+	return &schema
+}
+
+// inferSchema is a function which returns the JSON Schema of a value.
+// Values of unknown types get an empty schema, which accepts any value.
+func inferSchema(value any) *Map[string, any] {
+	schema := New[string, any]()
+	switch v := value.(type) {
+	case nil:
+		schema.Store("type", "null")
+	case bool:
+		schema.Store("type", "boolean")
+	case string:
+		schema.Store("type", "string")
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			schema.Store("type", "integer")
+		} else {
+			schema.Store("type", "number")
+		}
+	case float64:
+		schema.Store("type", floatSchemaType(v))
+	case float32:
+		schema.Store("type", floatSchemaType(float64(v)))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		schema.Store("type", "integer")
+	case []any:
+		schema.Store("type", "array")
+		list := make([]*Map[string, any], len(v))
+		for i, elem := range v {
+			list[i] = inferSchema(elem)
+		}
+		if items := unionSchemas(list); items != nil {
+			schema.Store("items", items)
+		}
+	case *Map[string, any]:
+		fillObjectSchema(&schema, v.Range)
+	case Map[string, any]:
+		fillObjectSchema(&schema, v.Range)
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fillObjectSchema(&schema, func(fn func(string, any) bool) {
+			for _, key := range keys {
+				if !fn(key, v[key]) {
+					return
+				}
+			}
+		})
+	}
+	return &schema
+}
+
+// floatSchemaType is a function which returns "integer" for a float without
+// a fractional part, as JSON decoders give float64 for integers, and returns
+// "number" otherwise.
+func floatSchemaType(f float64) string {
+	if f == math.Trunc(f) && !math.IsInf(f, 0) {
+		return "integer"
+	}
+	return "number"
+}
+
+// fillObjectSchema is a function which stores the keywords of an object
+// schema whose properties are the entries given by the specified range
+// function, in that order.
+func fillObjectSchema(schema *Map[string, any], each func(func(string, any) bool)) {
+	props := New[string, any]()
+	required := []string{}
+	each(func(key string, value any) bool {
+		props.Store(key, inferSchema(value))
+		required = append(required, key)
+		return true
+	})
+	schema.Store("type", "object")
+	schema.Store("properties", &props)
+	schema.Store("required", required)
+}
+
+// unionSchemas is a function which returns a schema accepting the values of
+// all the specified schemas. Mergeable schemas are merged, and the rest are
+// listed in "anyOf" in the order of their first appearances. This function
+// returns nil if the list is empty.
+func unionSchemas(list []*Map[string, any]) *Map[string, any] {
+	var merged []*Map[string, any]
+	for _, schema := range list {
+		for _, alt := range schemaAlternatives(schema) {
+			found := false
+			for i, m := range merged {
+				if s := mergeSchemas(m, alt); s != nil {
+					merged[i] = s
+					found = true
+					break
+				}
+			}
+			if !found {
+				merged = append(merged, alt)
+			}
+		}
+	}
+
+	switch len(merged) {
+	case 0:
+		return nil
+	case 1:
+		return merged[0]
+	}
+	anyOf := New[string, any]()
+	anyOf.Store("anyOf", merged)
+	return &anyOf
+}
+
+// schemaAlternatives is a function which returns the schemas listed in
+// "anyOf" of the specified schema, or the schema itself.
+func schemaAlternatives(schema *Map[string, any]) []*Map[string, any] {
+	if v, ok := schema.Load("anyOf"); ok {
+		if list, ok := v.([]*Map[string, any]); ok {
+			return list
+		}
+	}
+	return []*Map[string, any]{schema}
+}
+
+// mergeSchemas is a function which merges two schemas of the same type, or
+// of "integer" and "number", into one schema. This function returns nil if
+// the schemas cannot be merged.
+func mergeSchemas(a, b *Map[string, any]) *Map[string, any] {
+	ta, _ := a.Load("type")
+	tb, _ := b.Load("type")
+	if ta != tb {
+		if (ta == "integer" && tb == "number") || (ta == "number" && tb == "integer") {
+			schema := New[string, any]()
+			schema.Store("type", "number")
+			return &schema
+		}
+		return nil
+	}
+
+	switch ta {
+	case "object":
+		return mergeObjectSchemas(a, b)
+	case "array":
+		var list []*Map[string, any]
+		for _, s := range []*Map[string, any]{a, b} {
+			if items, ok := s.Load("items"); ok {
+				list = append(list, items.(*Map[string, any]))
+			}
+		}
+		schema := New[string, any]()
+		schema.Store("type", "array")
+		if items := unionSchemas(list); items != nil {
+			schema.Store("items", items)
+		}
+		return &schema
+	}
+	return a
+}
+
+// mergeObjectSchemas is a function which merges two object schemas. The
+// properties are in the order of their first appearances, and the required
+// keys are the keys required by both schemas.
+func mergeObjectSchemas(a, b *Map[string, any]) *Map[string, any] {
+	pa, _ := a.Load("properties")
+	pb, _ := b.Load("properties")
+	propsA, propsB := pa.(*Map[string, any]), pb.(*Map[string, any])
+
+	props := New[string, any]()
+	propsA.Range(func(key string, value any) bool {
+		if other, ok := propsB.Load(key); ok {
+			value = unionSchemas([]*Map[string, any]{
+				value.(*Map[string, any]), other.(*Map[string, any]),
+			})
+		}
+		props.Store(key, value)
+		return true
+	})
+	propsB.Range(func(key string, value any) bool {
+		props.LoadOrStore(key, value)
+		return true
+	})
+
+	ra, _ := a.Load("required")
+	rb, _ := b.Load("required")
+	inB := make(map[string]bool)
+	for _, key := range rb.([]string) {
+		inB[key] = true
+	}
+	required := []string{}
+	for _, key := range ra.([]string) {
+		if inB[key] {
+			required = append(required, key)
+		}
+	}
+
+	schema := New[string, any]()
+	schema.Store("type", "object")
+	schema.Store("properties", &props)
+	schema.Store("required", required)
+	return &schema
+}
+
+// SchemaError is an error type which reports that a value violates a keyword
+// of a JSON Schema. It is held in the ErrorMap returned by ValidateSchema.
+type SchemaError struct {
+	Keyword string
+	msg     string
+}
+
+func (err SchemaError) Error() string {
+	return err.Keyword + ": " + err.msg
+}
+
+// ValidateSchema is a method which validates the JSON document of this map
+// against the specified JSON Schema, and returns the violations keyed by
+// the JSON pointers of the violating values, e.g. "/servers/0/port", in the
+// order of the document. A missing required property is reported at its
+// object. If there is no violation, this method returns nil.
+//
+// The supported keywords are type, enum, const, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, multipleOf, minLength, maxLength,
+// pattern, items, prefixItems, minItems, maxItems, uniqueItems, properties,
+// patternProperties, additionalProperties, required, minProperties,
+// maxProperties, allOf, anyOf, oneOf, not and $ref to the same schema
+// (e.g. "#/$defs/port"). Other keywords are ignored.
+// If the schema is not valid JSON or the map cannot be encoded into JSON,
+// the error is reported for the root pointer "".
+func (om *Map[K, V]) ValidateSchema(schema []byte) *ErrorMap[string] {
+	errs := &ErrorMap[string]{}
+
+	var root any
+	if err := json.Unmarshal(schema, &root); err != nil {
+		errs.Add("", fmt.Errorf("orderedmap: invalid schema: %w", err))
+		return errs
+	}
+	data, err := om.MarshalJSON()
+	if err != nil {
+		errs.Add("", err)
+		return errs
+	}
+	doc, err := decodeOrderedAny(json.NewDecoder(bytes.NewReader(data)))
+	if err != nil {
+		errs.Add("", err)
+		return errs
+	}
+
+	v := schemaValidator{root: root, errs: errs}
+	v.validate(root, doc, "")
+	if errs.Len() == 0 {
+		return nil
+	}
+
+	// Keywords like allOf may report a value after the values following it,
+	// so the errors are sorted by the positions of their pointers.
+	pos := make(map[string]int)
+	walkPointers(doc, "", pos)
+	errs.SortFunc(func(a, b *Entry[string, error]) bool {
+		return pos[a.Key()] < pos[b.Key()]
+	})
+	return errs
+}
+
+// maxSchemaRefDepth is the maximum depth of nested $ref resolutions, to stop
+// validations with recursive schemas which do not consume the document.
+const maxSchemaRefDepth = 64
+
+// schemaValidator is a struct which validates a document decoded by
+// decodeOrderedAny against a schema decoded by json.Unmarshal.
+type schemaValidator struct {
+	root     any
+	errs     *ErrorMap[string]
+	refDepth int
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// walkPointers is a function which numbers the JSON pointers of a document
+// in the order of the document.
+func walkPointers(value any, ptr string, pos map[string]int) {
+	pos[ptr] = len(pos)
+	switch v := value.(type) {
+	case *Map[string, any]:
+		v.Range(func(key string, elem any) bool {
+			walkPointers(elem, ptr+"/"+pointerEscaper.Replace(key), pos)
+			return true
+		})
+	case []any:
+		for i, elem := range v {
+			walkPointers(elem, ptr+"/"+strconv.Itoa(i), pos)
+		}
+	}
+}
+
+func (v *schemaValidator) fail(ptr, keyword, format string, a ...any) {
+	v.errs.Add(ptr, SchemaError{Keyword: keyword, msg: fmt.Sprintf(format, a...)})
+}
+
+// matches is a method which reports whether a value is valid against a
+// schema, without reporting violations.
+func (v *schemaValidator) matches(schema, value any, ptr string) bool {
+	sub := schemaValidator{root: v.root, errs: &ErrorMap[string]{}, refDepth: v.refDepth}
+	sub.validate(schema, value, ptr)
+	return sub.errs.Len() == 0
+}
+
+func (v *schemaValidator) validate(schema, value any, ptr string) {
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			v.fail(ptr, "false", "no value is allowed")
+		}
+		return
+	case map[string]any:
+		v.validateKeywords(s, value, ptr)
+	}
+}
+
+func (v *schemaValidator) validateKeywords(s map[string]any, value any, ptr string) {
+	if ref, ok := s["$ref"].(string); ok {
+		target, ok := resolveSchemaRef(v.root, ref)
+		switch {
+		case !ok:
+			v.fail(ptr, "$ref", "cannot resolve %q", ref)
+		case v.refDepth >= maxSchemaRefDepth:
+			v.fail(ptr, "$ref", "too deep references at %q", ref)
+		default:
+			v.refDepth++
+			v.validate(target, value, ptr)
+			v.refDepth--
+		}
+	}
+
+	if t, ok := s["type"]; ok && !schemaTypeMatches(t, value) {
+		v.fail(ptr, "type", "expected %s, but got %s", schemaTypeText(t), jsonTypeOf(value))
+	}
+	if enum, ok := s["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if jsonEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(ptr, "enum", "the value is not one of %s", marshalSchemaValue(enum))
+		}
+	}
+	if c, ok := s["const"]; ok && !jsonEqual(c, value) {
+		v.fail(ptr, "const", "the value is not %s", marshalSchemaValue(c))
+	}
+
+	switch val := value.(type) {
+	case float64:
+		v.validateNumber(s, val, ptr)
+	case string:
+		v.validateString(s, val, ptr)
+	case []any:
+		v.validateArray(s, val, ptr)
+	case *Map[string, any]:
+		v.validateObject(s, val, ptr)
+	}
+
+	if list, ok := s["allOf"].([]any); ok {
+		for _, sub := range list {
+			v.validate(sub, value, ptr)
+		}
+	}
+	if list, ok := s["anyOf"].([]any); ok {
+		found := false
+		for _, sub := range list {
+			if v.matches(sub, value, ptr) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(ptr, "anyOf", "the value matches none of the schemas")
+		}
+	}
+	if list, ok := s["oneOf"].([]any); ok {
+		n := 0
+		for _, sub := range list {
+			if v.matches(sub, value, ptr) {
+				n++
+			}
+		}
+		if n != 1 {
+			v.fail(ptr, "oneOf", "the value matches %d schemas, not exactly one", n)
+		}
+	}
+	if sub, ok := s["not"]; ok && v.matches(sub, value, ptr) {
+		v.fail(ptr, "not", "the value matches the schema")
+	}
+}
+
+func (v *schemaValidator) validateNumber(s map[string]any, f float64, ptr string) {
+	if min, ok := s["minimum"].(float64); ok && f < min {
+		v.fail(ptr, "minimum", "%v is less than %v", f, min)
+	}
+	if max, ok := s["maximum"].(float64); ok && f > max {
+		v.fail(ptr, "maximum", "%v is greater than %v", f, max)
+	}
+	if min, ok := s["exclusiveMinimum"].(float64); ok && f <= min {
+		v.fail(ptr, "exclusiveMinimum", "%v is not greater than %v", f, min)
+	}
+	if max, ok := s["exclusiveMaximum"].(float64); ok && f >= max {
+		v.fail(ptr, "exclusiveMaximum", "%v is not less than %v", f, max)
+	}
+	if d, ok := s["multipleOf"].(float64); ok && d > 0 {
+		if q := f / d; q != math.Trunc(q) {
+			v.fail(ptr, "multipleOf", "%v is not a multiple of %v", f, d)
+		}
+	}
+}
+
+func (v *schemaValidator) validateString(s map[string]any, str string, ptr string) {
+	n := utf8.RuneCountInString(str)
+	if min, ok := s["minLength"].(float64); ok && float64(n) < min {
+		v.fail(ptr, "minLength", "the length %d is less than %v", n, min)
+	}
+	if max, ok := s["maxLength"].(float64); ok && float64(n) > max {
+		v.fail(ptr, "maxLength", "the length %d is greater than %v", n, max)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			v.fail(ptr, "pattern", "invalid pattern %q: %v", pattern, err)
+		} else if !re.MatchString(str) {
+			v.fail(ptr, "pattern", "%q does not match %q", str, pattern)
+		}
+	}
+}
+
+func (v *schemaValidator) validateArray(s map[string]any, arr []any, ptr string) {
+	n := len(arr)
+	if min, ok := s["minItems"].(float64); ok && float64(n) < min {
+		v.fail(ptr, "minItems", "%d items are less than %v", n, min)
+	}
+	if max, ok := s["maxItems"].(float64); ok && float64(n) > max {
+		v.fail(ptr, "maxItems", "%d items are more than %v", n, max)
+	}
+	if unique, _ := s["uniqueItems"].(bool); unique {
+	dup:
+		for i := 1; i < n; i++ {
+			for j := 0; j < i; j++ {
+				if jsonEqual(arr[i], arr[j]) {
+					v.fail(ptr, "uniqueItems", "items %d and %d are equal", j, i)
+					break dup
+				}
+			}
+		}
+	}
+
+	prefix, _ := s["prefixItems"].([]any)
+	for i, elem := range arr {
+		elemPtr := ptr + "/" + strconv.Itoa(i)
+		if i < len(prefix) {
+			v.validate(prefix[i], elem, elemPtr)
+		} else if items, ok := s["items"]; ok {
+			v.validate(items, elem, elemPtr)
+		}
+	}
+}
+
+func (v *schemaValidator) validateObject(s map[string]any, obj *Map[string, any], ptr string) {
+	if required, ok := s["required"].([]any); ok {
+		for _, r := range required {
+			if key, ok := r.(string); ok {
+				if _, exists := obj.Load(key); !exists {
+					v.fail(ptr, "required", "the property %q is missing", key)
+				}
+			}
+		}
+	}
+	n := obj.Len()
+	if min, ok := s["minProperties"].(float64); ok && float64(n) < min {
+		v.fail(ptr, "minProperties", "%d properties are less than %v", n, min)
+	}
+	if max, ok := s["maxProperties"].(float64); ok && float64(n) > max {
+		v.fail(ptr, "maxProperties", "%d properties are more than %v", n, max)
+	}
+
+	props, _ := s["properties"].(map[string]any)
+	patterns, _ := s["patternProperties"].(map[string]any)
+	patternKeys := make([]string, 0, len(patterns))
+	for pattern := range patterns {
+		patternKeys = append(patternKeys, pattern)
+	}
+	sort.Strings(patternKeys)
+	additional, hasAdditional := s["additionalProperties"]
+	obj.Range(func(key string, elem any) bool {
+		elemPtr := ptr + "/" + pointerEscaper.Replace(key)
+		matched := false
+		if sub, ok := props[key]; ok {
+			v.validate(sub, elem, elemPtr)
+			matched = true
+		}
+		for _, pattern := range patternKeys {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(key) {
+				v.validate(patterns[pattern], elem, elemPtr)
+				matched = true
+			}
+		}
+		if !matched && hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				v.fail(elemPtr, "additionalProperties", "the property %q is not allowed", key)
+			} else {
+				v.validate(additional, elem, elemPtr)
+			}
+		}
+		return true
+	})
+}
+
+// resolveSchemaRef is a function which returns the subschema referred by a
+// JSON pointer fragment in the root schema, e.g. "#/$defs/port".
+func resolveSchemaRef(root any, ref string) (any, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+	cur := root
+	if ref == "#" {
+		return cur, true
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, false
+	}
+	for _, tok := range strings.Split(ref[2:], "/") {
+		tok = pointerUnescaper.Replace(tok)
+		switch c := cur.(type) {
+		case map[string]any:
+			next, ok := c[tok]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []any:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+			cur = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// jsonTypeOf is a function which returns the JSON Schema type name of a
+// decoded value.
+func jsonTypeOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		return floatSchemaType(v)
+	case []any:
+		return "array"
+	case *Map[string, any]:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func schemaTypeMatches(t any, value any) bool {
+	switch t := t.(type) {
+	case string:
+		actual := jsonTypeOf(value)
+		return t == actual || (t == "number" && actual == "integer")
+	case []any:
+		for _, e := range t {
+			if schemaTypeMatches(e, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func schemaTypeText(t any) string {
+	if list, ok := t.([]any); ok {
+		names := make([]string, len(list))
+		for i, e := range list {
+			names[i] = fmt.Sprint(e)
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+// jsonEqual is a function which reports whether two values in a schema or a
+// document are equal as JSON values.
+func jsonEqual(a, b any) bool {
+	return reflect.DeepEqual(plainJSONValue(a), plainJSONValue(b))
+}
+
+// plainJSONValue is a function which converts *Map[string, any] in a value
+// into map[string]any at every depth, to compare it with a value decoded by
+// json.Unmarshal.
+func plainJSONValue(value any) any {
+	switch v := value.(type) {
+	case *Map[string, any]:
+		m := make(map[string]any, v.Len())
+		v.Range(func(key string, elem any) bool {
+			m[key] = plainJSONValue(elem)
+			return true
+		})
+		return m
+	case []any:
+		arr := make([]any, len(v))
+		for i, elem := range v {
+			arr[i] = plainJSONValue(elem)
+		}
+		return arr
+	}
+	return value
+}
+
+func marshalSchemaValue(value any) string {
+	b, _ := json.Marshal(value)
+	return string(b)
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// defaultScopeChunkSize is the number of entries in a chunk of a Scope when
+// the chunk size is not specified.
+const defaultScopeChunkSize = 256
+
+// Scope is a struct which creates many short-lived ordered maps and frees
+// them together.
+//
+// The entries of maps created by a Scope are allocated from chunks shared by
+// those maps instead of one by one, so the garbage collector sees a few large
+// objects instead of millions of small ones. Entries deleted from a map are
+// not reused until Free is called.
+// A Scope is not safe for concurrent use.
+type Scope[K comparable, V any] struct {
+	chunkSize int
+	chunk     []Entry[K, V]
+	maps      []*Map[K, V]
+}
+
+// NewScope is a function which creates a new Scope. chunkSize is the number
+// of entries allocated at once. If it is zero or less, a default is used.
+func NewScope[K comparable, V any](chunkSize int) *Scope[K, V] {
+	if chunkSize <= 0 {
+		chunkSize = defaultScopeChunkSize
+	}
+	return &Scope[K, V]{chunkSize: chunkSize}
+}
+
+// New is a method which creates a new empty ordered map belonging to this
+// scope.
+func (sc *Scope[K, V]) New() *Map[K, V] {
+	om := &Map[K, V]{
+		m:   make(map[K](*Entry[K, V])),
+		ext: &extension[K, V]{scope: sc},
+	}
+	sc.maps = append(sc.maps, om)
+	return om
+}
+
+// Free is a method which clears all maps created by this scope at once and
+// releases the chunks of their entries. The maps stay usable as empty maps
+// which no longer belong to this scope, but entries taken from them before
+// must not be used.
+func (sc *Scope[K, V]) Free() {
+	for _, om := range sc.maps {
+		om.m = nil
+		om.peak = 0
+		om.head = nil
+		om.last = nil
+		om.len = 0
+		om.ext.scope = nil
+	}
+	sc.maps = nil
+	sc.chunk = nil
+}
+
+func (sc *Scope[K, V]) newEntry(key K, value V) *Entry[K, V] {
+	if len(sc.chunk) == cap(sc.chunk) {
+		sc.chunk = make([]Entry[K, V], 0, sc.chunkSize)
+	}
+	sc.chunk = append(sc.chunk, Entry[K, V]{key: key, value: value})
+	return &sc.chunk[len(sc.chunk)-1]
+}
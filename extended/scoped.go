@@ -0,0 +1,77 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"strings"
+)
+
+// ScopedMap is a struct which is a view of a string-keyed ordered map in
+// which a prefix is added to keys on writing and stripped from keys on
+// reading. This lets modules share one map without key collisions, while
+// the shared map itself shows fully-qualified keys.
+type ScopedMap[V any] struct {
+	om     *Map[string, V]
+	prefix string
+}
+
+// Scoped is a function which returns a view of the specified map scoped by
+// the specified prefix.
+// (This is a function, not a method, because Go methods cannot be
+// restricted to the string key type.)
+func Scoped[V any](om *Map[string, V], prefix string) ScopedMap[V] {
+	return ScopedMap[V]{om: om, prefix: prefix}
+}
+
+// Prefix is a method which returns the prefix of this view.
+func (sm ScopedMap[V]) Prefix() string {
+	return sm.prefix
+}
+
+// Scoped is a method which returns a nested view whose prefix is the prefix
+// of this view followed by the specified prefix.
+func (sm ScopedMap[V]) Scoped(prefix string) ScopedMap[V] {
+	return ScopedMap[V]{om: sm.om, prefix: sm.prefix + prefix}
+}
+
+// Len is a method which returns the number of entries in this scope.
+// This method iterates all entries of the underlying map.
+func (sm ScopedMap[V]) Len() int {
+	n := 0
+	sm.Range(func(string, V) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Load is a method which returns a value for a key in this scope.
+func (sm ScopedMap[V]) Load(key string) (value V, ok bool) {
+	return sm.om.Load(sm.prefix + key)
+}
+
+// Store is a method which sets a value for a key in this scope.
+func (sm ScopedMap[V]) Store(key string, value V) {
+	sm.om.Store(sm.prefix+key, value)
+}
+
+// Delete is a method which deletes a value for a key in this scope.
+func (sm ScopedMap[V]) Delete(key string) {
+	sm.om.Delete(sm.prefix + key)
+}
+
+// Range is a method which calls the specified function: fn sequentially for
+// each entry in this scope with the prefix stripped from its key, in the
+// order of the underlying map. If fn returns false, this method stops the
+// iteration.
+func (sm ScopedMap[V]) Range(fn func(key string, value V) bool) {
+	sm.om.Range(func(key string, value V) bool {
+		k, found := strings.CutPrefix(key, sm.prefix)
+		if !found {
+			return true
+		}
+		return fn(k, value)
+	})
+}
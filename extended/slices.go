@@ -0,0 +1,47 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// KeysSlice is a method which returns a new slice of keys of this map in the
+// order of key insertions. The slice is allocated once with the length of
+// this map.
+func (om *Map[K, V]) KeysSlice() []K {
+	if om == nil {
+		return nil
+	}
+	keys := make([]K, 0, om.len)
+	for ent := om.head; ent != nil; ent = ent.next {
+		keys = append(keys, ent.key)
+	}
+	return keys
+}
+
+// ValuesSlice is a method which returns a new slice of values of this map in
+// the order of key insertions. The slice is allocated once with the length
+// of this map.
+func (om *Map[K, V]) ValuesSlice() []V {
+	if om == nil {
+		return nil
+	}
+	values := make([]V, 0, om.len)
+	for ent := om.head; ent != nil; ent = ent.next {
+		values = append(values, ent.value)
+	}
+	return values
+}
+
+// Pairs is a method which returns a new slice of pairs of keys and values of
+// this map in the order of key insertions. The slice is allocated once with
+// the length of this map.
+func (om *Map[K, V]) Pairs() []Pair[K, V] {
+	if om == nil {
+		return nil
+	}
+	pairs := make([]Pair[K, V], 0, om.len)
+	for ent := om.head; ent != nil; ent = ent.next {
+		pairs = append(pairs, Pair[K, V]{Key: ent.key, Value: ent.value})
+	}
+	return pairs
+}
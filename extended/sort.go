@@ -0,0 +1,75 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"sort"
+)
+
+// Ordered is a type constraint of key types which are ordered by the
+// operator <.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// SortKeys is a function which reorders the entries of the specified map in
+// place in ascending order of their keys.
+func SortKeys[K Ordered, V any](om *Map[K, V]) {
+	om.SortFunc(func(a, b *Entry[K, V]) bool {
+		return a.key < b.key
+	})
+}
+
+// SortFunc is a method which reorders the entries of this map in place,
+// where less reports whether an entry must be before another. The sort is
+// stable, so entries which are equal keep their order.
+// After sorting, the order of entries is the order of key insertions for all
+// other methods, e.g. the front entry is the first to be evicted by
+// WithMaxLen, and tokens of Page returned before sorting are no longer valid.
+func (om *Map[K, V]) SortFunc(less func(a, b *Entry[K, V]) bool) {
+	if om == nil || om.len < 2 || om.limited() {
+		return
+	}
+	ents := make([]*Entry[K, V], 0, om.len)
+	for ent := om.head; ent != nil; ent = ent.next {
+		ents = append(ents, ent)
+	}
+	sort.SliceStable(ents, func(i, j int) bool {
+		return less(ents[i], ents[j])
+	})
+	om.relink(ents)
+}
+
+// relink is a method which rebuilds the entry list in the order of the
+// specified entries, which must be all entries of this map, and renumbers
+// their sequence numbers in that order.
+func (om *Map[K, V]) relink(ents []*Entry[K, V]) {
+	var prev *Entry[K, V]
+	for _, ent := range ents {
+		ent.prev = prev
+		if prev == nil {
+			om.head = ent
+		} else {
+			prev.next = ent
+		}
+		om.seq++
+		ent.seq = om.seq
+		prev = ent
+	}
+	if prev != nil {
+		prev.next = nil
+	}
+	om.last = prev
+	if om.ext != nil {
+		if om.ext.quota != nil {
+			om.ext.quota.reset(om.head)
+		}
+		for ent := om.head; ent != nil; ent = ent.next {
+			om.movedExt(ent)
+		}
+	}
+}
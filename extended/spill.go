@@ -0,0 +1,211 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// SpillOptions is a struct which holds options of SpillMap.
+//
+// Threshold is the size in bytes above which a value is written to a file
+// instead of being held in memory. The size of a []byte or string value is its
+// length, and the size of other values is the length of their JSON encoding.
+//
+// Dir is the directory in which a temporary directory for the files is
+// created. If Dir is empty, os.TempDir is used.
+type SpillOptions struct {
+	Threshold int
+	Dir       string
+}
+
+// SpillMap is a struct which is an ordered map which spills values larger
+// than a threshold to temporary files and reads them back on Load and Range,
+// so that a few huge values do not have to stay in memory.
+// Values of types other than []byte and string are encoded as JSON.
+// Close must be called to remove the files, and methods return ErrClosed
+// after it. Errors of file operations are returned in KeyError.
+// A SpillMap is not safe for concurrent use.
+type SpillMap[K comparable, V any] struct {
+	m      Map[K, spillValue[V]]
+	dir    string
+	seq    uint64
+	opts   SpillOptions
+	closed bool
+}
+
+type spillValue[V any] struct {
+	value V
+	path  string
+}
+
+// NewSpill is a function which creates a new ordered map which spills large
+// values to files in a new temporary directory.
+func NewSpill[K comparable, V any](opts SpillOptions) (*SpillMap[K, V], error) {
+	dir, err := os.MkdirTemp(opts.Dir, "orderedmap-spill-")
+	if err != nil {
+		return nil, err
+	}
+	return &SpillMap[K, V]{m: New[K, spillValue[V]](), dir: dir, opts: opts}, nil
+}
+
+// Len is a method which returns the number of entries in this map.
+func (sm *SpillMap[K, V]) Len() int {
+	return sm.m.Len()
+}
+
+// Spilled is a method which returns the number of values held in files.
+func (sm *SpillMap[K, V]) Spilled() int {
+	n := 0
+	sm.m.Range(func(_ K, sv spillValue[V]) bool {
+		if sv.path != "" {
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+// Store is a method which sets a value for a key. If the value is larger than
+// the threshold, it is written to a file.
+func (sm *SpillMap[K, V]) Store(key K, value V) error {
+	if sm.closed {
+		return ErrClosed
+	}
+	bs, small, err := sm.encode(value)
+	if err != nil {
+		return err
+	}
+
+	sv := spillValue[V]{value: value}
+	if !small {
+		sm.seq++
+		sv = spillValue[V]{path: filepath.Join(sm.dir, strconv.FormatUint(sm.seq, 10))}
+		if err := os.WriteFile(sv.path, bs, 0o600); err != nil {
+			return &KeyError{Op: "store", Key: key, Err: err}
+		}
+	}
+
+	old, loaded := sm.m.Swap(key, sv)
+	if loaded && old.path != "" {
+		return sm.remove(key, old.path)
+	}
+	return nil
+}
+
+// Load is a method which returns a value stored in this map for a key,
+// reading it from its file if it was spilled.
+// If no value was found for a key, the ok result is false.
+func (sm *SpillMap[K, V]) Load(key K) (value V, ok bool, err error) {
+	if sm.closed {
+		err = ErrClosed
+		return
+	}
+	sv, ok := sm.m.Load(key)
+	if !ok {
+		return
+	}
+	value, err = sm.read(sv)
+	if err != nil {
+		err = &KeyError{Op: "load", Key: key, Err: err}
+	}
+	return
+}
+
+// Delete is a method which deletes a value for a key, and removes its file if
+// it was spilled.
+func (sm *SpillMap[K, V]) Delete(key K) error {
+	if sm.closed {
+		return ErrClosed
+	}
+	sv, loaded := sm.m.LoadAndDelete(key)
+	if loaded && sv.path != "" {
+		return sm.remove(key, sv.path)
+	}
+	return nil
+}
+
+func (sm *SpillMap[K, V]) remove(key K, path string) error {
+	if err := os.Remove(path); err != nil {
+		return &KeyError{Op: "delete", Key: key, Err: err}
+	}
+	return nil
+}
+
+// Range is a method which calls the specified function: fn sequentially for
+// each key and value in this map, reading spilled values from their files.
+// If fn returns false, this method stops the iteration.
+func (sm *SpillMap[K, V]) Range(fn func(key K, value V) bool) error {
+	if sm.closed {
+		return ErrClosed
+	}
+	var err error
+	sm.m.Range(func(key K, sv spillValue[V]) bool {
+		var value V
+		value, err = sm.read(sv)
+		if err != nil {
+			err = &KeyError{Op: "load", Key: key, Err: err}
+			return false
+		}
+		return fn(key, value)
+	})
+	return err
+}
+
+// Close is a method which removes all entries and the temporary directory of
+// this map. Calling Close more than once does nothing.
+func (sm *SpillMap[K, V]) Close() error {
+	if sm.closed {
+		return nil
+	}
+	sm.closed = true
+	sm.m.Clear()
+	return os.RemoveAll(sm.dir)
+}
+
+// encode is a method which returns the bytes written to a file for a value,
+// and whether the value is small enough to be held in memory.
+func (sm *SpillMap[K, V]) encode(value V) (bs []byte, small bool, err error) {
+	switch v := any(value).(type) {
+	case []byte:
+		if len(v) <= sm.opts.Threshold {
+			return nil, true, nil
+		}
+		return v, false, nil
+	case string:
+		if len(v) <= sm.opts.Threshold {
+			return nil, true, nil
+		}
+		return []byte(v), false, nil
+	default:
+		bs, err = json.Marshal(value)
+		if err != nil {
+			return nil, false, err
+		}
+		return bs, len(bs) <= sm.opts.Threshold, nil
+	}
+}
+
+func (sm *SpillMap[K, V]) read(sv spillValue[V]) (value V, err error) {
+	if sv.path == "" {
+		return sv.value, nil
+	}
+	bs, err := os.ReadFile(sv.path)
+	if err != nil {
+		return
+	}
+	switch p := any(&value).(type) {
+	case *[]byte:
+		*p = bs
+	case *string:
+		*p = string(bs)
+	default:
+		err = json.Unmarshal(bs, &value)
+	}
+	return
+}
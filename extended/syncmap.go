@@ -0,0 +1,335 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SyncMap is a struct which is an ordered map safe for concurrent use by
+// multiple goroutines, like sync.Map. It guards a Map with a read-write
+// mutex, so reads run in parallel and writes are serialized.
+//
+// Range and the iteration methods take a snapshot of the entries and call
+// the function without the lock, so the function can read and write this map.
+// Entries are returned by value instead of *Entry, because an entry may be
+// changed by other goroutines.
+type SyncMap[K comparable, V any] struct {
+	mu sync.RWMutex
+	om Map[K, V]
+
+	reclaimed uint64
+	closed    bool
+	done      chan struct{}
+	janitors  sync.WaitGroup
+
+	flights map[K]*flightCall[V]
+}
+
+// flightCall is a struct which holds a fetch by the loader in flight and its
+// result, which are shared by concurrent loads of the same key.
+type flightCall[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	ok    bool
+}
+
+// NewSync is a function which creates a new empty SyncMap. Options are same
+// with New; writers and eviction callbacks are called while the map is
+// locked, so they must not call methods of this map. Loaders are called by
+// Load without the lock.
+func NewSync[K comparable, V any](opts ...Option) *SyncMap[K, V] {
+	return &SyncMap[K, V]{om: New[K, V](opts...)}
+}
+
+// Len is a method which returns the number of entries in this map.
+func (sm *SyncMap[K, V]) Len() int {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.om.Len()
+}
+
+// Store is a method which sets a value for a key.
+func (sm *SyncMap[K, V]) Store(key K, value V) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.om.Store(key, value)
+}
+
+// StoreCtx is a method which sets a value for a key like Map#StoreCtx.
+func (sm *SyncMap[K, V]) StoreCtx(ctx context.Context, key K, value V) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.StoreCtx(ctx, key, value)
+}
+
+// DeleteCtx is a method which deletes a value for a key like Map#DeleteCtx.
+func (sm *SyncMap[K, V]) DeleteCtx(ctx context.Context, key K) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.DeleteCtx(ctx, key)
+}
+
+// Swap is a method which sets a value for a key, and returns the previous
+// value and whether the key was present.
+func (sm *SyncMap[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.Swap(key, value)
+}
+
+// Load is a method which returns a value stored in this map for a key.
+// If a loader is set and the key is not found, the value is fetched without
+// the lock, so other goroutines can use this map during the fetch. If a value
+// for the key is stored during the fetch, the stored value is returned and
+// the fetched value is discarded.
+// Concurrent loads of the same key wait for one fetch and share its result,
+// so that a cold key does not hit the external source many times.
+// If this map is created with WithAccessOrder, this map is locked for
+// writing to look up the key.
+func (sm *SyncMap[K, V]) Load(key K) (value V, ok bool) {
+	if !sm.accessOrdered() {
+		sm.mu.RLock()
+		value, ok = sm.om.m[key].valueOf()
+		hasLoader := sm.om.ext != nil && sm.om.ext.loader != nil
+		sm.mu.RUnlock()
+		if ok || !hasLoader {
+			return
+		}
+	}
+
+	sm.mu.Lock()
+	if value, ok = sm.om.loadStored(key); ok || sm.om.ext == nil || sm.om.ext.loader == nil {
+		sm.mu.Unlock()
+		return
+	}
+	if c, exists := sm.flights[key]; exists {
+		sm.mu.Unlock()
+		c.wg.Wait()
+		return c.value, c.ok
+	}
+	c := new(flightCall[V])
+	c.wg.Add(1)
+	if sm.flights == nil {
+		sm.flights = make(map[K]*flightCall[V])
+	}
+	sm.flights[key] = c
+	loader := sm.om.ext.loader
+	sm.mu.Unlock()
+
+	var fetched V
+	fetchedOk := false
+	defer func() {
+		sm.mu.Lock()
+		delete(sm.flights, key)
+		if fetchedOk {
+			if value, ok = sm.om.loadStored(key); !ok {
+				value = sm.om.intern(fetched)
+				sm.om.store(key, value)
+				ok = true
+			}
+		}
+		c.value, c.ok = value, ok
+		sm.mu.Unlock()
+		c.wg.Done()
+	}()
+
+	fetched, err := loader(context.Background(), key)
+	fetchedOk = err == nil
+	return
+}
+
+// LoadOrStore is a method which returns a value for a key if present,
+// otherwise stores and returns the given value.
+func (sm *SyncMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	if !sm.accessOrdered() {
+		sm.mu.RLock()
+		actual, loaded = sm.om.m[key].valueOf()
+		sm.mu.RUnlock()
+		if loaded {
+			return
+		}
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.LoadOrStore(key, value)
+}
+
+// Delete is a method which deletes a value for a key.
+func (sm *SyncMap[K, V]) Delete(key K) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.om.Delete(key)
+}
+
+// LoadAndDelete is a method which deletes a value for a key, and returns the
+// previous value if any.
+func (sm *SyncMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.LoadAndDelete(key)
+}
+
+// PopFront is a method which deletes the first entry and returns its key and
+// value atomically, like PopFront of Map.
+func (sm *SyncMap[K, V]) PopFront() (key K, value V, ok bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.PopFront()
+}
+
+// PopBack is a method which deletes the last entry and returns its key and
+// value atomically, like PopBack of Map.
+func (sm *SyncMap[K, V]) PopBack() (key K, value V, ok bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.PopBack()
+}
+
+// Pop is a method which deletes the entry for a key and returns its key and
+// value atomically, like Pop of Map.
+func (sm *SyncMap[K, V]) Pop(key K) (k K, value V, ok bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.Pop(key)
+}
+
+// CompareAndSwap is a method which swaps the value for a key if the stored
+// value is equal to old. The value type must be comparable, otherwise this
+// method panics like sync.Map.
+// The swapped flag is false if the swap is rejected by the rate limit set by
+// WithRateLimit.
+func (sm *SyncMap[K, V]) CompareAndSwap(key K, old, new V) (swapped bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	cur, ok := sm.om.m[key].valueOf()
+	if !ok || any(cur) != any(old) || sm.om.limited() {
+		return false
+	}
+	sm.om.put(key, new)
+	return true
+}
+
+// CompareAndDelete is a method which deletes the entry for a key if its value
+// is equal to old. The value type must be comparable, otherwise this method
+// panics like sync.Map.
+// The deleted flag is false if the deletion is rejected by the rate limit set
+// by WithRateLimit.
+func (sm *SyncMap[K, V]) CompareAndDelete(key K, old V) (deleted bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	cur, ok := sm.om.m[key].valueOf()
+	if !ok || any(cur) != any(old) || sm.om.limited() {
+		return false
+	}
+	sm.om.remove(key)
+	return true
+}
+
+// Clear is a method which deletes all entries.
+func (sm *SyncMap[K, V]) Clear() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.om.Clear()
+}
+
+// Range is a method which calls fn sequentially for each key and value of a
+// snapshot of this map taken at the call, in the order of key insertions.
+// If fn returns false, this method stops the iteration.
+func (sm *SyncMap[K, V]) Range(fn func(key K, value V) bool) {
+	for _, p := range sm.Snapshot() {
+		if !fn(p.Key, p.Value) {
+			break
+		}
+	}
+}
+
+// Snapshot is a method which returns the keys and values of this map in the
+// order of key insertions.
+func (sm *SyncMap[K, V]) Snapshot() []Pair[K, V] {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	pairs := make([]Pair[K, V], 0, sm.om.Len())
+	for ent := sm.om.Front(); ent != nil; ent = ent.next {
+		pairs = append(pairs, Pair[K, V]{Key: ent.key, Value: ent.value})
+	}
+	return pairs
+}
+
+// MarshalJSON is a method which returns the JSON of this map.
+func (sm *SyncMap[K, V]) MarshalJSON() ([]byte, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.om.MarshalJSON()
+}
+
+// UnmarshalJSON is a method which sets the content of this map from a JSON.
+func (sm *SyncMap[K, V]) UnmarshalJSON(data []byte) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.UnmarshalJSON(data)
+}
+
+// GobEncode is a method which encodes this map for encoding/gob, like
+// GobEncode of Map.
+func (sm *SyncMap[K, V]) GobEncode() ([]byte, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.om.GobEncode()
+}
+
+// GobDecode is a method which sets the content of this map from data encoded
+// by GobEncode.
+func (sm *SyncMap[K, V]) GobDecode(data []byte) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.GobDecode(data)
+}
+
+// String is a method which returns a string of this map's content.
+func (sm *SyncMap[K, V]) String() string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return "Sync" + sm.om.String()
+}
+
+// accessOrdered is a method which reports whether the underlying map is in
+// access order, where a load moves an entry and so needs the write lock.
+func (sm *SyncMap[K, V]) accessOrdered() bool {
+	return sm.om.ext != nil && sm.om.ext.accessOrder
+}
+
+// valueOf is a method which returns the value of an entry of the hash index
+// and whether it is live, without any side effect, so that it can be called
+// under a read lock.
+func (ent *Entry[K, V]) valueOf() (value V, ok bool) {
+	if ent == nil || ent.deleted || ent.expired() {
+		return
+	}
+	return ent.value, true
+}
+
+// StoreWithTTL is a method which sets a value for a key, and makes the entry
+// expire when ttl has passed, like Map#StoreWithTTL.
+// An expired entry is not returned by loads of this map even before it is
+// removed.
+func (sm *SyncMap[K, V]) StoreWithTTL(key K, value V, ttl time.Duration) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.om.StoreWithTTL(key, value, ttl)
+}
+
+// PurgeExpired is a method which removes all expired entries, and returns the
+// number of removed entries.
+func (sm *SyncMap[K, V]) PurgeExpired() int {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	n := sm.om.PurgeExpired()
+	sm.reclaimed += uint64(n)
+	return n
+}
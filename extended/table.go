@@ -0,0 +1,110 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// TableOptions is a struct which specifies how WriteTable renders a map.
+type TableOptions struct {
+	// Markdown renders the table as a Markdown table instead of plain text.
+	Markdown bool
+
+	// NoHeader omits the header row.
+	NoHeader bool
+
+	// KeyHeader and ValueHeader are the headers of the columns.
+	// If they are empty, "KEY" and "VALUE" are used.
+	KeyHeader   string
+	ValueHeader string
+
+	// Format returns the text of a key or a value. If it is nil, fmt.Sprint
+	// is used.
+	Format func(v any) string
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "|", `\|`, "`", "\\`", "*", `\*`, "_", `\_`,
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`,
+)
+
+// WriteTable is a method which writes keys and values of this map to the
+// specified writer as a table of two columns, in the order of key
+// insertions.
+// In plain text, the columns are aligned with spaces. In Markdown, the pipes
+// and the characters of inline markups in keys and values are escaped with
+// backslashes. In both, line breaks are replaced with spaces.
+func (om *Map[K, V]) WriteTable(w io.Writer, opts TableOptions) error {
+	cell := func(v any) string {
+		s := strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(opts.format(v))
+		if opts.Markdown {
+			s = markdownEscaper.Replace(s)
+		}
+		return s
+	}
+
+	rows := make([][2]string, 0, om.Len()+1)
+	if !opts.NoHeader {
+		kh, vh := opts.headers()
+		rows = append(rows, [2]string{kh, vh})
+	}
+	om.Range(func(key K, value V) bool {
+		rows = append(rows, [2]string{cell(key), cell(value)})
+		return true
+	})
+
+	width := 0
+	for _, row := range rows {
+		if n := utf8.RuneCountInString(row[0]); n > width {
+			width = n
+		}
+	}
+	if opts.Markdown && width < 3 {
+		width = 3
+	}
+
+	bw := bufio.NewWriter(w)
+	pad := func(s string) string {
+		return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+	}
+	for i, row := range rows {
+		if opts.Markdown {
+			fmt.Fprintf(bw, "| %s | %s |\n", pad(row[0]), row[1])
+			if i == 0 && !opts.NoHeader {
+				fmt.Fprintf(bw, "| %s | --- |\n", strings.Repeat("-", width))
+			}
+			continue
+		}
+		if row[1] == "" {
+			fmt.Fprintln(bw, row[0])
+		} else {
+			fmt.Fprintf(bw, "%s  %s\n", pad(row[0]), row[1])
+		}
+	}
+	return bw.Flush()
+}
+
+func (opts TableOptions) format(v any) string {
+	if opts.Format == nil {
+		return fmt.Sprint(v)
+	}
+	return opts.Format(v)
+}
+
+func (opts TableOptions) headers() (string, string) {
+	kh, vh := opts.KeyHeader, opts.ValueHeader
+	if kh == "" {
+		kh = "KEY"
+	}
+	if vh == "" {
+		vh = "VALUE"
+	}
+	return kh, vh
+}
@@ -0,0 +1,217 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// TieredOptions is a struct which holds options of TieredMap.
+//
+// HotLen is the maximum number of the most recently inserted entries kept in
+// memory. When it is exceeded, the oldest hot entry is demoted to the backend.
+// If HotLen is zero or less, 1 is used.
+//
+// PromotedLen is the maximum number of cold entries cached in memory after
+// they were loaded from the backend. The cache is evicted in the order of
+// promotions. If PromotedLen is zero or less, cold entries are not cached.
+type TieredOptions struct {
+	HotLen      int
+	PromotedLen int
+}
+
+// TieredStats is a struct which holds counters of a TieredMap.
+type TieredStats struct {
+	// Demotions is the number of entries moved from memory to the backend.
+	Demotions uint64
+	// Promotions is the number of cold entries cached in memory by Load.
+	Promotions uint64
+	// HotHits is the number of Loads served from memory.
+	HotHits uint64
+	// ColdHits is the number of Loads served from the backend.
+	ColdHits uint64
+	// Misses is the number of Loads of absent keys.
+	Misses uint64
+}
+
+// TieredMap is a struct which is an ordered map keeping the most recently
+// inserted entries in memory and older entries in a Backend.
+// The order of entries is the order of key insertions through both tiers:
+// entries in the backend are always older than entries in memory.
+// A TieredMap is not safe for concurrent use.
+type TieredMap[K comparable, V any] struct {
+	hot      Map[K, V]
+	promoted Map[K, V]
+	cold     Backend[K, V]
+	opts     TieredOptions
+	stats    TieredStats
+}
+
+// NewTiered is a function which creates a new ordered map which demotes old
+// entries to the specified backend. If the backend is nil, a MemoryBackend
+// is used.
+func NewTiered[K comparable, V any](
+	backend Backend[K, V],
+	opts TieredOptions,
+) *TieredMap[K, V] {
+	if backend == nil {
+		backend = NewMemoryBackend[K, V]()
+	}
+	if opts.HotLen <= 0 {
+		opts.HotLen = 1
+	}
+	return &TieredMap[K, V]{
+		hot:      New[K, V](),
+		promoted: New[K, V](),
+		cold:     backend,
+		opts:     opts,
+	}
+}
+
+// Stats is a method which returns the counters of this map.
+func (tm *TieredMap[K, V]) Stats() TieredStats {
+	return tm.stats
+}
+
+// Len is a method which returns the number of entries in this map.
+func (tm *TieredMap[K, V]) Len() (int, error) {
+	n, err := tm.cold.Len()
+	return n + tm.hot.Len(), err
+}
+
+// Store is a method which sets a value for a key. A new key is stored in
+// memory, and the oldest entry in memory is demoted if there are too many.
+func (tm *TieredMap[K, V]) Store(key K, value V) error {
+	if _, ok := tm.hot.Load(key); ok {
+		tm.hot.Store(key, value)
+		return nil
+	}
+
+	_, ok, err := tm.cold.Get(key)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if _, cached := tm.promoted.Load(key); cached {
+			tm.promoted.Store(key, value)
+		}
+		return tm.cold.Put(key, value)
+	}
+
+	tm.hot.Store(key, value)
+	for tm.hot.Len() > tm.opts.HotLen {
+		ent := tm.hot.Front()
+		err = tm.cold.Put(ent.key, ent.value)
+		if err != nil {
+			return err
+		}
+		tm.hot.Delete(ent.key)
+		tm.stats.Demotions++
+	}
+	return nil
+}
+
+// Load is a method which returns a value stored in this map for a key.
+// If no value was found for a key, the ok result is false.
+func (tm *TieredMap[K, V]) Load(key K) (value V, ok bool, err error) {
+	value, ok = tm.hot.Load(key)
+	if !ok {
+		value, ok = tm.promoted.Load(key)
+	}
+	if ok {
+		tm.stats.HotHits++
+		return
+	}
+
+	value, ok, err = tm.cold.Get(key)
+	if err != nil {
+		return
+	}
+	if !ok {
+		tm.stats.Misses++
+		return
+	}
+	tm.stats.ColdHits++
+
+	tm.promote(key, value)
+	return
+}
+
+// LoadMany is a method which returns values stored in this map for keys.
+// Keys which are not in memory are got from the backend in one batch if it
+// is a BatchGetter.
+// The values are in the same order as the keys, and the value for a key which
+// was not found is the zero value. The missing result has the keys which were
+// not found, in the order of the keys.
+func (tm *TieredMap[K, V]) LoadMany(keys []K) (values []V, missing []K, err error) {
+	values = make([]V, len(keys))
+	var coldKeys []K
+	var coldIndexes []int
+	for i, key := range keys {
+		v, ok := tm.hot.Load(key)
+		if !ok {
+			v, ok = tm.promoted.Load(key)
+		}
+		if ok {
+			tm.stats.HotHits++
+			values[i] = v
+			continue
+		}
+		coldKeys = append(coldKeys, key)
+		coldIndexes = append(coldIndexes, i)
+	}
+	if len(coldKeys) == 0 {
+		return
+	}
+
+	coldValues, found, err := getMany(tm.cold, coldKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+	for j, key := range coldKeys {
+		if !found[j] {
+			tm.stats.Misses++
+			missing = append(missing, key)
+			continue
+		}
+		tm.stats.ColdHits++
+		values[coldIndexes[j]] = coldValues[j]
+		tm.promote(key, coldValues[j])
+	}
+	return
+}
+
+func (tm *TieredMap[K, V]) promote(key K, value V) {
+	if tm.opts.PromotedLen <= 0 {
+		return
+	}
+	tm.promoted.Store(key, value)
+	tm.stats.Promotions++
+	if tm.promoted.Len() > tm.opts.PromotedLen {
+		tm.promoted.FrontAndDelete()
+	}
+}
+
+// Delete is a method which deletes a value for a key.
+func (tm *TieredMap[K, V]) Delete(key K) error {
+	if _, ok := tm.hot.LoadAndDelete(key); ok {
+		return nil
+	}
+	tm.promoted.Delete(key)
+	return tm.cold.Delete(key)
+}
+
+// Range is a method which calls the specified function: fn sequentially for
+// each key and value in this map, from the entries in the backend to the
+// entries in memory.
+// If fn returns false, this method stops the iteration.
+func (tm *TieredMap[K, V]) Range(fn func(key K, value V) bool) error {
+	stopped := false
+	err := tm.cold.Iterate(func(key K, value V) bool {
+		stopped = !fn(key, value)
+		return !stopped
+	})
+	if err != nil || stopped {
+		return err
+	}
+	tm.hot.Range(fn)
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"time"
+)
+
+// entryTimes is a struct which holds the times recorded for an entry of a
+// map created with WithTimestamps, and the expiration time of an entry
+// stored by StoreWithTTL.
+type entryTimes struct {
+	inserted time.Time
+	updated  time.Time
+	expires  time.Time
+}
+
+// InsertedAt is a method which returns the time when this entry was inserted
+// into the map. If the map is not created with WithTimestamps, this method
+// returns the zero time.
+func (ent *Entry[K, V]) InsertedAt() time.Time {
+	if ent.times == nil {
+		return time.Time{}
+	}
+	return ent.times.inserted
+}
+
+// UpdatedAt is a method which returns the time when the value of this entry
+// was last stored, which is same with InsertedAt if the value has never been
+// replaced. If the map is not created with WithTimestamps, this method
+// returns the zero time.
+func (ent *Entry[K, V]) UpdatedAt() time.Time {
+	if ent.times == nil {
+		return time.Time{}
+	}
+	return ent.times.updated
+}
+
+// DeleteInsertedBefore is a method which deletes entries inserted before the
+// specified time, and returns the number of deleted entries.
+// All entries are checked, because entries can be moved out of the order of
+// their insertion, e.g. by MoveToBack, SortFunc or WithLRU. If this map is not
+// created with WithTimestamps, this method deletes nothing.
+func (om *Map[K, V]) DeleteInsertedBefore(t time.Time) int {
+	if om == nil || om.limited() {
+		return 0
+	}
+	n := 0
+	for ent := om.head; ent != nil; {
+		next := ent.next
+		if ent.times != nil && !ent.times.inserted.IsZero() && ent.times.inserted.Before(t) {
+			om.remove(ent.key)
+			n++
+		}
+		ent = next
+	}
+	return n
+}
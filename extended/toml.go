@@ -0,0 +1,300 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlTable is an interface which every instantiation of Map implements, to
+// write nested maps as TOML tables.
+type tomlTable interface {
+	writeTOML(buf *bytes.Buffer, path []string) error
+	writeTOMLInline(buf *bytes.Buffer) error
+}
+
+// MarshalTOML is a method which encodes this map as a TOML inline table whose
+// keys are in the order of key insertions, for github.com/BurntSushi/toml.
+// This enables a map to be a value of a struct field or of an array.
+// Keys are the texts of the keys in the same form as MarshalJSON, and entries
+// whose values are nil are omitted because TOML has no null.
+// To write a map as a whole TOML document, use EncodeTOML.
+func (om Map[K, V]) MarshalTOML() ([]byte, error) {
+	var buf bytes.Buffer
+	err := om.writeTOMLInline(&buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalTOML is a method which sets the content of this map from a TOML
+// table decoded by github.com/BurntSushi/toml.
+// Because the decoder passes a table as a Go map, the entries are stored in
+// the order of their keys. To keep the order of a TOML document, use
+// DecodeTOML.
+func (om *Map[K, V]) UnmarshalTOML(data any) error {
+	tbl, ok := data.(map[string]any)
+	if !ok {
+		return fmt.Errorf("orderedmap: cannot unmarshal TOML %T into a map", data)
+	}
+	names := make([]string, 0, len(tbl))
+	for name := range tbl {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nested := om.ext != nil && om.ext.nestedOrder
+	for _, name := range names {
+		key, err := parseKey[K](name, 0)
+		if err != nil {
+			return err
+		}
+		var val V
+		if p, ok := any(&val).(*any); ok && nested {
+			*p = orderTOML(tbl[name], nil, nil)
+		} else if v, ok := tbl[name].(V); ok {
+			val = v
+		} else if err := convertTOML(tbl[name], &val); err != nil {
+			return err
+		}
+		om.put(key, val)
+	}
+	return nil
+}
+
+// EncodeTOML is a method which writes this map to the specified writer as a
+// TOML document whose keys are in the order of key insertions.
+// Entries whose values are maps are written as tables after the other
+// entries of the same table, because TOML requires it.
+func (om Map[K, V]) EncodeTOML(w io.Writer) error {
+	var buf bytes.Buffer
+	if err := om.writeTOML(&buf, nil); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// DecodeTOML is a method which reads a TOML document from the specified
+// reader and stores its top-level entries into this map in the order of the
+// document.
+// If the map is created with WithNestedOrder and its value type is any,
+// nested tables are decoded into *Map[string, any] in the order of the
+// document, too.
+func (om *Map[K, V]) DecodeTOML(r io.Reader) error {
+	var raw map[string]toml.Primitive
+	md, err := toml.NewDecoder(r).Decode(&raw)
+	if err != nil {
+		return err
+	}
+	order := tomlKeyOrder(md.Keys())
+
+	nested := om.ext != nil && om.ext.nestedOrder
+	for _, name := range order[""] {
+		key, err := parseKey[K](name, 0)
+		if err != nil {
+			return err
+		}
+		var val V
+		if p, ok := any(&val).(*any); ok && nested {
+			var v any
+			if err := md.PrimitiveDecode(raw[name], &v); err != nil {
+				return err
+			}
+			*p = orderTOML(v, []string{name}, order)
+		} else if err := md.PrimitiveDecode(raw[name], &val); err != nil {
+			return err
+		}
+		om.put(key, val)
+	}
+	return nil
+}
+
+func (om Map[K, V]) writeTOML(buf *bytes.Buffer, path []string) error {
+	var tables []*Entry[K, V]
+	for ent := om.Front(); ent != nil; ent = ent.Next() {
+		if isNilTOML(ent.value) {
+			continue
+		}
+		if _, ok := any(ent.value).(tomlTable); ok {
+			tables = append(tables, ent)
+			continue
+		}
+		name, err := tomlKeyText(ent.key)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(name)
+		buf.WriteString(" = ")
+		if err := writeTOMLValue(buf, ent.value); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+	}
+
+	for _, ent := range tables {
+		name, err := tomlKeyText(ent.key)
+		if err != nil {
+			return err
+		}
+		p := append(path[:len(path):len(path)], name)
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("[" + strings.Join(p, ".") + "]\n")
+		if err := any(ent.value).(tomlTable).writeTOML(buf, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (om Map[K, V]) writeTOMLInline(buf *bytes.Buffer) error {
+	buf.WriteByte('{')
+	first := true
+	for ent := om.Front(); ent != nil; ent = ent.Next() {
+		if isNilTOML(ent.value) {
+			continue
+		}
+		if !first {
+			buf.WriteString(", ")
+		}
+		first = false
+		name, err := tomlKeyText(ent.key)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(name)
+		buf.WriteString(" = ")
+		if err := writeTOMLValue(buf, ent.value); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// writeTOMLValue is a function which writes a value in TOML with the encoder
+// of github.com/BurntSushi/toml, which calls MarshalTOML of nested maps.
+func writeTOMLValue(buf *bytes.Buffer, value any) error {
+	if t, ok := value.(tomlTable); ok {
+		return t.writeTOMLInline(buf)
+	}
+	var b bytes.Buffer
+	if err := toml.NewEncoder(&b).Encode(map[string]any{"v": value}); err != nil {
+		return err
+	}
+	s := b.String()
+	if !strings.HasPrefix(s, "v = ") {
+		return fmt.Errorf("orderedmap: cannot marshal %T as a TOML value", value)
+	}
+	buf.WriteString(strings.TrimSuffix(s[len("v = "):], "\n"))
+	return nil
+}
+
+func tomlKeyText(key any) (string, error) {
+	var buf bytes.Buffer
+	if err := addKeyText(&buf, key); err != nil {
+		return "", err
+	}
+	name := buf.String()
+	if name != "" && strings.Trim(name,
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789_-") == "" {
+		return name, nil
+	}
+	buf.Reset()
+	err := writeTOMLValue(&buf, name)
+	return buf.String(), err
+}
+
+func isNilTOML(value any) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// tomlKeyOrder is a function which returns the names of child keys of every
+// table in the order of a TOML document. The key of the map is the path of a
+// table joined with ".", and the root table is "".
+func tomlKeyOrder(keys []toml.Key) map[string][]string {
+	order := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, k := range keys {
+		parent := strings.Join(k[:len(k)-1], ".")
+		full := k.String()
+		if seen[full] {
+			continue
+		}
+		seen[full] = true
+		order[parent] = append(order[parent], k[len(k)-1])
+	}
+	return order
+}
+
+// orderTOML is a function which converts tables in a decoded TOML value into
+// *Map[string, any] whose keys are in the order of a TOML document, or in the
+// order of the keys if the order is not known.
+func orderTOML(v any, path []string, order map[string][]string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		names := order[strings.Join(path, ".")]
+		if len(names) == 0 {
+			for name := range t {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		}
+		om := New[string, any](WithNestedOrder())
+		for _, name := range names {
+			if cv, ok := t[name]; ok {
+				om.Store(name, orderTOML(cv, append(path[:len(path):len(path)], name), order))
+			}
+		}
+		return &om
+	case []map[string]any:
+		arr := make([]any, len(t))
+		for i, e := range t {
+			arr[i] = orderTOML(e, path, order)
+		}
+		return arr
+	case []any:
+		for i, e := range t {
+			t[i] = orderTOML(e, path, order)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// convertTOML is a function which converts a decoded TOML value into the type
+// of the value which dst points to, by encoding and decoding it again.
+func convertTOML(v any, dst any) error {
+	var b bytes.Buffer
+	if err := toml.NewEncoder(&b).Encode(map[string]any{"v": v}); err != nil {
+		return err
+	}
+	rv := reflect.New(reflect.StructOf([]reflect.StructField{{
+		Name: "V",
+		Type: reflect.TypeOf(dst).Elem(),
+		Tag:  `toml:"v"`,
+	}}))
+	if _, err := toml.NewDecoder(&b).Decode(rv.Interface()); err != nil {
+		return err
+	}
+	reflect.ValueOf(dst).Elem().Set(rv.Elem().Field(0))
+	return nil
+}
@@ -0,0 +1,90 @@
+package extended_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+const tomlDoc = `title = "x"
+version = 2
+"a key" = true
+
+[server]
+port = 8080
+host = "localhost"
+
+[server.tls]
+cert = "c.pem"
+
+[db]
+pools = [{size = 1, name = "r"}, {size = 2, name = "w"}]
+`
+
+func TestMap_DecodeTOML(t *testing.T) {
+	om := orderedmap.New[string, any](orderedmap.WithNestedOrder())
+	if err := om.DecodeTOML(strings.NewReader(tomlDoc)); err != nil {
+		t.Fatal(err)
+	}
+	want := "Map[title:x version:2 a key:true " +
+		"server:Map[port:8080 host:localhost tls:Map[cert:c.pem]] " +
+		"db:Map[pools:[Map[size:1 name:r] Map[size:2 name:w]]]]"
+	if om.String() != want {
+		t.Errorf("om = %v", om)
+	}
+
+	var buf bytes.Buffer
+	if err := om.EncodeTOML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != tomlDoc {
+		t.Errorf("EncodeTOML = %s", buf.String())
+	}
+
+	ports := orderedmap.New[string, int]()
+	if err := ports.DecodeTOML(strings.NewReader("web = 80\nadmin = 8080\n")); err != nil {
+		t.Fatal(err)
+	}
+	if ports.String() != "Map[web:80 admin:8080]" {
+		t.Errorf("ports = %v", ports)
+	}
+}
+
+func TestMap_MarshalTOML(t *testing.T) {
+	type Config struct {
+		Name    string
+		Headers orderedmap.Map[string, string]
+	}
+	cfg := Config{Name: "x", Headers: orderedmap.New[string, string]()}
+	cfg.Headers.Store("X-B", "2")
+	cfg.Headers.Store("X-A", "1")
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := "Name = \"x\"\nHeaders = {X-B = \"2\", X-A = \"1\"}\n"
+	if buf.String() != want {
+		t.Errorf("toml.Encode = %s", buf.String())
+	}
+
+	var got Config
+	if _, err := toml.Decode(buf.String(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Headers.String() != "Map[X-A:1 X-B:2]" {
+		t.Errorf("Headers = %v", got.Headers)
+	}
+
+	var limits struct{ Limits orderedmap.Map[string, int] }
+	if _, err := toml.Decode("[Limits]\nb = 2\na = 1\n", &limits); err != nil {
+		t.Fatal(err)
+	}
+	if limits.Limits.String() != "Map[a:1 b:2]" {
+		t.Errorf("Limits = %v", limits.Limits)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// TopN is a method which returns up to n largest entries of this map in
+// descending order, where less reports whether an entry is smaller than
+// another. Entries which are equal keep the order of key insertions.
+//
+// This method walks entries once with a heap bounded to n entries, so it
+// costs O(len log n) time and O(n) memory, without copying and sorting all
+// entries.
+func (om *Map[K, V]) TopN(n int, less func(a, b *Entry[K, V]) bool) []*Entry[K, V] {
+	if om == nil || n <= 0 {
+		return nil
+	}
+	if om.len < n {
+		n = om.len
+	}
+
+	// h is a min-heap on less, so h[0] is the smallest of the kept entries.
+	// An entry inserted later is treated as smaller than an equal one, so
+	// that the earlier one is kept and ranked first.
+	type ranked struct {
+		ent *Entry[K, V]
+		seq int
+	}
+	h := make([]ranked, 0, n)
+	lower := func(i, j int) bool {
+		if less(h[i].ent, h[j].ent) {
+			return true
+		}
+		if less(h[j].ent, h[i].ent) {
+			return false
+		}
+		return h[i].seq > h[j].seq
+	}
+	down := func(i, size int) {
+		for {
+			c := 2*i + 1
+			if c >= size {
+				return
+			}
+			if c+1 < size && lower(c+1, c) {
+				c++
+			}
+			if !lower(c, i) {
+				return
+			}
+			h[i], h[c] = h[c], h[i]
+			i = c
+		}
+	}
+
+	seq := 0
+	for ent := om.Front(); ent != nil; ent = ent.next {
+		seq++
+		if len(h) < n {
+			h = append(h, ranked{ent: ent, seq: seq})
+			for i := len(h) - 1; i > 0; {
+				p := (i - 1) / 2
+				if !lower(i, p) {
+					break
+				}
+				h[i], h[p] = h[p], h[i]
+				i = p
+			}
+			continue
+		}
+		if less(h[0].ent, ent) {
+			h[0] = ranked{ent: ent, seq: seq}
+			down(0, n)
+		}
+	}
+
+	// Heap sort in place: moving the smallest to the end each time leaves
+	// the entries in descending order.
+	for size := len(h) - 1; size > 0; size-- {
+		h[0], h[size] = h[size], h[0]
+		down(0, size)
+	}
+
+	top := make([]*Entry[K, V], len(h))
+	for i, r := range h {
+		top[i] = r.ent
+	}
+	return top
+}
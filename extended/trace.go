@@ -0,0 +1,195 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// TraceOp is a type which represents an operation recorded by the trace mode
+// set by WithTrace.
+type TraceOp int
+
+const (
+	// TraceInsert means that an entry was inserted.
+	TraceInsert TraceOp = iota + 1
+
+	// TraceUpdate means that the value of an entry was replaced.
+	TraceUpdate
+
+	// TraceDelete means that an entry was deleted.
+	TraceDelete
+
+	// TraceLdelete means that an entry was logically deleted.
+	TraceLdelete
+
+	// TraceEvict means that an entry was evicted by WithMaxLen or
+	// WithMaxWeight.
+	TraceEvict
+
+	// TraceClear means that an entry was removed by Clear.
+	TraceClear
+
+	// TraceMove means that an entry was moved in the order of entries, e.g.
+	// by MoveToBack, SortFunc or an access with WithLRU.
+	TraceMove
+)
+
+func (op TraceOp) String() string {
+	switch op {
+	case TraceInsert:
+		return "Insert"
+	case TraceUpdate:
+		return "Update"
+	case TraceDelete:
+		return "Delete"
+	case TraceLdelete:
+		return "Ldelete"
+	case TraceEvict:
+		return "Evict"
+	case TraceClear:
+		return "Clear"
+	case TraceMove:
+		return "Move"
+	default:
+		return "Unknown"
+	}
+}
+
+// TraceRecord is a struct which holds an operation recorded by the trace
+// mode.
+// Seq is the sequence number of the operation in the map, starting from 1.
+// Caller is a short stack of the code which called the operation, which
+// consists of up to three frames outside this package, e.g.
+// "main.load (config.go:42) < main.main (main.go:10)".
+type TraceRecord[K comparable] struct {
+	Seq    uint64
+	Op     TraceOp
+	Key    K
+	Caller string
+}
+
+// traceDepth is the maximum number of frames of TraceRecord#Caller.
+const traceDepth = 3
+
+var tracePkgPrefix = reflect.TypeOf(TraceOp(0)).PkgPath() + "."
+
+type tracer[K comparable] struct {
+	seq     uint64
+	buf     []TraceRecord[K]
+	next    int
+	full    bool
+	fn      func(TraceRecord[K])
+	pending TraceOp
+}
+
+// WithTrace is a function which returns an option to record every insertion,
+// update, deletion and move of the map with its key and caller, for diagnosing
+// which code overwrote or removed a key.
+// The last n records are kept in a ring buffer and returned by DebugHistory.
+// If fn is not nil, it is called with each record, e.g. to write it to a
+// log/slog logger:
+//
+//	orderedmap.WithTrace(0, func(r orderedmap.TraceRecord[string]) {
+//		logger.Debug("orderedmap", "seq", r.Seq, "op", r.Op, "key", r.Key, "caller", r.Caller)
+//	})
+//
+// This captures a stack per operation, so it is for debugging only.
+// The type parameter must be same with the map's key type, otherwise New
+// panics.
+func WithTrace[K comparable](n int, fn func(rec TraceRecord[K])) Option {
+	return func(o *options) {
+		o.traceLen = n
+		o.traceFn = fn
+	}
+}
+
+// DebugHistory is a method which returns the records kept by the trace mode
+// in the order of their sequence numbers.
+// If this map is not created with WithTrace, this method returns nil.
+func (om *Map[K, V]) DebugHistory() []TraceRecord[K] {
+	if om == nil || om.ext == nil || om.ext.trace == nil {
+		return nil
+	}
+	t := om.ext.trace
+	if !t.full {
+		return append([]TraceRecord[K](nil), t.buf[:t.next]...)
+	}
+	recs := make([]TraceRecord[K], 0, len(t.buf))
+	recs = append(recs, t.buf[t.next:]...)
+	return append(recs, t.buf[:t.next]...)
+}
+
+// trace is a method which records an operation if the trace mode is enabled.
+// This must be called only when om.ext is not nil.
+func (om *Map[K, V]) trace(op TraceOp, key K) {
+	t := om.ext.trace
+	if t == nil {
+		return
+	}
+	t.seq++
+	rec := TraceRecord[K]{Seq: t.seq, Op: op, Key: key, Caller: traceCaller()}
+	if len(t.buf) > 0 {
+		t.buf[t.next] = rec
+		t.next++
+		if t.next == len(t.buf) {
+			t.next = 0
+			t.full = true
+		}
+	}
+	if t.fn != nil {
+		t.fn(rec)
+	}
+}
+
+// traceUnlinked is a method which records a removal of an entry. The
+// operation is an eviction if evictFront has marked it, otherwise a deletion
+// or a logical deletion.
+func (om *Map[K, V]) traceUnlinked(ent *Entry[K, V]) {
+	t := om.ext.trace
+	if t == nil {
+		return
+	}
+	op := TraceDelete
+	if t.pending != 0 {
+		op = t.pending
+		t.pending = 0
+	} else if ent.deleted {
+		op = TraceLdelete
+	}
+	om.trace(op, ent.key)
+}
+
+func traceCaller() string {
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var sb strings.Builder
+	depth := 0
+	for depth < traceDepth {
+		fr, more := frames.Next()
+		if !strings.HasPrefix(fr.Function, tracePkgPrefix) {
+			if depth > 0 {
+				sb.WriteString(" < ")
+			}
+			sb.WriteString(fr.Function)
+			sb.WriteString(" (")
+			sb.WriteString(filepath.Base(fr.File))
+			sb.WriteString(":")
+			sb.WriteString(strconv.Itoa(fr.Line))
+			sb.WriteString(")")
+			depth++
+		}
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
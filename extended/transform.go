@@ -0,0 +1,42 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+// Filter is a method which returns a new map having the entries of this map
+// for which pred returns true, in the order of this map.
+func (om *Map[K, V]) Filter(pred func(key K, value V) bool) *Map[K, V] {
+	filtered := &Map[K, V]{m: make(map[K](*Entry[K, V]))}
+	om.Range(func(key K, value V) bool {
+		if pred(key, value) {
+			filtered.Store(key, value)
+		}
+		return true
+	})
+	return filtered
+}
+
+// MapValues is a function which returns a new map having the keys of the
+// specified map in its order, and the values converted by f, which may be
+// of another type.
+func MapValues[K comparable, V any, V2 any](om *Map[K, V], f func(key K, value V) V2) *Map[K, V2] {
+	mapped := &Map[K, V2]{m: make(map[K](*Entry[K, V2]), om.Len())}
+	om.Range(func(key K, value V) bool {
+		mapped.Store(key, f(key, value))
+		return true
+	})
+	return mapped
+}
+
+// Reduce is a function which folds the entries of the specified map in its
+// order into a value, by calling f with the accumulated value, starting from
+// seed, and each key and value.
+func Reduce[K comparable, V any, A any](om *Map[K, V], seed A, f func(acc A, key K, value V) A) A {
+	acc := seed
+	om.Range(func(key K, value V) bool {
+		acc = f(acc, key, value)
+		return true
+	})
+	return acc
+}
@@ -0,0 +1,138 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"time"
+)
+
+// WithExpireFunc is a function which returns an option to set a callback
+// which is called with an entry removed from the map because its time to
+// live set by StoreWithTTL had passed.
+// The callback set by WithOnEvict is also called with the reason
+// EvictExpired before this callback.
+// The type parameters must be same with the map's, otherwise New panics.
+func WithExpireFunc[K comparable, V any](fn func(key K, value V)) Option {
+	return func(o *options) {
+		o.onExpire = fn
+	}
+}
+
+// ExpiresAt is a method which returns the time when this entry expires.
+// If this entry is not stored by StoreWithTTL, this method returns the zero
+// time.
+func (ent *Entry[K, V]) ExpiresAt() time.Time {
+	if ent.times == nil {
+		return time.Time{}
+	}
+	return ent.times.expires
+}
+
+// StoreWithTTL is a method which sets a value for a key like Store, and makes
+// the entry expire when ttl has passed. If ttl is zero or less, the entry
+// never expires.
+// The expiration time is kept by later updates of the value by Store and
+// other methods, and is reset by StoreWithTTL.
+//
+// Expired entries are removed lazily: Load, LoadCtx, TryLoad, LoadOrStore
+// and LoadOrStoreFunc remove an expired entry for the key, and PurgeExpired
+// removes all expired entries. Until then, expired entries are counted by Len
+// and visited by iterations. A SyncMap can also remove them in background by
+// StartJanitor.
+func (om *Map[K, V]) StoreWithTTL(key K, value V, ttl time.Duration) {
+	if om.limited() {
+		return
+	}
+	om.put(key, value)
+	ent, exists := om.m[key]
+	if !exists || ent.deleted {
+		return
+	}
+	if ttl <= 0 {
+		if ent.times != nil {
+			ent.times.expires = time.Time{}
+		}
+		return
+	}
+	if ent.times == nil {
+		ent.times = &entryTimes{}
+	}
+	ent.times.expires = time.Now().Add(ttl)
+}
+
+// PurgeExpired is a method which removes all expired entries, and returns the
+// number of removed entries.
+func (om *Map[K, V]) PurgeExpired() int {
+	if om == nil {
+		return 0
+	}
+	n, _ := om.purgeFrom(om.head, time.Now(), 0)
+	return n
+}
+
+// purgeFrom is a method which removes expired entries from the specified
+// entry toward the back, visiting at most max entries if max is positive.
+// This method returns the number of removed entries and the entry to resume
+// from, which is nil if the end of the entry list is reached.
+func (om *Map[K, V]) purgeFrom(
+	ent *Entry[K, V],
+	now time.Time,
+	max int,
+) (n int, resume *Entry[K, V]) {
+	for visited := 0; ent != nil; visited++ {
+		if max > 0 && visited == max {
+			return n, ent
+		}
+		next := ent.next
+		if ent.expiredAt(now) {
+			om.expire(ent)
+			n++
+		}
+		ent = next
+	}
+	return n, nil
+}
+
+// linked is a method which reports whether an entry is still in the entry
+// list of this map.
+func (om *Map[K, V]) linked(ent *Entry[K, V]) bool {
+	e, exists := om.m[ent.key]
+	return exists && e == ent && !ent.deleted
+}
+
+func (ent *Entry[K, V]) expiredAt(now time.Time) bool {
+	return ent.times != nil && !ent.times.expires.IsZero() && !now.Before(ent.times.expires)
+}
+
+// expired is a method which reports whether this entry has expired, reading
+// the clock only if this entry has an expiration time.
+func (ent *Entry[K, V]) expired() bool {
+	if ent.times == nil || ent.times.expires.IsZero() {
+		return false
+	}
+	return ent.expiredAt(time.Now())
+}
+
+// expireIfDue is a method which removes an entry if it has expired, and
+// reports whether it is removed.
+func (om *Map[K, V]) expireIfDue(ent *Entry[K, V]) bool {
+	if !ent.expired() {
+		return false
+	}
+	om.expire(ent)
+	return true
+}
+
+func (om *Map[K, V]) expire(ent *Entry[K, V]) {
+	if om.ext == nil {
+		delete(om.m, ent.key)
+		om.unlink(ent)
+		return
+	}
+	om.evict(ent, EvictExpired)
+	if om.ext.onExpire != nil {
+		om.ext.onExpire(ent.key, ent.value)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"strings"
+)
+
+// KeySeparator is a string which separates elements of Key2 and Key3 in their
+// text forms, e.g. JSON object keys. Elements must not contain this separator
+// to be read back correctly.
+var KeySeparator = "|"
+
+// Key2 is a struct which is a comparable tuple of two values, to be used as
+// a composite key of a Map without flattening the values into a string.
+// The hash of this key is computed by the Go runtime from its fields.
+type Key2[A, B comparable] struct {
+	A A
+	B B
+}
+
+// Key3 is a struct which is a comparable tuple of three values, to be used as
+// a composite key of a Map without flattening the values into a string.
+// The hash of this key is computed by the Go runtime from its fields.
+type Key3[A, B, C comparable] struct {
+	A A
+	B B
+	C C
+}
+
+// MakeKey2 is a function which creates a Key2 from two values.
+func MakeKey2[A, B comparable](a A, b B) Key2[A, B] {
+	return Key2[A, B]{A: a, B: b}
+}
+
+// MakeKey3 is a function which creates a Key3 from three values.
+func MakeKey3[A, B, C comparable](a A, b B, c C) Key3[A, B, C] {
+	return Key3[A, B, C]{A: a, B: b, C: c}
+}
+
+// MarshalText is a method which returns the text form of this key, which is
+// the text forms of the elements joined with KeySeparator, e.g. "a|1".
+func (k Key2[A, B]) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	err := addKeyText(&buf, k.A)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString(KeySeparator)
+	err = addKeyText(&buf, k.B)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText is a method which sets this key from the text form which
+// MarshalText returns.
+func (k *Key2[A, B]) UnmarshalText(text []byte) error {
+	parts := strings.SplitN(string(text), KeySeparator, 2)
+	if len(parts) != 2 {
+		return SyntaxError{msg: "Invalid Key2 key '" + string(text) + "'"}
+	}
+	a, err := parseKey[A](parts[0], 0)
+	if err != nil {
+		return err
+	}
+	b, err := parseKey[B](parts[1], 0)
+	if err != nil {
+		return err
+	}
+	k.A, k.B = a, b
+	return nil
+}
+
+// MarshalText is a method which returns the text form of this key, which is
+// the text forms of the elements joined with KeySeparator, e.g. "a|1|true".
+func (k Key3[A, B, C]) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	err := addKeyText(&buf, k.A)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString(KeySeparator)
+	err = addKeyText(&buf, k.B)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString(KeySeparator)
+	err = addKeyText(&buf, k.C)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText is a method which sets this key from the text form which
+// MarshalText returns.
+func (k *Key3[A, B, C]) UnmarshalText(text []byte) error {
+	parts := strings.SplitN(string(text), KeySeparator, 3)
+	if len(parts) != 3 {
+		return SyntaxError{msg: "Invalid Key3 key '" + string(text) + "'"}
+	}
+	a, err := parseKey[A](parts[0], 0)
+	if err != nil {
+		return err
+	}
+	b, err := parseKey[B](parts[1], 0)
+	if err != nil {
+		return err
+	}
+	c, err := parseKey[C](parts[2], 0)
+	if err != nil {
+		return err
+	}
+	k.A, k.B, k.C = a, b, c
+	return nil
+}
@@ -0,0 +1,215 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// View is a struct which is a read-only live view of an ordered map, which
+// shows only entries matching a predicate.
+// A View does not copy entries, so changes of the underlying map are visible
+// through it immediately.
+type View[K comparable, V any] struct {
+	om   *Map[K, V]
+	pred func(key K, value V) bool
+}
+
+// View is a method which returns a read-only live view of this map which
+// shows only entries for which pred returns true.
+func (om *Map[K, V]) View(pred func(key K, value V) bool) View[K, V] {
+	return View[K, V]{om: om, pred: pred}
+}
+
+// Len is a method which returns the number of entries matching the predicate.
+// This method iterates all entries of the underlying map.
+func (vw View[K, V]) Len() int {
+	n := 0
+	vw.Range(func(K, V) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Load is a method which returns a value for a key if the entry is present
+// and matches the predicate. This does not change the underlying map: it
+// does not call a loader set by WithLoader, nor move the entry of a map in
+// access order, and an expired entry is treated as missing.
+func (vw View[K, V]) Load(key K) (value V, ok bool) {
+	v, found := vw.om.peek(key)
+	if found && vw.pred(key, v) {
+		value = v
+		ok = true
+	}
+	return
+}
+
+// Range is a method which calls the specified function: fn sequentially for
+// each key and value matching the predicate, in the order of the underlying
+// map. If fn returns false, this method stops the iteration.
+func (vw View[K, V]) Range(fn func(key K, value V) bool) {
+	vw.om.Range(func(key K, value V) bool {
+		if !vw.pred(key, value) {
+			return true
+		}
+		return fn(key, value)
+	})
+}
+
+// String is a method which returns a string of the content of this view.
+func (vw View[K, V]) String() string {
+	var buf strings.Builder
+	buf.WriteString("View[")
+	sep := ""
+	vw.Range(func(key K, value V) bool {
+		buf.WriteString(fmt.Sprintf("%s%v:%v", sep, key, value))
+		sep = " "
+		return true
+	})
+	buf.WriteString("]")
+	return buf.String()
+}
+
+// MarshalJSON is a method which returns the JSON of the entries matching the
+// predicate, in the same form as MarshalJSON of Map. The field options of the
+// underlying map are applied.
+// A View has no UnmarshalJSON because it is read-only; the JSON is decoded
+// into a Map.
+func (vw View[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := vw.om.encodeJSONIf(&buf, nil, vw.pred); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobEncode is a method which encodes the entries matching the predicate for
+// encoding/gob, in the same form as GobEncode of Map, so the data is decoded
+// into a Map.
+func (vw View[K, V]) GobEncode() ([]byte, error) {
+	return vw.om.gobEncodeIf(vw.pred)
+}
+
+// ChainView is a struct which is a read-only live view over multiple ordered
+// maps, like layered scopes. Earlier maps shadow later ones.
+type ChainView[K comparable, V any] struct {
+	maps []*Map[K, V]
+}
+
+// Chain is a function which returns a read-only live view over the specified
+// maps. Load resolves a key through the maps in order, and Range iterates the
+// maps one after another, skipping keys already shown by an earlier map.
+func Chain[K comparable, V any](maps ...*Map[K, V]) ChainView[K, V] {
+	return ChainView[K, V]{maps: maps}
+}
+
+// Len is a method which returns the number of distinct keys in the chained
+// maps.
+func (cv ChainView[K, V]) Len() int {
+	n := 0
+	cv.Range(func(K, V) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Load is a method which returns a value for a key from the first map which
+// has the key. Like all methods of a view, this does not change the maps: it
+// does not call loaders set by WithLoader, nor move entries of maps in access
+// order, and expired entries are treated as missing.
+func (cv ChainView[K, V]) Load(key K) (value V, ok bool) {
+	for _, om := range cv.maps {
+		value, ok = om.peek(key)
+		if ok {
+			return
+		}
+	}
+	return
+}
+
+// Range is a method which calls the specified function: fn sequentially for
+// each distinct key and its resolved value, iterating the chained maps in
+// order. Expired entries are skipped like Load treats them as missing.
+// If fn returns false, this method stops the iteration.
+func (cv ChainView[K, V]) Range(fn func(key K, value V) bool) {
+	now := time.Now()
+	for i, om := range cv.maps {
+		for ent := om.Front(); ent != nil; ent = ent.Next() {
+			if ent.expiredAt(now) || cv.shadowed(i, ent.key) {
+				continue
+			}
+			if !fn(ent.key, ent.value) {
+				return
+			}
+		}
+	}
+}
+
+func (cv ChainView[K, V]) shadowed(i int, key K) bool {
+	for _, om := range cv.maps[:i] {
+		if _, ok := om.peek(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// String is a method which returns a string of the content of this view.
+func (cv ChainView[K, V]) String() string {
+	var buf strings.Builder
+	buf.WriteString("ChainView[")
+	sep := ""
+	cv.Range(func(key K, value V) bool {
+		buf.WriteString(fmt.Sprintf("%s%v:%v", sep, key, value))
+		sep = " "
+		return true
+	})
+	buf.WriteString("]")
+	return buf.String()
+}
+
+// MarshalJSON is a method which returns the JSON of the distinct keys and
+// their resolved values, in the same form as MarshalJSON of Map. The field
+// options of the chained maps are not applied.
+// A ChainView has no UnmarshalJSON because it is read-only; the JSON is
+// decoded into a Map.
+func (cv ChainView[K, V]) MarshalJSON() ([]byte, error) {
+	om := cv.flatten()
+	return om.MarshalJSON()
+}
+
+// GobEncode is a method which encodes the distinct keys and their resolved
+// values for encoding/gob, in the same form as GobEncode of Map, so the data
+// is decoded into a Map.
+func (cv ChainView[K, V]) GobEncode() ([]byte, error) {
+	om := cv.flatten()
+	return om.GobEncode()
+}
+
+// flatten is a method which copies the distinct keys and their resolved
+// values, except expired ones, into a new map.
+func (cv ChainView[K, V]) flatten() Map[K, V] {
+	om := New[K, V]()
+	cv.Range(func(key K, value V) bool {
+		om.Store(key, value)
+		return true
+	})
+	return om
+}
+
+// peek is a method which returns the value for a key without any side
+// effect, unlike Load: loaders are not called, entries are not moved in
+// access order, and expired entries are not removed but treated as missing.
+func (om *Map[K, V]) peek(key K) (value V, ok bool) {
+	if om == nil {
+		return
+	}
+	return om.m[key].valueOf()
+}
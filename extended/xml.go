@@ -0,0 +1,134 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// XMLNameError is an error type which is returned by MarshalXML when the text
+// of a key is not a valid XML element name.
+type XMLNameError struct {
+	Name string
+}
+
+func (err XMLNameError) Error() string {
+	return fmt.Sprintf("orderedmap: %q is not a valid XML element name", err.Name)
+}
+
+// MarshalXML is a method which encodes this map as an XML element whose child
+// elements are the entries in the order of key insertions, for encoding/xml.
+// The name of a child element is the text of a key in the same form as
+// MarshalJSON, and its content is the value encoded by encoding/xml.
+// If the name of the start element is not a valid XML name, as the default
+// name derived from this generic type, "map" is used instead.
+func (om Map[K, V]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if !isXMLName(start.Name.Local) {
+		start.Name.Local = "map"
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for ent := om.Front(); ent != nil; ent = ent.Next() {
+		buf.Reset()
+		if err := addKeyText(&buf, ent.key); err != nil {
+			return err
+		}
+		name := buf.String()
+		if !isXMLName(name) {
+			return XMLNameError{Name: name}
+		}
+		child := xml.StartElement{Name: xml.Name{Local: name}}
+		if err := e.EncodeElement(ent.value, child); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML is a method which sets the content of this map from the child
+// elements of an XML element in the order of the elements, for encoding/xml.
+// If the value type is any, a child element which has child elements is
+// decoded into *Map[string, any] and other one is decoded into its text.
+func (om *Map[K, V]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			key, err := parseKey[K](t.Name.Local, d.InputOffset())
+			if err != nil {
+				return err
+			}
+			var val V
+			if p, ok := any(&val).(*any); ok {
+				*p, err = decodeOrderedXML(d)
+			} else {
+				err = d.DecodeElement(&val, &t)
+			}
+			if err != nil {
+				return err
+			}
+			om.put(key, val)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// decodeOrderedXML is a function which decodes the rest of the current
+// element into a *Map[string, any] if it has child elements, otherwise into
+// its text.
+func decodeOrderedXML(d *xml.Decoder) (any, error) {
+	var text strings.Builder
+	var om *Map[string, any]
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			if om == nil {
+				m := New[string, any]()
+				om = &m
+			}
+			v, err := decodeOrderedXML(d)
+			if err != nil {
+				return nil, err
+			}
+			om.Store(t.Name.Local, v)
+		case xml.EndElement:
+			if om != nil {
+				return om, nil
+			}
+			return text.String(), nil
+		}
+	}
+}
+
+func isXMLName(s string) bool {
+	if s == "" || strings.HasPrefix(strings.ToLower(s), "xml") {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case i > 0 && (r == '-' || r == '.' || unicode.IsDigit(r)):
+		default:
+			return false
+		}
+	}
+	return true
+}
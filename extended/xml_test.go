@@ -0,0 +1,73 @@
+package extended_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+func TestMap_MarshalXML(t *testing.T) {
+	type Request struct {
+		XMLName xml.Name                      `xml:"request"`
+		Fields  orderedmap.Map[string, Foo]   `xml:"fields"`
+		Params  orderedmap.Map[string, int64] `xml:"params"`
+	}
+	req := Request{
+		Fields: orderedmap.New[string, Foo](),
+		Params: orderedmap.New[string, int64](),
+	}
+	req.Fields.Store("zeta", Foo{Bar: "a", Baz: 1})
+	req.Fields.Store("alpha", Foo{Bar: "b", Baz: 2})
+	req.Params.Store("limit", 10)
+	req.Params.Store("cursor", 3)
+
+	bs, err := xml.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<request><fields><zeta><Bar>a</Bar><Baz>1</Baz></zeta>` +
+		`<alpha><Bar>b</Bar><Baz>2</Baz></alpha></fields>` +
+		`<params><limit>10</limit><cursor>3</cursor></params></request>`
+	if string(bs) != want {
+		t.Errorf("xml.Marshal = %s", bs)
+	}
+
+	got := Request{}
+	if err := xml.Unmarshal(bs, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Fields.String() != req.Fields.String() || got.Params.String() != req.Params.String() {
+		t.Errorf("xml.Unmarshal = %v %v", got.Fields, got.Params)
+	}
+
+	om := orderedmap.New[int, string]()
+	om.Store(1, "a")
+	if _, err := xml.Marshal(om); !errors.As(err, &orderedmap.XMLNameError{}) {
+		t.Errorf("xml.Marshal with an int key: err = %v", err)
+	}
+}
+
+func TestMap_UnmarshalXML(t *testing.T) {
+	data := `<root><b><y>1</y><x>2</x></b><a>s</a></root>`
+	om := orderedmap.New[string, any]()
+	if err := xml.Unmarshal([]byte(data), &om); err != nil {
+		t.Fatal(err)
+	}
+	if om.String() != "Map[b:Map[y:1 x:2] a:s]" {
+		t.Errorf("om = %v", om)
+	}
+	b, _ := om.Load("b")
+	if _, ok := b.(*orderedmap.Map[string, any]); !ok {
+		t.Errorf("nested element = %T", b)
+	}
+
+	bs, err := xml.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != `<map><b><y>1</y><x>2</x></b><a>s</a></map>` {
+		t.Errorf("xml.Marshal = %s", bs)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package extended
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML is a method which returns a YAML mapping node which has the
+// entries of this map in the order of key insertions, for gopkg.in/yaml.v3.
+// Keys of string, bool and numeric types are encoded as YAML scalars of those
+// types, and keys of other types are encoded as strings in the same text
+// forms as MarshalJSON.
+func (om Map[K, V]) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for ent := om.Front(); ent != nil; ent = ent.Next() {
+		kn, err := yamlKeyNode(ent.Key())
+		if err != nil {
+			return nil, err
+		}
+		vn := &yaml.Node{}
+		if err := vn.Encode(ent.Value()); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, kn, vn)
+	}
+	return node, nil
+}
+
+// UnmarshalYAML is a method which sets the content of this map from a YAML
+// mapping node in the order of the node, for gopkg.in/yaml.v3.
+// If the map is created with WithNestedOrder and its value type is any,
+// nested mappings are decoded into *Map[string, any].
+func (om *Map[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("orderedmap: line %d: cannot unmarshal %s into a map", node.Line, node.Tag)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, err := yamlKey[K](node.Content[i])
+		if err != nil {
+			return err
+		}
+		var val V
+		if p, ok := any(&val).(*any); ok && om.ext != nil && om.ext.nestedOrder {
+			*p, err = decodeOrderedYAML(node.Content[i+1])
+		} else {
+			err = node.Content[i+1].Decode(&val)
+		}
+		if err != nil {
+			return err
+		}
+		om.put(key, val)
+	}
+	return nil
+}
+
+func yamlKeyNode(key any) (*yaml.Node, error) {
+	kn := &yaml.Node{}
+	switch key.(type) {
+	case string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		err := kn.Encode(key)
+		return kn, err
+	}
+	var buf bytes.Buffer
+	if err := addKeyText(&buf, key); err != nil {
+		return nil, err
+	}
+	kn.SetString(buf.String())
+	return kn, nil
+}
+
+func yamlKey[K comparable](kn *yaml.Node) (key K, err error) {
+	switch any(key).(type) {
+	case string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		err = kn.Decode(&key)
+		return
+	}
+	return parseKey[K](kn.Value, 0)
+}
+
+// decodeOrderedYAML is a function which decodes a YAML node into an any,
+// except that mappings are decoded into *Map[string, any] at every depth.
+func decodeOrderedYAML(node *yaml.Node) (any, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		om := New[string, any](WithNestedOrder())
+		if err := om.UnmarshalYAML(node); err != nil {
+			return nil, err
+		}
+		return &om, nil
+	case yaml.SequenceNode:
+		arr := make([]any, 0, len(node.Content))
+		for _, n := range node.Content {
+			v, err := decodeOrderedYAML(n)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	default:
+		var v any
+		err := node.Decode(&v)
+		return v, err
+	}
+}
@@ -0,0 +1,84 @@
+package extended_test
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	orderedmap "github.com/sttk/benchmarks_orderedmap/extended"
+)
+
+func TestMap_MarshalYAML(t *testing.T) {
+	om := orderedmap.New[string, any]()
+	om.Store("z", 1)
+	om.Store("a", "x")
+	om.Store("m", []int{1, 2})
+
+	bs, err := yaml.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "z: 1\na: x\nm:\n    - 1\n    - 2\n"
+	if string(bs) != want {
+		t.Errorf("yaml.Marshal = %q", bs)
+	}
+
+	om2 := orderedmap.New[int, string]()
+	om2.Store(3, "c")
+	om2.Store(1, "a")
+	bs, err = yaml.Marshal(om2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != "3: c\n1: a\n" {
+		t.Errorf("yaml.Marshal = %q", bs)
+	}
+}
+
+func TestMap_UnmarshalYAML(t *testing.T) {
+	data := "z: 1\na:\n  y: true\n  x: [1, 2]\nc: s\n"
+
+	om := orderedmap.New[string, any](orderedmap.WithNestedOrder())
+	if err := yaml.Unmarshal([]byte(data), &om); err != nil {
+		t.Fatal(err)
+	}
+	keys := ""
+	for ent := om.Front(); ent != nil; ent = ent.Next() {
+		keys += ent.Key()
+	}
+	if keys != "zac" {
+		t.Errorf("keys = %s", keys)
+	}
+	a, _ := om.Load("a")
+	nested, ok := a.(*orderedmap.Map[string, any])
+	if !ok {
+		t.Fatalf("nested mapping = %T", a)
+	}
+	if y, _ := nested.Load("y"); y != true {
+		t.Errorf("y = %v", y)
+	}
+
+	bs, err := yaml.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	om2 := orderedmap.New[string, any](orderedmap.WithNestedOrder())
+	if err := yaml.Unmarshal(bs, &om2); err != nil {
+		t.Fatal(err)
+	}
+	if om.String() != om2.String() {
+		t.Errorf("round trip: %v != %v", om2, om)
+	}
+
+	om3 := orderedmap.New[int, string]()
+	if err := yaml.Unmarshal([]byte("3: c\n1: a\n"), &om3); err != nil {
+		t.Fatal(err)
+	}
+	if om3.String() != "Map[3:c 1:a]" {
+		t.Errorf("om3 = %v", om3)
+	}
+
+	if err := yaml.Unmarshal([]byte("- 1\n- 2\n"), &om3); err == nil {
+		t.Error("expected an error for a sequence")
+	}
+}
@@ -0,0 +1,230 @@
+package stringint64_test
+
+import (
+	"testing"
+
+	"github.com/sttk/benchmarks_orderedmap/specialized/stringint64"
+	orderedmap "github.com/sttk/benchmarks_orderedmap/v1_0_0"
+)
+
+func BenchmarkSpecialized_Store_newFiveEntries(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		om := stringint64.New()
+		om.Store("foo-0", 0)
+		om.Store("foo-1", 11)
+		om.Store("foo-2", 22)
+		om.Store("foo-3", 33)
+		om.Store("foo-4", 44)
+	}
+}
+
+func BenchmarkGeneric_Store_newFiveEntries(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		om := orderedmap.New[string, int64]()
+		om.Store("foo-0", 0)
+		om.Store("foo-1", 11)
+		om.Store("foo-2", 22)
+		om.Store("foo-3", 33)
+		om.Store("foo-4", 44)
+	}
+}
+
+func BenchmarkSpecialized_Store_rewriteFiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := stringint64.New()
+	om.Store("foo-0", 0)
+	om.Store("foo-1", 11)
+	om.Store("foo-2", 22)
+	om.Store("foo-3", 33)
+	om.Store("foo-4", 44)
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Store("foo-0", 0)
+		om.Store("foo-1", 11)
+		om.Store("foo-2", 22)
+		om.Store("foo-3", 33)
+		om.Store("foo-4", 44)
+	}
+}
+
+func BenchmarkGeneric_Store_rewriteFiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[string, int64]()
+	om.Store("foo-0", 0)
+	om.Store("foo-1", 11)
+	om.Store("foo-2", 22)
+	om.Store("foo-3", 33)
+	om.Store("foo-4", 44)
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Store("foo-0", 0)
+		om.Store("foo-1", 11)
+		om.Store("foo-2", 22)
+		om.Store("foo-3", 33)
+		om.Store("foo-4", 44)
+	}
+}
+
+func BenchmarkSpecialized_Load_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := stringint64.New()
+	om.Store("foo-0", 0)
+	om.Store("foo-1", 11)
+	om.Store("foo-2", 22)
+	om.Store("foo-3", 33)
+	om.Store("foo-4", 44)
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		v0, exists := om.Load("foo-0")
+		v1, exists := om.Load("foo-1")
+		v2, exists := om.Load("foo-2")
+		v3, exists := om.Load("foo-3")
+		v4, exists := om.Load("foo-4")
+		_ = v0
+		_ = v1
+		_ = v2
+		_ = v3
+		_ = v4
+		_ = exists
+	}
+}
+
+func BenchmarkGeneric_Load_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[string, int64]()
+	om.Store("foo-0", 0)
+	om.Store("foo-1", 11)
+	om.Store("foo-2", 22)
+	om.Store("foo-3", 33)
+	om.Store("foo-4", 44)
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		v0, exists := om.Load("foo-0")
+		v1, exists := om.Load("foo-1")
+		v2, exists := om.Load("foo-2")
+		v3, exists := om.Load("foo-3")
+		v4, exists := om.Load("foo-4")
+		_ = v0
+		_ = v1
+		_ = v2
+		_ = v3
+		_ = v4
+		_ = exists
+	}
+}
+
+func BenchmarkSpecialized_Delete_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := stringint64.New()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Store("foo-0", 0)
+		om.Store("foo-1", 11)
+		om.Store("foo-2", 22)
+		om.Store("foo-3", 33)
+		om.Store("foo-4", 44)
+		om.Delete("foo-0")
+		om.Delete("foo-1")
+		om.Delete("foo-2")
+		om.Delete("foo-3")
+		om.Delete("foo-4")
+	}
+}
+
+func BenchmarkGeneric_Delete_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[string, int64]()
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Store("foo-0", 0)
+		om.Store("foo-1", 11)
+		om.Store("foo-2", 22)
+		om.Store("foo-3", 33)
+		om.Store("foo-4", 44)
+		om.Delete("foo-0")
+		om.Delete("foo-1")
+		om.Delete("foo-2")
+		om.Delete("foo-3")
+		om.Delete("foo-4")
+	}
+}
+
+func BenchmarkSpecialized_IterateWithRange_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := stringint64.New()
+	om.Store("foo-0", 0)
+	om.Store("foo-1", 11)
+	om.Store("foo-2", 22)
+	om.Store("foo-3", 33)
+	om.Store("foo-4", 44)
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Range(func(k string, v int64) bool {
+			_ = k
+			_ = v
+			return true
+		})
+	}
+}
+
+func BenchmarkGeneric_IterateWithRange_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[string, int64]()
+	om.Store("foo-0", 0)
+	om.Store("foo-1", 11)
+	om.Store("foo-2", 22)
+	om.Store("foo-3", 33)
+	om.Store("foo-4", 44)
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		om.Range(func(k string, v int64) bool {
+			_ = k
+			_ = v
+			return true
+		})
+	}
+}
+
+func BenchmarkSpecialized_IterateWithFront_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := stringint64.New()
+	om.Store("foo-0", 0)
+	om.Store("foo-1", 11)
+	om.Store("foo-2", 22)
+	om.Store("foo-3", 33)
+	om.Store("foo-4", 44)
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		for ent := om.Front(); ent != nil; ent = ent.Next() {
+			_ = ent.Key()
+			_ = ent.Value()
+		}
+	}
+}
+
+func BenchmarkGeneric_IterateWithFront_fiveEntries(b *testing.B) {
+	b.StopTimer()
+	om := orderedmap.New[string, int64]()
+	om.Store("foo-0", 0)
+	om.Store("foo-1", 11)
+	om.Store("foo-2", 22)
+	om.Store("foo-3", 33)
+	om.Store("foo-4", 44)
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		for ent := om.Front(); ent != nil; ent = ent.Next() {
+			_ = ent.Key()
+			_ = ent.Value()
+		}
+	}
+}
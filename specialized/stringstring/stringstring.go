@@ -0,0 +1,171 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+// Package stringstring provides Map type which is an ordered map hand-specialized for
+// string keys and string values.
+//
+// This package has the same algorithm as the generic Map of v1_0_0 without
+// type parameters, to measure whether GC shape stenciling of the generic
+// implementation costs performance.
+package stringstring
+
+// Map is a struct which represents a map which preserves the order of key
+// insertions, with string keys and string values.
+type Map struct {
+	m    map[string](*Entry)
+	head *Entry
+	last *Entry
+	len  int
+}
+
+// Entry is a struct which is a map element and holds a pair of key and value.
+type Entry struct {
+	key     string
+	value   string
+	prev    *Entry
+	next    *Entry
+	deleted bool
+}
+
+// New is a function which creates a new ordered map, which is empty.
+func New() Map {
+	return Map{m: make(map[string](*Entry))}
+}
+
+// Len is a method which returns the number of entries in this map.
+func (om *Map) Len() int {
+	return om.len
+}
+
+// Store is a method which sets a value for a key.
+func (om *Map) Store(key string, value string) {
+	ent, exists := om.m[key]
+	if exists {
+		if !ent.deleted {
+			ent.value = value
+			return
+		}
+		ent.value = value
+		ent.deleted = false
+	} else {
+		ent = &Entry{key: key, value: value}
+	}
+
+	om.m[key] = ent
+	om.linkLast(ent)
+}
+
+// Load is a method which returns a value stored in this map for a key.
+// If no value was found for a key, the ok result is false.
+func (om *Map) Load(key string) (value string, ok bool) {
+	ent, exists := om.m[key]
+	if exists {
+		if !ent.deleted {
+			value = ent.value
+			ok = true
+		}
+	}
+	return
+}
+
+// Delete is a method which deletes a value for a key.
+func (om *Map) Delete(key string) {
+	ent, exists := om.m[key]
+	if !exists {
+		return
+	}
+
+	delete(om.m, key)
+
+	if ent.deleted {
+		return
+	}
+	om.unlink(ent)
+}
+
+// Ldelete is a method which logically deletes a value for a key.
+func (om *Map) Ldelete(key string) {
+	ent, exists := om.m[key]
+	if !exists {
+		return
+	}
+
+	if ent.deleted {
+		return
+	}
+	ent.deleted = true
+	om.unlink(ent)
+}
+
+func (om *Map) linkLast(ent *Entry) {
+	if om.last == nil {
+		om.head = ent
+	} else {
+		ent.prev = om.last
+		om.last.next = ent
+	}
+	om.last = ent
+	om.len++
+}
+
+func (om *Map) unlink(ent *Entry) {
+	if ent.prev != nil {
+		ent.prev.next = ent.next
+	} else {
+		om.head = ent.next
+	}
+
+	if ent.next != nil {
+		ent.next.prev = ent.prev
+	} else {
+		om.last = ent.prev
+	}
+
+	ent.next = nil
+	ent.prev = nil
+	om.len--
+}
+
+// Range is a method which calls the specified function: fn sequentially for
+// each key and value in this map.
+// If fn returns false, this method stops the iteration.
+func (om *Map) Range(fn func(key string, value string) bool) {
+	for entry := om.head; entry != nil; entry = entry.next {
+		if !fn(entry.key, entry.value) {
+			break
+		}
+	}
+}
+
+// Front is a method which returns the head entry of this map.
+func (om *Map) Front() *Entry {
+	return om.head
+}
+
+// Back is a method which returns the last entry of this map.
+func (om *Map) Back() *Entry {
+	return om.last
+}
+
+// Prev is a method which returns the previous entry of this entry.
+// If this entry is a head entry of an ordered map, the returned value is nil.
+func (ent *Entry) Prev() *Entry {
+	return ent.prev
+}
+
+// Next is a method which returns the next entry of this entry.
+// If this entry is a last entry of an ordered map, the returned value is nil.
+func (ent *Entry) Next() *Entry {
+	return ent.next
+}
+
+// Key is a method which returns the key of this entry.
+func (ent *Entry) Key() string {
+	return ent.key
+}
+
+// Value is a method which returns the value of this entry.
+func (ent *Entry) Value() string {
+	return ent.value
+}